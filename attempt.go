@@ -0,0 +1,25 @@
+package retrier
+
+import "context"
+
+// AttemptHook derives the context of one attempt (1-based) from ctx, for
+// example to attach an attempt-specific request ID, deadline, or target that
+// fn and downstream clients observe. cleanup, if not nil, runs once the
+// attempt returns.
+type AttemptHook func(ctx context.Context, attempt int) (attemptCtx context.Context, cleanup func())
+
+// WithAttemptContext adds hook to the hooks deriving the context of each
+// attempt under RetryCtx. Hooks run in the order they were added, each
+// deriving from the context of the previous one, and their cleanups run in
+// reverse order. Default is none.
+//
+// Example:
+//
+//	retrier.WithAttemptContext(func(ctx context.Context, attempt int) (context.Context, func()) {
+//	    return context.WithTimeout(ctx, 2*time.Second)
+//	})
+func WithAttemptContext(hook AttemptHook) RetryOption {
+	return func(c *retryConfig) {
+		c.attemptHooks = append(c.attemptHooks, hook)
+	}
+}
@@ -0,0 +1,232 @@
+package retrier
+
+import (
+	"errors"
+	"time"
+)
+
+// BackoffStrategy computes the delay before each retry, replacing the built-in
+// exponential backoff (see WithBackoff).
+type BackoffStrategy interface {
+	// NextDelay returns the delay before retry number retry (1-based), following
+	// the failed attempt's err. Returning false stops retrying.
+	NextDelay(retry int, err error) (time.Duration, bool)
+}
+
+// WithBackoff replaces the exponential backoff with delays computed by a
+// BackoffStrategy. newStrategy is called at the start of every Retry call, so
+// the strategy may keep state across the attempts of one call.
+//
+// Jitter and instance offsets are still added to the strategy's delays, and a
+// server-suggested delay still acts as a minimum. When the strategy stops,
+// Retry returns ErrBackoffStopped.
+// WithInitialDuration, WithMultiplier, and WithMaxDuration have no effect.
+func WithBackoff(newStrategy func() BackoffStrategy) RetryOption {
+	return func(c *retryConfig) {
+		c.newBackoff = newStrategy
+	}
+}
+
+// ConstantBackoff makes every retry wait d, whatever WithInitialDuration,
+// WithMultiplier, and WithMaxDuration say. It is WithBackoff with a strategy
+// that never stops, so jitter, severity weights, and server-suggested delays
+// still apply, and WithMaxAttempts still bounds the attempts.
+//
+// Example:
+//
+//	result := retrier.Retry(ctx, logger, fn,
+//	    retrier.WithMaxAttempts(5),
+//	    retrier.ConstantBackoff(2*time.Second),
+//	    retrier.WithJitter(200*time.Millisecond),
+//	)
+func ConstantBackoff(d time.Duration) RetryOption {
+	return WithBackoff(func() BackoffStrategy {
+		return constantBackoff(d)
+	})
+}
+
+// constantBackoff is the BackoffStrategy of ConstantBackoff.
+type constantBackoff time.Duration
+
+// NextDelay returns the constant delay.
+func (b constantBackoff) NextDelay(int, error) (time.Duration, bool) {
+	return max(time.Duration(b), 0), true
+}
+
+// WithLinearBackoff makes the backoff curve grow linearly instead of
+// exponentially: each retry waits increment longer than the previous one,
+// starting from the initial duration, so retry n waits
+// initial + (n-1)*increment, capped by WithMaxDuration. WithMultiplier has no
+// effect. Jitter, severity weights, and server-suggested delays apply as on
+// the exponential curve. Default is exponential.
+//
+// Example:
+//
+//	// 500ms, 1s, 1.5s, 2s...
+//	result := retrier.Retry(ctx, logger, fn,
+//	    retrier.WithInitialDuration(500*time.Millisecond),
+//	    retrier.WithLinearBackoff(500*time.Millisecond),
+//	)
+func WithLinearBackoff(increment time.Duration) RetryOption {
+	return func(c *retryConfig) {
+		c.linearIncrement = increment
+	}
+}
+
+// linearDelay computes the delay before retry number retry on the linear
+// curve, scaled by weight, with serverDelay as a minimum. raw is the delay
+// before the cap of WithMaxDuration and jitter.
+func (c *retryConfig) linearDelay(retry int, serverDelay time.Duration, weight float64) (delay, raw time.Duration) {
+	step := addDurations(min(c.initialDuration, c.maxDuration), scaleDuration(c.linearIncrement, float64(retry-1)))
+	raw = max(scaleDuration(step, weight), serverDelay)
+	return min(raw, c.maxDuration), raw
+}
+
+// WithMaxTotalBackoff caps the sum of the backoff delays of one call at d,
+// bounding the latency added purely by waiting, whatever the time the
+// attempts take; bound the context for a limit including them. A delay that
+// would exceed what is left of d is shortened to it; once d is spent,
+// Retry stops with ErrTotalBackoffExceeded, wrapping the last error. Delays
+// are measured with the configured Clock, so delays cut short by a
+// WakeSignal count for the time actually waited, and WithHealthCheck stops
+// extending a delay once d is spent. Default is none.
+//
+// Example:
+//
+//	// At most 2s of added delay, however many attempts fit in it
+//	result := retrier.Retry(ctx, logger, fn,
+//	    retrier.WithMaxAttempts(10),
+//	    retrier.WithMaxTotalBackoff(2*time.Second),
+//	)
+func WithMaxTotalBackoff(d time.Duration) RetryOption {
+	return func(c *retryConfig) {
+		c.maxTotalBackoff = d
+	}
+}
+
+// WithFastRetries makes the first n retries wait delay, a short fixed delay,
+// before the backoff curve starts: retry n+1 waits the initial duration of
+// the curve (or the first delay of a WithBackoff strategy), and so on. Quick
+// retries ride out very short transients such as connection handoffs, while
+// sustained failures still back off.
+//
+// Fast retries are not jittered, but a server-suggested delay still acts as
+// a minimum. The offset of WithInstanceKey shifts the first retry after them.
+// Default is none.
+//
+// Example:
+//
+//	result := retrier.Retry(ctx, logger, fn,
+//	    retrier.WithMaxAttempts(8),
+//	    retrier.WithFastRetries(2, 10*time.Millisecond), // 10ms, 10ms, then 1s, 2s, 4s...
+//	)
+func WithFastRetries(n int, delay time.Duration) RetryOption {
+	return func(c *retryConfig) {
+		c.fastRetries = n
+		c.fastDelay = delay
+	}
+}
+
+// WithSeverity weighs how hard to back off after each failed attempt: the
+// delay the backoff computes before the next retry is multiplied by
+// weigh(err), so that overload signals lengthen it and connection blips
+// shorten it, where a retry decision alone would treat them alike. A weight
+// of 0 or less falls back to the SeverityWeighter of the error chain, if
+// any, and otherwise to 1.
+//
+// The weight scales the delay of the exponential curve (or of a WithBackoff
+// strategy) before WithMaxDuration caps it and jitter is added, and is
+// reflected in RetryEvent.RawBackoff. Fast retries (see WithFastRetries) are
+// not scaled, and a server-suggested delay is still a minimum. Default is
+// none.
+//
+// Example:
+//
+//	result := retrier.Retry(ctx, logger, fn,
+//	    retrier.WithSeverity(func(err error) float64 {
+//	        switch {
+//	        case errors.Is(err, errOverloaded):
+//	            return 2
+//	        case errors.Is(err, syscall.ECONNRESET):
+//	            return 0.5
+//	        }
+//	        return 1
+//	    }),
+//	)
+func WithSeverity(weigh func(err error) float64) RetryOption {
+	return func(c *retryConfig) {
+		c.severity = weigh
+	}
+}
+
+// severityWeight returns the factor scaling the backoff delay after err.
+func (c *retryConfig) severityWeight(err error) float64 {
+	if c.severity != nil {
+		if weight := c.severity(err); weight > 0 {
+			return weight
+		}
+	}
+	var weighter SeverityWeighter
+	if errors.As(err, &weighter) {
+		if weight := weighter.SeverityWeight(); weight > 0 {
+			return weight
+		}
+	}
+	return 1
+}
+
+// nextDelay computes the delay before retry number retry following err, and
+// the raw delay of the backoff curve behind it (see RetryEvent.RawBackoff).
+// It returns false if a custom strategy stopped retrying.
+func (c *retryConfig) nextDelay(retry int, err error) (delay, raw time.Duration, ok bool) {
+	return c.delayWith(retry, err, computeJitter)
+}
+
+// delayWith is nextDelay drawing the jitter added to delays with jitter.
+func (c *retryConfig) delayWith(retry int, err error, jitter func(max time.Duration) time.Duration) (delay, raw time.Duration, ok bool) {
+	// Check for server-suggested delay (e.g., HTTP Retry-After, gRPC retry-info)
+	var serverDelay time.Duration
+	if ds, ok := err.(DelaySuggestioner); ok {
+		serverDelay = ds.SuggestedDelay()
+	}
+
+	// Fast retries come first, then the curve starts over
+	if retry <= c.fastRetries {
+		return max(c.fastDelay, serverDelay, 0), max(c.fastDelay, 0), true
+	}
+	retry -= max(c.fastRetries, 0)
+
+	weight := c.severityWeight(err)
+	switch {
+	case c.backoff == nil && c.jitterMode == JitterDecorrelated:
+		// The previous delay, not the retry number, drives the next one
+		delay, raw = c.decorrelatedDelay(weight, jitter)
+		c.prevDelay = delay
+		delay = max(delay, serverDelay)
+	case c.backoff == nil && c.linearIncrement > 0:
+		delay, raw = c.linearDelay(retry, serverDelay, weight)
+		delay = max(c.jitterDelay(delay, jitter), serverDelay)
+	case c.backoff == nil:
+		// Compute delay using exponential backoff, jittered below
+		delay, raw = exponentialDelay(c.initialDuration, c.maxDuration, c.multiplier, 0, retry, serverDelay, weight)
+		delay = max(c.jitterDelay(delay, jitter), serverDelay)
+	default:
+		if raw, ok = c.backoff.NextDelay(retry, err); !ok {
+			return 0, 0, false
+		}
+		raw = scaleDuration(raw, weight)
+		delay = max(c.jitterDelay(raw, jitter), serverDelay)
+	}
+
+	// Shift this instance's whole retry schedule by its stable phase offset
+	if retry == 1 && c.instanceKey != "" {
+		delay = addDurations(delay, instancePhase(c.instanceKey, min(c.initialDuration, c.maxDuration)))
+	}
+	return delay, raw, true
+}
+
+// capped reports whether WithMaxDuration capped the delay before retry,
+// whose delay before capping was raw.
+func (c *retryConfig) capped(retry int, raw time.Duration) bool {
+	return c.backoff == nil && retry > c.fastRetries && raw > c.maxDuration
+}
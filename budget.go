@@ -0,0 +1,312 @@
+package retrier
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BudgetStore holds the counters behind a RetryBudget.
+// The default store keeps counters in process memory. Sharing a networked store
+// (see the redisbackend subpackage) between replicas makes the budget fleet-wide.
+type BudgetStore interface {
+	// Add increments the counter at key by delta and returns the new value.
+	// A counter created by Add should expire after ttl.
+	Add(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+
+	// Get returns the current value of the counter at key, or 0 if it does not exist.
+	Get(ctx context.Context, key string) (int64, error)
+}
+
+// RetryBudget caps retries to a ratio of requests within fixed time windows,
+// so that retries cannot multiply the load on a struggling dependency.
+// A retry is allowed while retries < minRetries + ratio*requests for the current window.
+//
+// Budget bookkeeping fails open: if the store returns an error, the retry is allowed.
+type RetryBudget struct {
+	store      BudgetStore
+	key        string
+	ratio      float64
+	minRetries int64
+	window     time.Duration
+	clock      Clock
+
+	// lastUsed is when b last counted a request or was returned by
+	// ForTenant, in Unix nanoseconds
+	lastUsed atomic.Int64
+
+	mu           sync.Mutex
+	tenants      map[string]*RetryBudget
+	lastEviction time.Time
+}
+
+// BudgetStats reports the activity of a RetryBudget in the current window.
+type BudgetStats struct {
+	// Requests is the number of Retry calls counted.
+	Requests int64 `json:"requests"`
+
+	// Retries is the number of retries granted.
+	Retries int64 `json:"retries"`
+
+	// Denied is the number of retries refused because the budget was exhausted.
+	Denied int64 `json:"denied"`
+}
+
+// BudgetOption is a functional option for configuring a RetryBudget.
+type BudgetOption func(*RetryBudget)
+
+// WithBudgetStore sets the store holding the budget counters and the key
+// identifying this budget in it. Budgets sharing a store and key share their counters.
+// Default is a private in-memory store.
+func WithBudgetStore(store BudgetStore, key string) BudgetOption {
+	return func(b *RetryBudget) {
+		b.store = store
+		b.key = key
+	}
+}
+
+// WithBudgetWindow sets the length of the accounting window. A window of 0
+// or less keeps the default of 10 seconds.
+func WithBudgetWindow(d time.Duration) BudgetOption {
+	return func(b *RetryBudget) {
+		if d > 0 {
+			b.window = d
+		}
+	}
+}
+
+// WithBudgetClock sets the Clock deciding the accounting window. Use the
+// Clock of the retry loops (see WithClock), such as a FakeClock in tests.
+// Default is the system clock.
+func WithBudgetClock(c Clock) BudgetOption {
+	return func(b *RetryBudget) {
+		b.clock = c
+	}
+}
+
+// WithMinRetries sets the number of retries allowed per window regardless of
+// the request count, so low-traffic operations can still retry. Default is 10.
+func WithMinRetries(n int) BudgetOption {
+	return func(b *RetryBudget) {
+		b.minRetries = int64(n)
+	}
+}
+
+// NewRetryBudget creates a RetryBudget allowing ratio retries per request.
+// For example, a ratio of 0.1 allows one retry for every ten Retry calls.
+func NewRetryBudget(ratio float64, opts ...BudgetOption) *RetryBudget {
+	b := &RetryBudget{
+		store:      NewMemoryBudgetStore(),
+		key:        "default",
+		ratio:      ratio,
+		minRetries: 10,
+		window:     10 * time.Second,
+		clock:      systemClock{},
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// WithBudget makes Retry consult b before each retry.
+// Every Retry call counts as a request; when the budget is exhausted,
+// Retry returns immediately with ErrBudgetExhausted.
+func WithBudget(b *RetryBudget) RetryOption {
+	return func(c *retryConfig) {
+		c.budget = b
+	}
+}
+
+// ForTenant returns the budget of tenant id, creating it on first use.
+// Each tenant gets its own counters with the same ratio, minimum, and window
+// as b, so a noisy tenant exhausts only its own budget and cannot starve the
+// others. Tenant budgets live in the same store as b.
+//
+// Tenants unused for two windows, whose counters have expired, are dropped,
+// so b does not grow with every tenant id it has seen. A dropped tenant
+// keeps working, and ForTenant returns a new budget sharing its counters.
+func (b *RetryBudget) ForTenant(id string) *RetryBudget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	b.evictIdleTenants(now)
+	if tenant, ok := b.tenants[id]; ok {
+		tenant.lastUsed.Store(now.UnixNano())
+		return tenant
+	}
+	if b.tenants == nil {
+		b.tenants = make(map[string]*RetryBudget)
+	}
+	tenant := &RetryBudget{
+		store:      b.store,
+		key:        b.key + ":tenant:" + id,
+		ratio:      b.ratio,
+		minRetries: b.minRetries,
+		window:     b.window,
+		clock:      b.clock,
+	}
+	tenant.lastUsed.Store(now.UnixNano())
+	b.tenants[id] = tenant
+	return tenant
+}
+
+// evictIdleTenants drops the tenant budgets unused since their counters
+// expired. It scans the tenants at most once per window.
+func (b *RetryBudget) evictIdleTenants(now time.Time) {
+	if now.Sub(b.lastEviction) < b.window {
+		return
+	}
+	b.lastEviction = now
+	for id, tenant := range b.tenants {
+		if now.Sub(time.Unix(0, tenant.lastUsed.Load())) > 2*b.window {
+			delete(b.tenants, id)
+		}
+	}
+}
+
+// Stats returns the activity of b in the current window.
+// Tenant budgets are not included; see TenantStats.
+func (b *RetryBudget) Stats(ctx context.Context) (BudgetStats, error) {
+	var stats BudgetStats
+	var err error
+	if stats.Requests, err = b.store.Get(ctx, b.counterKey("requests")); err != nil {
+		return BudgetStats{}, err
+	}
+	if stats.Retries, err = b.store.Get(ctx, b.counterKey("retries")); err != nil {
+		return BudgetStats{}, err
+	}
+	if stats.Denied, err = b.store.Get(ctx, b.counterKey("denied")); err != nil {
+		return BudgetStats{}, err
+	}
+	return stats, nil
+}
+
+// TenantStats returns the current-window activity of every tenant budget
+// created with ForTenant and used within the last two windows, keyed by
+// tenant id.
+func (b *RetryBudget) TenantStats(ctx context.Context) (map[string]BudgetStats, error) {
+	b.mu.Lock()
+	b.evictIdleTenants(b.clock.Now())
+	tenants := make(map[string]*RetryBudget, len(b.tenants))
+	for id, tenant := range b.tenants {
+		tenants[id] = tenant
+	}
+	b.mu.Unlock()
+
+	stats := make(map[string]BudgetStats, len(tenants))
+	for id, tenant := range tenants {
+		s, err := tenant.Stats(ctx)
+		if err != nil {
+			return nil, err
+		}
+		stats[id] = s
+	}
+	return stats, nil
+}
+
+// recordRequest counts a new Retry call against the current window.
+func (b *RetryBudget) recordRequest(ctx context.Context) {
+	b.lastUsed.Store(b.clock.Now().UnixNano())
+	_, _ = b.store.Add(ctx, b.counterKey("requests"), 1, 2*b.window)
+}
+
+// allowRetry reserves a retry in the current window, returning false if
+// the budget is exhausted.
+func (b *RetryBudget) allowRetry(ctx context.Context) bool {
+	requests, err := b.store.Get(ctx, b.counterKey("requests"))
+	if err != nil {
+		return true
+	}
+	retriesKey := b.counterKey("retries")
+	retries, err := b.store.Add(ctx, retriesKey, 1, 2*b.window)
+	if err != nil {
+		return true
+	}
+	if float64(retries) > float64(b.minRetries)+b.ratio*float64(requests) {
+		// Give the reservation back so denied retries do not eat into the budget
+		_, _ = b.store.Add(ctx, retriesKey, -1, 2*b.window)
+		_, _ = b.store.Add(ctx, b.counterKey("denied"), 1, 2*b.window)
+		return false
+	}
+	return true
+}
+
+// counterKey returns the store key of the named counter for the current window.
+func (b *RetryBudget) counterKey(counter string) string {
+	window := b.clock.Now().UnixNano() / int64(b.window)
+	return b.key + ":" + strconv.FormatInt(window, 10) + ":" + counter
+}
+
+// MemoryBudgetStore is an in-process BudgetStore.
+// It is safe for concurrent use.
+type MemoryBudgetStore struct {
+	mu       sync.Mutex
+	counters map[string]memoryCounter
+
+	// nextExpiry is the earliest expiry among counters, before which there
+	// is nothing to evict
+	nextExpiry time.Time
+}
+
+type memoryCounter struct {
+	value     int64
+	expiresAt time.Time
+}
+
+// NewMemoryBudgetStore creates an empty MemoryBudgetStore.
+func NewMemoryBudgetStore() *MemoryBudgetStore {
+	return &MemoryBudgetStore{counters: make(map[string]memoryCounter)}
+}
+
+// Add increments the counter at key by delta and returns the new value.
+func (s *MemoryBudgetStore) Add(_ context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.evictExpired(now)
+
+	counter, ok := s.counters[key]
+	if !ok {
+		counter.expiresAt = now.Add(ttl)
+		if len(s.counters) == 0 || counter.expiresAt.Before(s.nextExpiry) {
+			s.nextExpiry = counter.expiresAt
+		}
+	}
+	counter.value += delta
+	s.counters[key] = counter
+	return counter.value, nil
+}
+
+// Get returns the current value of the counter at key.
+func (s *MemoryBudgetStore) Get(_ context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, ok := s.counters[key]
+	if !ok || !time.Now().Before(counter.expiresAt) {
+		return 0, nil
+	}
+	return counter.value, nil
+}
+
+// evictExpired drops counters whose window has passed. It scans the
+// counters only once the earliest of them has expired.
+func (s *MemoryBudgetStore) evictExpired(now time.Time) {
+	if len(s.counters) == 0 || now.Before(s.nextExpiry) {
+		return
+	}
+	s.nextExpiry = time.Time{}
+	for key, counter := range s.counters {
+		switch {
+		case !now.Before(counter.expiresAt):
+			delete(s.counters, key)
+		case s.nextExpiry.IsZero() || counter.expiresAt.Before(s.nextExpiry):
+			s.nextExpiry = counter.expiresAt
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package retrier
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// StopSignal aborts retry loops from outside, for example on shutdown or when
+// an operator gives up on a dependency. Once stopped, loops using it do not
+// retry, and the context of an attempt under RetryCtx is cancelled so the
+// attempt can bail out early (see CheckCancel).
+//
+// A StopSignal is safe for concurrent use and can be shared by any number of
+// Retry calls. Stopping is permanent.
+type StopSignal struct {
+	once sync.Once
+	done chan struct{}
+}
+
+// NewStopSignal creates a StopSignal.
+func NewStopSignal() *StopSignal {
+	return &StopSignal{done: make(chan struct{})}
+}
+
+// Stop aborts every retry loop using s. Its signature makes it usable directly
+// as a callback.
+func (s *StopSignal) Stop() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// Stopped reports whether Stop has been called.
+func (s *StopSignal) Stopped() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// WithStopSignal lets s abort the retry loop. A stopped loop returns
+// ErrRetryStopped, wrapping the last error.
+func WithStopSignal(s *StopSignal) RetryOption {
+	return func(c *retryConfig) {
+		c.stop = s
+	}
+}
+
+// CheckCancel returns nil while the attempt owning ctx should keep going, and
+// otherwise the reason it was aborted: a RetryError with ErrRetryStopped when a
+// StopSignal fired, or the cause of the cancellation of ctx. Long-running
+// attempts under RetryCtx should call it between steps and return its error:
+//
+//	for _, chunk := range chunks {
+//	    if err := retrier.CheckCancel(ctx); err != nil {
+//	        return 0, err
+//	    }
+//	    // process chunk
+//	}
+func CheckCancel(ctx context.Context) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	return context.Cause(ctx)
+}
+
+// attemptContext derives the context of attempt from ctx: it is cancelled
+// with a RetryError when the StopSignal of c fires during the attempt, bounded
+// by the deadline split and the duration limits of c, given the time elapsed
+// since the loop started, and then passed through the attempt hooks of c.
+// release must be called once the attempt returns.
+func (c *retryConfig) attemptContext(ctx context.Context, attempt int, elapsed time.Duration) (attemptCtx context.Context, release func()) {
+	var cleanups []func()
+	if c.stop != nil {
+		stopCtx, cancel := context.WithCancelCause(ctx)
+		go func() {
+			select {
+			case <-c.stop.done:
+				cancel(NewRetryError(
+					ErrRetryStopped,
+					fmt.Sprintf("retry stopped during attempt %d", attempt),
+					RetryPolicyNever,
+					nil,
+				))
+			case <-stopCtx.Done():
+			}
+		}()
+		ctx = stopCtx
+		cleanups = append(cleanups, func() { cancel(nil) })
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if timeout, ok := c.attemptTimeout(deadline, attempt); ok {
+			splitCtx, cancel := context.WithTimeoutCause(ctx, timeout,
+				fmt.Errorf("%w: attempt %d exceeded its share of the deadline (%v)", context.DeadlineExceeded, attempt, timeout))
+			ctx = splitCtx
+			cleanups = append(cleanups, cancel)
+		}
+	}
+	if limit, ok := c.attemptLimit(elapsed); ok {
+		limitCtx, cancel := context.WithTimeoutCause(ctx, limit,
+			fmt.Errorf("%w: attempt %d exceeded its time limit (%v)", context.DeadlineExceeded, attempt, limit))
+		ctx = limitCtx
+		cleanups = append(cleanups, cancel)
+	}
+	for _, hook := range c.attemptHooks {
+		var cleanup func()
+		ctx, cleanup = hook(ctx, attempt)
+		if cleanup != nil {
+			cleanups = append(cleanups, cleanup)
+		}
+	}
+	return ctx, func() {
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+	}
+}
+
+// contextCause returns the cause of the cancellation of ctx, such as a
+// StopSignal or a timeout set with WithTimeoutCause, or nil if ctx is not
+// done or was cancelled without a cause of its own.
+func contextCause(ctx context.Context) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	if cause := context.Cause(ctx); cause != ctx.Err() {
+		return cause
+	}
+	return nil
+}
+
+// contextError returns the error of the done context ctx, wrapping its
+// cause too, if any, so callers see why it was cancelled while errors.Is
+// still matches context.Canceled or context.DeadlineExceeded.
+func contextError(ctx context.Context) error {
+	if cause := contextCause(ctx); cause != nil {
+		return fmt.Errorf("%w: %w", ctx.Err(), cause)
+	}
+	return ctx.Err()
+}
+
+// stopped returns a channel closed when the StopSignal of c fires, or nil
+// (blocking forever) when there is none.
+func (c *retryConfig) stopped() <-chan struct{} {
+	if c.stop == nil {
+		return nil
+	}
+	return c.stop.done
+}
+
+// stopError creates the RetryError of a loop aborted by a StopSignal after attempt.
+func (c *retryConfig) stopError(history []AttemptError, attempt int, lastErr error) *RetryError {
+	return c.retryError(
+		history,
+		ErrRetryStopped,
+		fmt.Sprintf("retry stopped after %d attempts", attempt),
+		RetryPolicyNever,
+		lastErr,
+	)
+}
@@ -0,0 +1,86 @@
+// Package cenkaltibackoff adapts retrier policies to and from the BackOff
+// interface of github.com/cenkalti/backoff (v3, v4, and v5) without importing it.
+package cenkaltibackoff
+
+import (
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// Stop mirrors backoff.Stop: a BackOff returns it to signal that no more
+// retries should be made.
+const Stop time.Duration = -1
+
+// BackOff has the method set of backoff.BackOff, so any cenkalti BackOff
+// satisfies it and the BackOff returned by NewBackOff can be passed to
+// backoff.Retry.
+type BackOff interface {
+	// NextBackOff returns the duration to wait before retrying, or Stop.
+	NextBackOff() time.Duration
+
+	// Reset returns the BackOff to its initial state.
+	Reset()
+}
+
+// RetrierBackOff is a BackOff producing the delays of a retrier configuration.
+// It is not safe for concurrent use.
+type RetrierBackOff struct {
+	options retrier.Options
+	retry   int
+}
+
+// NewBackOff creates a BackOff producing the delays retrier.Retry would use with opts.
+// NextBackOff returns Stop after MaxAttempts-1 delays, matching the number of
+// retries retrier.Retry makes.
+func NewBackOff(opts ...retrier.RetryOption) *RetrierBackOff {
+	return &RetrierBackOff{options: retrier.ResolveOptions(opts...)}
+}
+
+// NextBackOff returns the delay before the next retry, or Stop.
+func (b *RetrierBackOff) NextBackOff() time.Duration {
+	if b.retry >= b.options.MaxAttempts-1 {
+		return Stop
+	}
+	b.retry++
+	return b.options.Delay(b.retry)
+}
+
+// Reset restarts the delay sequence.
+func (b *RetrierBackOff) Reset() {
+	b.retry = 0
+}
+
+// WithBackOff makes retrier.Retry wait for the delays of a cenkalti BackOff.
+// newBackOff is called at the start of every Retry call, so each call gets its
+// own BackOff state. When the BackOff returns Stop, Retry returns
+// retrier.ErrBackoffStopped. MaxAttempts still bounds the number of attempts.
+//
+// Example:
+//
+//	result := retrier.Retry(ctx, logger, fn,
+//	    cenkaltibackoff.WithBackOff(func() backoff.BackOff {
+//	        return backoff.NewExponentialBackOff()
+//	    }),
+//	)
+func WithBackOff[B BackOff](newBackOff func() B) retrier.RetryOption {
+	return retrier.WithBackoff(func() retrier.BackoffStrategy {
+		b := newBackOff()
+		b.Reset()
+		return strategy{b}
+	})
+}
+
+// strategy exposes a BackOff as a retrier.BackoffStrategy.
+type strategy struct {
+	backOff BackOff
+}
+
+// NextDelay returns the BackOff's next delay.
+func (s strategy) NextDelay(int, error) (time.Duration, bool) {
+	d := s.backOff.NextBackOff()
+	if d == Stop {
+		return 0, false
+	}
+	return d, true
+}
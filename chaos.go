@@ -0,0 +1,66 @@
+package retrier
+
+import (
+	"errors"
+	"math/rand/v2"
+	"os"
+	"strconv"
+)
+
+// ChaosEnv is the environment variable enabling WithChaos in builds without
+// the retrierchaos build tag. Any value strconv.ParseBool accepts as true
+// enables it.
+const ChaosEnv = "RETRIER_CHAOS"
+
+// ErrChaosInjected is the failure WithChaos injects when it has no error factory.
+var ErrChaosInjected = errors.New("chaos: injected failure")
+
+// chaos injects failures into attempts (see WithChaos).
+type chaos struct {
+	prob       float64
+	errFactory func() error
+}
+
+// inject returns an injected failure, or nil if the attempt should run.
+func (c *chaos) inject() error {
+	if rand.Float64() >= c.prob {
+		return nil
+	}
+	if c.errFactory == nil {
+		return ErrChaosInjected
+	}
+	return c.errFactory()
+}
+
+// WithChaos makes each attempt fail with probability prob, without running
+// the function, with the error errFactory returns (ErrChaosInjected if
+// errFactory is nil). Injected failures go through the same classification,
+// backoff, and callbacks as real ones, so staging environments can verify
+// retry and fallback behavior without infrastructure-level fault injection.
+//
+// WithChaos does nothing unless the program is built with the retrierchaos
+// build tag or the ChaosEnv environment variable is true when the option is
+// created, so it cannot fire in production by accident. Default is none.
+//
+// Example:
+//
+//	result := retrier.Retry(ctx, logger, fn,
+//	    retrier.WithChaos(0.2, func() error { return errUpstreamTimeout }),
+//	)
+func WithChaos(prob float64, errFactory func() error) RetryOption {
+	if !chaosEnabled() {
+		return func(*retryConfig) {}
+	}
+	return func(c *retryConfig) {
+		c.chaos = &chaos{prob: prob, errFactory: errFactory}
+	}
+}
+
+// chaosEnabled reports whether WithChaos may inject failures.
+func chaosEnabled() bool {
+	if chaosBuildTag {
+		return true
+	}
+	enabled, _ := strconv.ParseBool(os.Getenv(ChaosEnv))
+	return enabled
+}
@@ -0,0 +1,6 @@
+//go:build !retrierchaos
+
+package retrier
+
+// chaosBuildTag enables WithChaos in builds with the retrierchaos tag.
+const chaosBuildTag = false
@@ -0,0 +1,49 @@
+package retrier
+
+import "time"
+
+// Clock is the time source of the retry loop: it timestamps attempt errors and
+// times backoff delays. Tests can substitute a fake clock (see
+// retriertest.FakeClock) to run retries without real sleeps.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer creates a Timer firing once after d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single-shot timer created by a Clock.
+type Timer interface {
+	// C returns the channel receiving the time when the timer fires.
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing. It returns false if the timer
+	// already fired or was stopped.
+	Stop() bool
+}
+
+// WithClock sets the Clock of the retry loop. Default is the system clock.
+func WithClock(c Clock) RetryOption {
+	return func(config *retryConfig) {
+		config.clock = c
+	}
+}
+
+// systemClock is the Clock backed by package time.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+// systemTimer adapts *time.Timer to Timer.
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time { return t.t.C }
+
+func (t systemTimer) Stop() bool { return t.t.Stop() }
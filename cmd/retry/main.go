@@ -0,0 +1,165 @@
+// Command retry runs a command until it succeeds, with the retry policies of
+// the retrier package.
+//
+// Usage:
+//
+//	retry [flags] -- command [args...]
+//
+// Example:
+//
+//	retry -attempts 5 -initial 1s -- curl -fsS https://example.com/health
+//	retry -policy "exponential(500ms, x2, max=30s, attempts=8)" -- ./migrate.sh
+//
+// Flags given explicitly override the settings of -policy. retry exits with
+// the status of the last attempt of the command, 0 once it succeeds, 1 if the
+// command could not run, and 2 for usage errors.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/execretry"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run parses args, runs the command with retries, and returns the exit status.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("retry", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: retry [flags] -- command [args...]")
+		fs.PrintDefaults()
+	}
+	settings := retrier.BindFlags(fs, "")
+	fs.IntVar(&settings.MaxAttempts, "attempts", settings.MaxAttempts, "maximum number of attempts (alias of -max-attempts)")
+	policy := fs.String("policy", "", `retry policy, such as "exponential(1s, x2, max=30s, attempts=5)"`)
+	permanent := fs.String("permanent-exit", "", "comma-separated exit statuses that are not retried")
+	verbose := fs.Bool("v", false, "log attempts to standard error")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	opts, err := options(fs, settings, *policy, *permanent)
+	if err != nil {
+		fmt.Fprintf(stderr, "retry: %v\n", err)
+		return 2
+	}
+
+	var logger retrier.DebugLogger = retrier.NewNoOpLogger()
+	if *verbose {
+		logger = &stderrLogger{w: stderr}
+	}
+	name, cmdArgs := fs.Arg(0), fs.Args()[1:]
+	result := execretry.Run(ctx, logger, func(ctx context.Context) *exec.Cmd {
+		cmd := exec.CommandContext(ctx, name, cmdArgs...)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		return cmd
+	}, opts...)
+
+	if result.IsSuccess() {
+		return 0
+	}
+	if code, ok := execretry.ExitCode(result.Err()); ok {
+		return code
+	}
+	fmt.Fprintf(stderr, "retry: %v\n", result.Err())
+	return 1
+}
+
+// options builds the retry options: those of policy, overridden by the flags
+// set explicitly, then the classification of permanent exit statuses.
+func options(fs *flag.FlagSet, settings *retrier.Options, policy, permanent string) ([]retrier.RetryOption, error) {
+	var opts []retrier.RetryOption
+	if policy != "" {
+		policyOpts, err := retrier.ParsePolicy(policy)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, policyOpts...)
+	}
+
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "attempts", "max-attempts":
+			if settings.MaxAttempts < 1 {
+				flagErr = fmt.Errorf("invalid -%s %d: must be at least 1", f.Name, settings.MaxAttempts)
+			}
+			opts = append(opts, retrier.WithMaxAttempts(settings.MaxAttempts))
+		case "initial":
+			if settings.InitialDuration < 0 {
+				flagErr = fmt.Errorf("invalid -initial %v: must not be negative", settings.InitialDuration)
+			}
+			opts = append(opts, retrier.WithInitialDuration(settings.InitialDuration))
+		case "multiplier":
+			if settings.Multiplier < 1 {
+				flagErr = fmt.Errorf("invalid -multiplier %v: must be at least 1", settings.Multiplier)
+			}
+			opts = append(opts, retrier.WithMultiplier(settings.Multiplier))
+		case "max":
+			if settings.MaxDuration <= 0 {
+				flagErr = fmt.Errorf("invalid -max %v: must be positive", settings.MaxDuration)
+			}
+			opts = append(opts, retrier.WithMaxDuration(settings.MaxDuration))
+		case "jitter":
+			if settings.Jitter < 0 {
+				flagErr = fmt.Errorf("invalid -jitter %v: must not be negative", settings.Jitter)
+			}
+			opts = append(opts, retrier.WithJitter(settings.Jitter))
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
+	if permanent != "" {
+		var codes []int
+		for _, field := range strings.Split(permanent, ",") {
+			code, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return nil, fmt.Errorf("invalid -permanent-exit status %q", field)
+			}
+			codes = append(codes, code)
+		}
+		opts = append(opts, retrier.WithRetryIf(execretry.RetryIfExitCode(codes...)))
+	}
+	return opts, nil
+}
+
+// stderrLogger logs attempts as plain lines.
+type stderrLogger struct {
+	w io.Writer
+}
+
+func (l *stderrLogger) Enabled() bool { return true }
+
+func (l *stderrLogger) LogRetry(_ context.Context, attempt, maxAttempts int, backoff time.Duration, err error, _ ...any) {
+	switch {
+	case err == nil:
+		fmt.Fprintf(l.w, "retry: attempt %d/%d succeeded\n", attempt, maxAttempts)
+	case backoff > 0:
+		fmt.Fprintf(l.w, "retry: attempt %d/%d failed: %v; retrying in %v\n", attempt, maxAttempts, err, backoff)
+	default:
+		fmt.Fprintf(l.w, "retry: attempt %d/%d failed: %v; giving up\n", attempt, maxAttempts, err)
+	}
+}
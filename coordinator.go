@@ -0,0 +1,44 @@
+package retrier
+
+import (
+	"context"
+	"time"
+)
+
+// Coordinator serializes retries of a keyed operation across processes.
+// In a multi-replica deployment, only the instance holding the lease for a key
+// runs its retry ladder; the other instances give up after their first failed
+// attempt instead of adding more load to the failing dependency.
+//
+// See the redisbackend subpackage for a Redis-based implementation.
+type Coordinator interface {
+	// Acquire attempts to take the lease for key for at most ttl.
+	// It returns false if the lease is currently held by someone else.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+
+	// Release gives up a lease previously obtained with Acquire.
+	Release(ctx context.Context, key string) error
+}
+
+// coordination holds the Coordinator settings configured via WithCoordinator.
+type coordination struct {
+	coordinator Coordinator
+	key         string
+	ttl         time.Duration
+}
+
+// WithCoordinator makes retries of the operation identified by key exclusive
+// across all processes sharing the Coordinator.
+// The first attempt always runs. Before the first retry, the lease for key is
+// acquired with the given ttl; if another instance holds it, Retry returns
+// immediately with ErrCoordinationDenied. The lease is released when Retry returns.
+// The ttl should exceed the worst-case duration of the whole retry ladder.
+func WithCoordinator(c Coordinator, key string, ttl time.Duration) RetryOption {
+	return func(cfg *retryConfig) {
+		cfg.coordination = &coordination{
+			coordinator: c,
+			key:         key,
+			ttl:         ttl,
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package retrier
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"time"
 )
 
@@ -10,11 +12,60 @@ import (
 type retryConfig struct {
 	jitter             time.Duration
 	maxAttempts        int
+	zeroAttempts       ZeroAttempts
+	softMaxAttempts    int
 	initialDuration    time.Duration
 	multiplier         float64
 	maxDuration        time.Duration
 	defaultRetryPolicy RetryPolicy
 	attrs              []any
+	coordination       *coordination
+	budget             *RetryBudget
+	instanceKey        string
+	guard              *RetryGuard
+	wake               *WakeSignal
+	backoff            BackoffStrategy
+	newBackoff         func() BackoffStrategy
+	retryIf            func(error) bool
+	resultCheck        func(result any) error
+	resultType         reflect.Type
+	onRetry            func(attempt int, err error)
+	provider           PolicyProvider
+	enabled            func(ctx context.Context) bool
+	fingerprinter      Fingerprinter
+	errorFormatter     func(*RetryError) string
+	redactor           Redactor
+	semaphore          Semaphore
+	stop               *StopSignal
+	attemptHooks       []AttemptHook
+	clock              Clock
+	notify             chan<- RetryEvent
+	notifyMode         NotifyMode
+	chaos              *chaos
+	tuner              *LatencyTuner
+	deadlineSplit      DeadlineSplit
+	summarySink        SummarySink
+	fastRetries        int
+	fastDelay          time.Duration
+	healthCheck        func(ctx context.Context) bool
+	explain            bool
+	maxTotalBackoff    time.Duration
+	severity           func(err error) float64
+	watchdog           time.Duration
+	onStuck            func(ctx context.Context, s StuckAttempt)
+	watchdogStacks     bool
+	maxAttemptDuration time.Duration
+	maxTotalDuration   time.Duration
+	maxElapsedTime     time.Duration
+	validation         *sampledValidation
+	traceID            func(ctx context.Context) string
+	jitterMode         JitterMode
+	linearIncrement    time.Duration // step of WithLinearBackoff; 0 for exponential
+	traceName          string
+	recordingSink      RecordingSink
+	prevDelay          time.Duration // last delay of JitterDecorrelated in the current loop
+	strict             StrictMode
+	softFail           bool
 }
 
 // defaults returns a retryConfig with sensible default values.
@@ -26,20 +77,111 @@ func defaults() retryConfig {
 		initialDuration:    1 * time.Second,
 		multiplier:         2.0,
 		maxDuration:        1 * time.Minute,
+		fingerprinter:      DefaultFingerprint,
+		clock:              systemClock{},
 	}
 }
 
+// newConfig applies opts over the defaults, followed by the options of the
+// policy provider, if any, and the initial backoff of the latency tuner.
+func newConfig(opts []RetryOption) retryConfig {
+	config := applyOptions(opts)
+	if config.provider != nil {
+		for _, opt := range config.provider.RetryOptions() {
+			opt(&config)
+		}
+	}
+	if config.tuner != nil {
+		if d, ok := config.tuner.InitialDuration(); ok {
+			config.initialDuration = d
+		}
+	}
+	if config.newBackoff != nil {
+		config.backoff = config.newBackoff()
+	}
+	return config
+}
+
+// applyOptions applies opts over the defaults without calling the backoff
+// strategy factory, PolicyProvider, or LatencyTuner they set, for readers of
+// the configured settings that run no call.
+func applyOptions(opts []RetryOption) retryConfig {
+	config := defaults()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
 // RetryOption is a functional option for configuring retry behavior.
 type RetryOption func(*retryConfig)
 
 // WithMaxAttempts sets the maximum number of retry attempts.
-// Default is 3.
+// A limit of 0 is a configuration error unless WithZeroAttempts makes it
+// unlimited; a negative limit is always one. Default is 3.
 func WithMaxAttempts(n int) RetryOption {
 	return func(c *retryConfig) {
 		c.maxAttempts = n
 	}
 }
 
+// ZeroAttempts is what a WithMaxAttempts limit of 0 means (see
+// WithZeroAttempts).
+type ZeroAttempts int
+
+const (
+	// ZeroAttemptsInvalid makes a limit of 0 a configuration error: Retry
+	// returns ErrZeroAttempt without calling fn.
+	ZeroAttemptsInvalid ZeroAttempts = iota
+
+	// ZeroAttemptsUnlimited makes a limit of 0 mean no limit: the loop
+	// retries until fn succeeds, an error is not retried, or another limit
+	// such as the context, WithMaxTotalDuration, or a RetryBudget stops it.
+	ZeroAttemptsUnlimited
+)
+
+// WithZeroAttempts sets what a WithMaxAttempts limit of 0 means, for callers
+// that want to retry until something else stops the loop. With
+// ZeroAttemptsUnlimited, RetryEvent.MaxAttempts, Result.MaxAttempts, and the
+// maxAttempts passed to DebugLogger.LogRetry are 0, and WithDeadlineSplit
+// does not split the deadline. Default is ZeroAttemptsInvalid.
+//
+// Example:
+//
+//	// Retry until ctx is done
+//	result := retrier.RetryCtx(ctx, logger, fn,
+//	    retrier.WithMaxAttempts(0),
+//	    retrier.WithZeroAttempts(retrier.ZeroAttemptsUnlimited),
+//	    retrier.WithMaxDuration(30*time.Second),
+//	)
+func WithZeroAttempts(mode ZeroAttempts) RetryOption {
+	return func(c *retryConfig) {
+		c.zeroAttempts = mode
+	}
+}
+
+// unlimited reports whether the loop has no attempt limit.
+func (c *retryConfig) unlimited() bool {
+	return c.maxAttempts == 0 && c.zeroAttempts == ZeroAttemptsUnlimited
+}
+
+// lastAttempt reports whether attempt is the last one the limit allows.
+func (c *retryConfig) lastAttempt(attempt int) bool {
+	return !c.unlimited() && attempt >= c.maxAttempts
+}
+
+// WithSoftMaxAttempts sets a soft limit on attempts that does not stop the
+// loop: when attempt n fails and another attempt follows, an
+// EventSoftLimitExceeded is published (see WithNotifyChannel) and a warning
+// is logged if the logger implements WarningLogger. WithMaxAttempts still
+// decides when the loop gives up. Use it to learn how often a policy runs
+// long before tightening it. Default is none.
+func WithSoftMaxAttempts(n int) RetryOption {
+	return func(c *retryConfig) {
+		c.softMaxAttempts = n
+	}
+}
+
 // WithJitter sets the maximum random duration added to backoff delays.
 // This helps avoid thundering herd problems. Default is 0 (no jitter).
 func WithJitter(d time.Duration) RetryOption {
@@ -72,14 +214,26 @@ func WithMaxDuration(d time.Duration) RetryOption {
 	}
 }
 
-// WithRetryPolicy sets the default retry policy for standard errors.
-// Default is RetryPolicyAuto (standard errors are retried automatically).
-func WithRetryPolicy(p RetryPolicy) RetryOption {
+// WithDefaultPolicy sets how errors that do not implement RetryableError are
+// treated, unless WithRetryIf decides instead: RetryPolicyAuto retries them,
+// while RetryPolicyManual and RetryPolicyNever fail fast, so that only errors
+// explicitly classified as retryable are retried. Default is RetryPolicyAuto.
+//
+// Example:
+//
+//	// Retry only errors that ask for it
+//	r := retrier.NewRetrier(logger, retrier.WithDefaultPolicy(retrier.RetryPolicyNever))
+func WithDefaultPolicy(p RetryPolicy) RetryOption {
 	return func(c *retryConfig) {
 		c.defaultRetryPolicy = p
 	}
 }
 
+// WithRetryPolicy is WithDefaultPolicy, under its original name.
+func WithRetryPolicy(p RetryPolicy) RetryOption {
+	return WithDefaultPolicy(p)
+}
+
 // WithLogAttrs sets additional attributes to be passed to the logger.
 // Attributes follow Go's slog convention for structured logging - alternating
 // key-value pairs (string, any, string, any, ...).
@@ -90,12 +244,147 @@ func WithLogAttrs(attrs ...any) RetryOption {
 	}
 }
 
+// WithInstanceKey sets a stable identifier of this process (pod name, host ID, ...).
+// The first backoff delay is shifted by an offset in [0, initialDuration) derived
+// from a hash of key, so instances that failed at the same moment retry at
+// different, deterministic times instead of in lockstep. Default is no offset.
+func WithInstanceKey(key string) RetryOption {
+	return func(c *retryConfig) {
+		c.instanceKey = key
+	}
+}
+
+// WithRetryIf sets a predicate deciding which errors are retried, replacing
+// the RetryPolicy-based decision. Errors for which retryIf returns false are
+// returned immediately. Default is to follow RetryPolicy.
+func WithRetryIf(retryIf func(err error) bool) RetryOption {
+	return func(c *retryConfig) {
+		c.retryIf = retryIf
+	}
+}
+
+// WithRetryOnResult sets a check of successful results, for APIs that report
+// failures in the response itself, such as GraphQL servers answering HTTP 200
+// with an errors array. When check returns an error, the attempt fails with
+// it and is retried or returned like any other error; the Result then
+// carries no value. T must match the type parameter of the retried function:
+// when it cannot hold its results, Retry fails before the first attempt with
+// ErrResultTypeMismatch. Default is none.
+//
+// Example:
+//
+//	result := retrier.Retry(ctx, logger, fetchJob,
+//	    retrier.WithRetryOnResult(func(job Job) error {
+//	        if job.Status == "pending" {
+//	            return errors.New("job still pending")
+//	        }
+//	        return nil
+//	    }),
+//	)
+func WithRetryOnResult[T any](check func(result T) error) RetryOption {
+	return func(c *retryConfig) {
+		c.resultType = reflect.TypeFor[T]()
+		c.resultCheck = func(result any) error {
+			value, ok := result.(T)
+			if !ok && result != nil {
+				// Only possible for interface results holding another type
+				return Permanent(fmt.Errorf("retrier: WithRetryOnResult checks %v results, got %T", c.resultType, result))
+			}
+			return check(value)
+		}
+	}
+}
+
+// resultCheckMismatch reports why the WithRetryOnResult check of c cannot
+// check results of type result, or "" if it can.
+func (c *retryConfig) resultCheckMismatch(result reflect.Type) string {
+	if c.resultCheck == nil || c.resultType == nil {
+		return ""
+	}
+	// Interface results are checked by their dynamic type
+	if result.AssignableTo(c.resultType) || (result.Kind() == reflect.Interface && c.resultType.Implements(result)) {
+		return ""
+	}
+	return fmt.Sprintf("WithRetryOnResult checks %v results, but the retried function returns %v", c.resultType, result)
+}
+
+// WithOnRetry sets a callback invoked before each backoff delay with the number
+// of the failed attempt (1-based) and its error. Default is none.
+func WithOnRetry(onRetry func(attempt int, err error)) RetryOption {
+	return func(c *retryConfig) {
+		c.onRetry = onRetry
+	}
+}
+
+// WithEnabledFunc sets a switch consulted before each retry (never before the
+// first attempt). When enabled returns false, Retry stops with ErrRetriesDisabled,
+// wrapping the last error. Backing enabled with a feature flag lets operators
+// turn retries off for a misbehaving dependency without a deploy.
+// Default is always enabled.
+func WithEnabledFunc(enabled func(ctx context.Context) bool) RetryOption {
+	return func(c *retryConfig) {
+		c.enabled = enabled
+	}
+}
+
+// WithHealthCheck sets a cheap probe of the dependency, such as a TCP dial or
+// a /healthz request, consulted after each backoff delay. While check returns
+// false, the loop waits the same delay again and probes anew instead of
+// spending an attempt on a dependency known to be down; the wait ends only
+// when check passes, the context is done, or a StopSignal fires, so bound
+// the context when the dependency may stay down. check receives the context
+// of the loop. Default is none.
+//
+// Example:
+//
+//	retrier.WithHealthCheck(func(ctx context.Context) bool {
+//	    conn, err := dialer.DialContext(ctx, "tcp", "db:5432")
+//	    if err != nil {
+//	        return false
+//	    }
+//	    conn.Close()
+//	    return true
+//	})
+func WithHealthCheck(check func(ctx context.Context) bool) RetryOption {
+	return func(c *retryConfig) {
+		c.healthCheck = check
+	}
+}
+
+// WithErrorFormatter sets the function rendering the message of the RetryErrors
+// returned by Retry, replacing the default format. Use it to enforce log-line
+// conventions or to keep wrapped error text out of messages:
+//
+//	retrier.WithErrorFormatter(func(e *retrier.RetryError) string {
+//	    return fmt.Sprintf("retry failed (%s) after attempt %d", e.Cause, e.Attempt())
+//	})
+//
+// The formatter must not call e.Error, which would recurse. Errors.Is,
+// errors.As and the RetryError accessors are unaffected. Default is the
+// built-in format.
+func WithErrorFormatter(format func(e *RetryError) string) RetryOption {
+	return func(c *retryConfig) {
+		c.errorFormatter = format
+	}
+}
+
 // Result encapsulates the immutable outcome of a retry operation.
 // It holds either a successful value or an error, along with metadata about the execution.
 type Result[T any] struct {
-	value    T
-	err      error
-	attempts int
+	value       T
+	err         error
+	attempts    int
+	maxAttempts int
+	history     []AttemptError
+
+	// totalAttempts counts the calls of fn across nested loops, if nested
+	totalAttempts int
+
+	// decisions explain the loop, with WithExplain
+	decisions []Decision
+
+	// warnings are the errors of a degraded success, with WithSoftFail
+	warnings []error
 }
 
 // NewSuccessResult creates a Result representing a successful retry operation.
@@ -125,11 +414,64 @@ func (r Result[T]) Attempts() int {
 	return r.attempts
 }
 
+// MaxAttempts returns the attempt limit of the loop, or 0 if it had none
+// (see WithZeroAttempts).
+func (r Result[T]) MaxAttempts() int {
+	return r.maxAttempts
+}
+
+// TotalAttempts returns the number of calls of the retried function: the
+// attempts of all the inner loops under RetryNested, and Attempts otherwise.
+func (r Result[T]) TotalAttempts() int {
+	if r.totalAttempts > 0 {
+		return r.totalAttempts
+	}
+	return r.attempts
+}
+
+// History returns the errors of every failed attempt in order, annotated
+// with their attempt number and time, like Errors. Under RetryNested, each
+// carries the history of its inner loop.
+func (r Result[T]) History() []AttemptError {
+	return r.history
+}
+
+// Decisions returns why the loop retried or stopped after each failed
+// attempt, in order, when WithExplain was set; nil otherwise.
+func (r Result[T]) Decisions() []Decision {
+	return r.decisions
+}
+
+// Errors returns the errors of every failed attempt in order. It is empty
+// when the first attempt succeeded, and holds the errors of the earlier
+// attempts when a later one succeeded. Results not produced by Retry have no
+// attempt errors.
+func (r Result[T]) Errors() []error {
+	errs := make([]error, len(r.history))
+	for i, attempt := range r.history {
+		errs[i] = attempt.Err
+	}
+	return errs
+}
+
 // IsSuccess returns true if the operation succeeded (no error).
 func (r Result[T]) IsSuccess() bool {
 	return r.err == nil
 }
 
+// IsDegraded returns true if the operation succeeded with warnings: it
+// failed, but returned the usable value of an attempt (see WithSoftFail).
+func (r Result[T]) IsDegraded() bool {
+	return r.err == nil && len(r.warnings) > 0
+}
+
+// Warnings returns the non-fatal errors of a degraded success: the Degraded
+// error returned with the value, then the error the call failed with, if
+// different. It is nil unless IsDegraded.
+func (r Result[T]) Warnings() []error {
+	return r.warnings
+}
+
 // IsFailure returns true if the operation failed (has error).
 func (r Result[T]) IsFailure() bool {
 	return r.err != nil
@@ -159,9 +501,45 @@ func (r Result[T]) UnwrapOr(defaultValue T) T {
 
 // Unwrap returns the successful value or panics if failed.
 // Use only when failure is impossible or should crash.
+// The panic value is an error wrapping the Result's error, so recover sites
+// and crash reporters can inspect the full chain with errors.Is and errors.As.
 func (r Result[T]) Unwrap() T {
 	if r.err != nil {
-		panic(fmt.Sprintf("unwrap called on failed result: %v", r.err))
+		panic(fmt.Errorf("unwrap called on failed result: %w", r.err))
+	}
+	return r.value
+}
+
+// Expect returns the successful value or panics with msg if failed.
+// Like Unwrap, the panic value is an error wrapping the Result's error:
+//
+//	cfg := retrier.Retry(ctx, logger, loadConfig).Expect("config must load at startup")
+func (r Result[T]) Expect(msg string) T {
+	if r.err != nil {
+		panic(fmt.Errorf("%s: %w", msg, r.err))
 	}
 	return r.value
 }
+
+// Inspect calls fn with the value if the operation succeeded, and returns the
+// Result unchanged for chaining. Useful for call-site logging and metrics:
+//
+//	user := retrier.Retry(ctx, logger, fetchUser).
+//	    Inspect(func(u User) { cache.Put(u.ID, u) }).
+//	    InspectErr(func(err error) { metrics.Inc("fetch_user_failed") }).
+//	    UnwrapOr(guest)
+func (r Result[T]) Inspect(fn func(T)) Result[T] {
+	if r.err == nil {
+		fn(r.value)
+	}
+	return r
+}
+
+// InspectErr calls fn with the error if the operation failed, and returns the
+// Result unchanged for chaining.
+func (r Result[T]) InspectErr(fn func(error)) Result[T] {
+	if r.err != nil {
+		fn(r.err)
+	}
+	return r
+}
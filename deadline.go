@@ -0,0 +1,202 @@
+package retrier
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DeadlineSplit is how WithDeadlineSplit shares the remaining time of the
+// context among the remaining attempts.
+type DeadlineSplit int
+
+const (
+	// SplitNone gives every attempt the whole remaining time.
+	SplitNone DeadlineSplit = iota
+
+	// SplitEqual gives every attempt an equal share of the remaining time:
+	// with 3 attempts left, a third of it.
+	SplitEqual
+
+	// SplitDecaying gives earlier attempts larger shares, halving from one
+	// attempt to the next: with 3 attempts left, 4/7 of the remaining time,
+	// then 2/3 of what is left, then all of it. A first attempt that is
+	// merely slow can complete, while later attempts still get time.
+	SplitDecaying
+)
+
+// WithDeadlineSplit bounds each attempt by a share of the time left before
+// the deadline of the context, so one slow attempt cannot consume the whole
+// budget and leave none for retries. Shares are recomputed before each
+// attempt from the time actually left and the attempts remaining; backoff
+// delays come out of the shares of later attempts. An attempt exceeding its
+// share sees its context fail with context.DeadlineExceeded, which is
+// retried like any standard error.
+//
+// The split only affects functions that receive the attempt context (see
+// RetryCtx), and only when the context has a deadline. Default is SplitNone.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+//	defer cancel()
+//	result := retrier.RetryCtx(ctx, logger, callBackend,
+//	    retrier.WithMaxAttempts(3),
+//	    retrier.WithDeadlineSplit(retrier.SplitEqual), // ~1s per attempt
+//	)
+func WithDeadlineSplit(split DeadlineSplit) RetryOption {
+	return func(c *retryConfig) {
+		c.deadlineSplit = split
+	}
+}
+
+// attemptTimeout returns the share of the time left before deadline that
+// attempt gets, and false if the split does not bound it.
+func (c *retryConfig) attemptTimeout(deadline time.Time, attempt int) (time.Duration, bool) {
+	left := c.maxAttempts - attempt + 1
+	if c.deadlineSplit == SplitNone || c.unlimited() || left <= 1 {
+		return 0, false
+	}
+	remaining := deadline.Sub(c.clock.Now())
+	switch c.deadlineSplit {
+	case SplitEqual:
+		return remaining / time.Duration(left), true
+	case SplitDecaying:
+		// Shares 2^(left-1), ..., 2, 1 of 2^left - 1
+		if left > 30 {
+			return remaining / 2, true
+		}
+		return time.Duration(float64(remaining) * float64(int64(1)<<(left-1)) / float64(int64(1)<<left-1)), true
+	default:
+		return 0, false
+	}
+}
+
+// WithMaxAttemptDuration bounds each attempt at d: the attempt context fails
+// with context.DeadlineExceeded once the attempt has run for d, which is
+// retried like any standard error. Unlike WithMaxDuration, which caps the
+// backoff delay between attempts, it bounds the attempts themselves, and
+// only affects functions that receive the attempt context (see RetryCtx).
+// It must not exceed WithMaxTotalDuration. Default is none.
+//
+// Example:
+//
+//	result := retrier.RetryCtx(ctx, logger, callBackend,
+//	    retrier.WithMaxAttemptDuration(500*time.Millisecond), // per attempt
+//	    retrier.WithMaxTotalDuration(3*time.Second),          // whole call
+//	    retrier.WithMaxDuration(time.Second),                 // backoff cap
+//	)
+func WithMaxAttemptDuration(d time.Duration) RetryOption {
+	return func(c *retryConfig) {
+		c.maxAttemptDuration = d
+	}
+}
+
+// WithMaxTotalDuration bounds a whole call at d, attempts and backoff delays
+// included, as measured by the configured Clock. Each attempt context is
+// bounded by what is left of d, and the loop stops with
+// ErrTotalDurationExceeded, wrapping the last error, rather than start a
+// backoff delay that would leave no time for the next attempt. Unlike a
+// context deadline, it applies to Retry as well as RetryCtx, and starts with
+// the call. Default is none.
+func WithMaxTotalDuration(d time.Duration) RetryOption {
+	return func(c *retryConfig) {
+		c.maxTotalDuration = d
+	}
+}
+
+// WithMaxElapsedTime gives up once d has elapsed since the first attempt
+// started, however many attempts remain: after a failed attempt, the loop
+// stops with ErrElapsedTimeExceeded, wrapping the last error, rather than
+// wait a backoff delay that would end past d. Unlike WithMaxTotalDuration,
+// it never cuts a running attempt short, so a call may outlast d by the
+// length of its last attempt. Default is none.
+//
+// Example:
+//
+//	// Keep retrying for up to 2 minutes
+//	result := retrier.Retry(ctx, logger, fn,
+//	    retrier.WithMaxAttempts(100),
+//	    retrier.WithMaxElapsedTime(2*time.Minute),
+//	)
+func WithMaxElapsedTime(d time.Duration) RetryOption {
+	return func(c *retryConfig) {
+		c.maxElapsedTime = d
+	}
+}
+
+// validateDurations reports the first invalid backoff setting or
+// inconsistency between the duration limits of c, or "" if there is none.
+func (c *retryConfig) validateDurations() string {
+	switch {
+	case c.initialDuration < 0:
+		return fmt.Sprintf("initial duration %v is negative", c.initialDuration)
+	case c.maxDuration <= 0:
+		return fmt.Sprintf("max duration %v is not positive", c.maxDuration)
+	case !(c.multiplier > 0): // also rejects NaN
+		return fmt.Sprintf("multiplier %v is not positive", c.multiplier)
+	case c.maxAttemptDuration < 0:
+		return fmt.Sprintf("max attempt duration %v is negative", c.maxAttemptDuration)
+	case c.maxTotalDuration < 0:
+		return fmt.Sprintf("max total duration %v is negative", c.maxTotalDuration)
+	case c.maxElapsedTime < 0:
+		return fmt.Sprintf("max elapsed time %v is negative", c.maxElapsedTime)
+	case c.maxAttemptDuration > 0 && c.maxTotalDuration > 0 && c.maxAttemptDuration > c.maxTotalDuration:
+		return fmt.Sprintf("max attempt duration %v exceeds max total duration %v", c.maxAttemptDuration, c.maxTotalDuration)
+	}
+	return ""
+}
+
+// attemptLimit returns how long an attempt started after elapsed may run
+// under WithMaxAttemptDuration and WithMaxTotalDuration, and false if
+// neither bounds it.
+func (c *retryConfig) attemptLimit(elapsed time.Duration) (time.Duration, bool) {
+	limit, ok := c.maxAttemptDuration, c.maxAttemptDuration > 0
+	if c.maxTotalDuration > 0 {
+		left := c.maxTotalDuration - elapsed
+		if !ok || left < limit {
+			limit, ok = left, true
+		}
+	}
+	return limit, ok
+}
+
+// DeadlineUsage splits the time a retry loop spent before it gave up on its
+// context or on WithMaxTotalDuration, telling timing out while working from
+// timing out while waiting. Times are measured with the configured Clock.
+type DeadlineUsage struct {
+	// Deadline is the deadline of the context, or zero if it had none.
+	Deadline time.Time
+
+	// Elapsed is the time from the start of the loop until it gave up.
+	Elapsed time.Duration
+
+	// Working is the part of Elapsed not spent in backoff delays: in
+	// attempts, and in the bookkeeping around them.
+	Working time.Duration
+
+	// Waiting is the part of Elapsed spent in backoff delays.
+	Waiting time.Duration
+}
+
+// String describes u, such as "2s elapsed: 1.2s working, 800ms waiting".
+func (u DeadlineUsage) String() string {
+	return fmt.Sprintf("%v elapsed: %v working, %v waiting", u.Elapsed, u.Working, u.Waiting)
+}
+
+// deadlineError creates the RetryError of a loop measured by t giving up on
+// ctx or on its total duration, carrying its DeadlineUsage, which message is
+// followed by.
+func (c *retryConfig) deadlineError(ctx context.Context, t *loopTimer, history []AttemptError, cause RetryErrorCause, message string, policy RetryPolicy, wrapped error) *RetryError {
+	var usage *DeadlineUsage
+	if t.clock != nil {
+		t.wake()
+		usage = &DeadlineUsage{Elapsed: t.elapsed(), Waiting: t.backoff}
+		usage.Working = usage.Elapsed - usage.Waiting
+		usage.Deadline, _ = ctx.Deadline()
+		message = fmt.Sprintf("%s (%v)", message, usage)
+	}
+	retryErr := c.retryError(history, cause, message, policy, wrapped)
+	retryErr.Usage = usage
+	return retryErr
+}
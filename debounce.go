@@ -0,0 +1,135 @@
+package retrier
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Debouncer coalesces bursts of triggers for the same operation into a single
+// retried execution once the triggers have been quiet for a while. Use it for
+// work that many events ask for but that only needs doing once, such as
+// reloading configuration or refilling a cache after a burst of misses.
+//
+// Executions for the same key never overlap: triggers arriving while the
+// operation runs schedule one more execution after it. A Debouncer is safe for
+// concurrent use.
+type Debouncer struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	logger DebugLogger
+	quiet  time.Duration
+	onDone func(key string, err error)
+	opts   []RetryOption
+
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	entries map[string]*debounceEntry
+	closed  bool
+}
+
+type debounceEntry struct {
+	timer   *time.Timer
+	fn      func(ctx context.Context) error
+	running bool
+	pending bool
+}
+
+// NewDebouncer creates a Debouncer running operations quiet after their last
+// trigger, retried with opts under a context derived from ctx. onDone, if not
+// nil, receives the outcome of every execution.
+//
+// Example:
+//
+//	d := retrier.NewDebouncer(ctx, logger, time.Second, nil, retrier.WithMaxAttempts(5))
+//	watcher.OnChange(func() { d.Trigger("config", reloadConfig) })
+func NewDebouncer(ctx context.Context, logger DebugLogger, quiet time.Duration, onDone func(key string, err error), opts ...RetryOption) *Debouncer {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Debouncer{
+		ctx:     ctx,
+		cancel:  cancel,
+		logger:  logger,
+		quiet:   quiet,
+		onDone:  onDone,
+		opts:    opts,
+		entries: make(map[string]*debounceEntry),
+	}
+}
+
+// Trigger schedules fn to run for key once no other trigger for key arrives
+// for the quiet period. Only the fn of the last trigger of a burst runs, and
+// it receives the attempt context (see RetryCtx).
+// Triggers after Close are ignored.
+func (d *Debouncer) Trigger(key string, fn func(ctx context.Context) error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+
+	entry, ok := d.entries[key]
+	if !ok {
+		entry = &debounceEntry{}
+		entry.timer = time.AfterFunc(d.quiet, func() { d.fire(key) })
+		d.entries[key] = entry
+	} else {
+		entry.timer.Reset(d.quiet)
+	}
+	entry.fn = fn
+}
+
+// fire runs the operation of key, or defers it if the operation is running.
+func (d *Debouncer) fire(key string) {
+	d.mu.Lock()
+	entry, ok := d.entries[key]
+	if !ok || d.closed {
+		d.mu.Unlock()
+		return
+	}
+	if entry.running {
+		entry.pending = true
+		d.mu.Unlock()
+		return
+	}
+	entry.running = true
+	fn := entry.fn
+	d.wg.Add(1)
+	d.mu.Unlock()
+
+	defer d.wg.Done()
+	result := RetryCtx(d.ctx, d.logger, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, d.opts...)
+	if d.onDone != nil {
+		d.onDone(key, result.err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entry.running = false
+	if entry.pending {
+		entry.pending = false
+		entry.timer.Reset(d.quiet)
+		return
+	}
+	// Keep the entry if a trigger arrived during the run, drop it otherwise
+	if entry.timer.Stop() {
+		entry.timer.Reset(d.quiet)
+		return
+	}
+	delete(d.entries, key)
+}
+
+// Close cancels pending executions and the context of running ones, and waits
+// for running executions to return.
+func (d *Debouncer) Close() {
+	d.mu.Lock()
+	d.closed = true
+	for _, entry := range d.entries {
+		entry.timer.Stop()
+	}
+	d.mu.Unlock()
+
+	d.cancel()
+	d.wg.Wait()
+}
@@ -0,0 +1,140 @@
+package retrier
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Registry holds named Retriers for inspection. It is an http.Handler
+// rendering the state of every registered Retrier as JSON, meant to be mounted
+// at a debug path:
+//
+//	registry := retrier.NewRegistry()
+//	registry.Register("payments", paymentsRetrier)
+//	http.Handle("/debug/retrier", registry)
+//
+// A Registry is safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	retriers map[string]*Retrier
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{retriers: make(map[string]*Retrier)}
+}
+
+// Register adds r under name, replacing any Retrier registered under it.
+func (reg *Registry) Register(name string, r *Retrier) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.retriers[name] = r
+}
+
+// Unregister removes the Retrier registered under name.
+func (reg *Registry) Unregister(name string) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	delete(reg.retriers, name)
+}
+
+// Get returns the Retrier registered under name.
+func (reg *Registry) Get(name string) (*Retrier, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	r, ok := reg.retriers[name]
+	return r, ok
+}
+
+// Names returns the registered names in sorted order.
+func (reg *Registry) Names() []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	names := make([]string, 0, len(reg.retriers))
+	for name := range reg.retriers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// debugState is the JSON view of a Retrier served by Registry.
+type debugState struct {
+	Options debugOptions     `json:"options"`
+	Stats   RetrierStats     `json:"stats"`
+	Errors  map[string]int64 `json:"errors,omitempty"`
+	Budget  *BudgetStats     `json:"budget,omitempty"`
+	Guard   *debugGuard      `json:"guard,omitempty"`
+}
+
+// debugOptions renders Options with human-readable durations.
+type debugOptions struct {
+	MaxAttempts     int     `json:"maxAttempts"`
+	InitialDuration string  `json:"initialDuration"`
+	Multiplier      float64 `json:"multiplier"`
+	MaxDuration     string  `json:"maxDuration"`
+	Jitter          string  `json:"jitter"`
+	RetryPolicy     string  `json:"retryPolicy"`
+}
+
+// debugGuard renders the state of a RetryGuard.
+type debugGuard struct {
+	Active int   `json:"active"`
+	Max    int64 `json:"max"`
+}
+
+// ServeHTTP renders the options, call stats, failures by error fingerprint,
+// retry budget, and retry guard of every registered Retrier as a JSON object
+// keyed by name.
+func (reg *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	states := make(map[string]debugState)
+	for _, name := range reg.Names() {
+		r, ok := reg.Get(name)
+		if !ok {
+			continue
+		}
+
+		config := applyOptions(r.RetryOptions())
+		state := debugState{
+			Options: debugOptions{
+				MaxAttempts:     config.maxAttempts,
+				InitialDuration: config.initialDuration.String(),
+				Multiplier:      config.multiplier,
+				MaxDuration:     config.maxDuration.String(),
+				Jitter:          config.jitter.String(),
+				RetryPolicy:     policyName(config.defaultRetryPolicy),
+			},
+			Stats:  r.Stats(),
+			Errors: r.ErrorCounts(),
+		}
+		if config.budget != nil {
+			if stats, err := config.budget.Stats(req.Context()); err == nil {
+				state.Budget = &stats
+			}
+		}
+		if config.guard != nil {
+			state.Guard = &debugGuard{Active: config.guard.Active(), Max: config.guard.max}
+		}
+		states[name] = state
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(map[string]any{"retriers": states})
+}
+
+// policyName returns the name of p as used by ParsePolicy.
+func policyName(p RetryPolicy) string {
+	switch p {
+	case RetryPolicyAuto:
+		return "auto"
+	case RetryPolicyManual:
+		return "manual"
+	case RetryPolicyNever:
+		return "never"
+	}
+	return "unknown"
+}
@@ -0,0 +1,80 @@
+package retrier
+
+import (
+	"context"
+	"sync"
+)
+
+// RetryEach retries fn for every entry of items, concurrently and
+// independently: unlike a Group, a failing entry does not cancel the others.
+// At most limit entries run at the same time; a limit of 0 or less means no
+// limit. Every entry is retried with opts, so a RetryBudget, RetryGuard, or
+// Semaphore among them is shared by the whole collection.
+//
+// It returns the outcome of every entry, keyed like items: nil if fn
+// succeeded, and otherwise the error Retry returned. fn receives the
+// attempt context (see RetryCtx).
+//
+// Example:
+//
+//	errs := retrier.RetryEach(ctx, logger, 8, invoices, func(ctx context.Context, id string, inv Invoice) error {
+//	    return ledger.Post(ctx, id, inv)
+//	}, retrier.WithBudget(budget))
+//	for id, err := range errs {
+//	    if err != nil {
+//	        log.Printf("invoice %s: %v", id, err)
+//	    }
+//	}
+func RetryEach[K comparable, V any](ctx context.Context, logger DebugLogger, limit int, items map[K]V, fn func(ctx context.Context, key K, value V) error, opts ...RetryOption) map[K]error {
+	keys := make([]K, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	errs := retryEach(ctx, logger, limit, len(keys), func(ctx context.Context, i int) error {
+		return fn(ctx, keys[i], items[keys[i]])
+	}, opts)
+
+	outcomes := make(map[K]error, len(keys))
+	for i, key := range keys {
+		outcomes[key] = errs[i]
+	}
+	return outcomes
+}
+
+// RetryEachSlice is RetryEach for a slice: fn receives the index and value of
+// each element, and the outcome of element i is at index i of the returned
+// slice.
+func RetryEachSlice[V any](ctx context.Context, logger DebugLogger, limit int, items []V, fn func(ctx context.Context, i int, value V) error, opts ...RetryOption) []error {
+	return retryEach(ctx, logger, limit, len(items), func(ctx context.Context, i int) error {
+		return fn(ctx, i, items[i])
+	}, opts)
+}
+
+// retryEach retries fn for the indexes 0 to n-1, at most limit at a time, and
+// returns their errors by index.
+func retryEach(ctx context.Context, logger DebugLogger, limit, n int, fn func(ctx context.Context, i int) error, opts []RetryOption) []error {
+	errs := make([]error, n)
+	var sem chan struct{}
+	if limit > 0 {
+		sem = make(chan struct{}, limit)
+	}
+	var wg sync.WaitGroup
+	for i := range n {
+		if sem != nil {
+			sem <- struct{}{}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if sem != nil {
+				defer func() { <-sem }()
+			}
+			// Each goroutine writes only its own index
+			errs[i] = RetryCtx(ctx, logger, func(ctx context.Context) (struct{}, error) {
+				return struct{}{}, fn(ctx, i)
+			}, opts...).err
+		}()
+	}
+	wg.Wait()
+	return errs
+}
@@ -2,7 +2,9 @@
 package retrier
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -48,6 +50,85 @@ type DelaySuggestioner interface {
 	SuggestedDelay() time.Duration
 }
 
+// SeverityWeighter is an optional interface that errors can implement to
+// weigh how hard the backoff should be after them: the delay the backoff
+// computes before the next retry is multiplied by the weight. Overload
+// signals can double the delay, and connection blips halve it. A weight of
+// 0 or less counts as 1. The first SeverityWeighter in the error chain is
+// used; see also WithSeverity.
+type SeverityWeighter interface {
+	error
+
+	// SeverityWeight returns the factor scaling the next backoff delay.
+	SeverityWeight() float64
+}
+
+// Severity wraps err so that the backoff delay after it is multiplied by
+// weight (see SeverityWeighter). errors.Is and errors.As see through the
+// wrapper, but it hides a DelaySuggestioner implemented by err itself; weigh
+// such errors with WithSeverity instead. Severity returns nil if err is nil.
+//
+// Example:
+//
+//	if resp.StatusCode == http.StatusServiceUnavailable {
+//	    return nil, retrier.Severity(errOverloaded, 2) // back off twice as long
+//	}
+func Severity(err error, weight float64) error {
+	if err == nil {
+		return nil
+	}
+	return &severityError{err: err, weight: weight}
+}
+
+// severityError weighs the backoff after an error (see Severity).
+type severityError struct {
+	err    error
+	weight float64
+}
+
+func (e *severityError) Error() string { return e.err.Error() }
+
+func (e *severityError) Unwrap() error { return e.err }
+
+func (e *severityError) SeverityWeight() float64 { return e.weight }
+
+// Permanent wraps err so that Retry returns it without retrying, whatever the
+// default retry policy. errors.Is and errors.As see through the wrapper.
+// Permanent returns nil if err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// permanentError marks an error as not retryable (see Permanent).
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+func (e *permanentError) RetryPolicy() RetryPolicy { return RetryPolicyNever }
+
+// IsTransient reports whether Retry would retry err with the default options:
+// the first RetryableError in its chain has RetryPolicyAuto, or the chain has
+// none and standard errors are retried by default. It returns false for nil.
+func IsTransient(err error) bool {
+	return err != nil && shouldAutoRetry(err, defaults().defaultRetryPolicy)
+}
+
+// IsPermanent reports whether err must never be retried: the first
+// RetryableError in its chain, such as an error wrapped with Permanent, has
+// RetryPolicyNever. Errors with RetryPolicyManual are neither transient nor
+// permanent. It returns false for nil.
+func IsPermanent(err error) bool {
+	var retryErr RetryableError
+	return errors.As(err, &retryErr) && retryErr.RetryPolicy() == RetryPolicyNever
+}
+
 // RetryErrorCause represents the cause of a retry error.
 type RetryErrorCause string
 
@@ -60,6 +141,61 @@ const (
 
 	// ErrContextCancelled indicates that the context was cancelled during retry.
 	ErrContextCancelled RetryErrorCause = "context cancelled"
+
+	// ErrCoordinationDenied indicates that the retry lease for the operation
+	// is held by another instance (see WithCoordinator).
+	ErrCoordinationDenied RetryErrorCause = "coordination denied"
+
+	// ErrBudgetExhausted indicates that the retry budget did not allow another retry
+	// (see WithBudget).
+	ErrBudgetExhausted RetryErrorCause = "budget exhausted"
+
+	// ErrRetrySuppressed indicates that too many operations were already retrying
+	// (see WithRetryGuard).
+	ErrRetrySuppressed RetryErrorCause = "retry suppressed"
+
+	// ErrBackoffStopped indicates that a custom BackoffStrategy stopped retrying
+	// (see WithBackoff).
+	ErrBackoffStopped RetryErrorCause = "backoff stopped"
+
+	// ErrRetriesDisabled indicates that retries were switched off
+	// (see WithEnabledFunc).
+	ErrRetriesDisabled RetryErrorCause = "retries disabled"
+
+	// ErrRetryStopped indicates that the retry loop was aborted by a StopSignal
+	// (see WithStopSignal).
+	ErrRetryStopped RetryErrorCause = "retry stopped"
+
+	// ErrTotalBackoffExceeded indicates that the backoff delays used up the
+	// total backoff allowed (see WithMaxTotalBackoff).
+	ErrTotalBackoffExceeded RetryErrorCause = "total backoff exceeded"
+
+	// ErrTotalDurationExceeded indicates that the call used up the total
+	// duration allowed (see WithMaxTotalDuration).
+	ErrTotalDurationExceeded RetryErrorCause = "total duration exceeded"
+
+	// ErrElapsedTimeExceeded indicates that the time allowed since the first
+	// attempt ran out (see WithMaxElapsedTime).
+	ErrElapsedTimeExceeded RetryErrorCause = "elapsed time exceeded"
+
+	// ErrDeadlineWouldExceed indicates that the backoff delay before the next
+	// attempt would have ended past the deadline of the context, so the loop
+	// gave up rather than sleep until it.
+	ErrDeadlineWouldExceed RetryErrorCause = "deadline would exceed"
+
+	// ErrInvalidDurations indicates that a backoff setting is out of range,
+	// such as a negative WithInitialDuration, or that the duration limits
+	// contradict each other (see WithMaxAttemptDuration).
+	ErrInvalidDurations RetryErrorCause = "invalid durations"
+
+	// ErrResultTypeMismatch indicates that the type parameter of
+	// WithRetryOnResult cannot hold the results of the retried function, so
+	// the check could never run.
+	ErrResultTypeMismatch RetryErrorCause = "result type mismatch"
+
+	// ErrUnclassified indicates that an attempt failed with an error that was
+	// not explicitly classified (see WithStrictClassification).
+	ErrUnclassified RetryErrorCause = "unclassified error"
 )
 
 // RetryError represents an error that occurred during retry attempts.
@@ -67,8 +203,67 @@ const (
 type RetryError struct {
 	Message string
 	Cause   RetryErrorCause
-	wrapped error       // Original error that caused the retry failure
-	policy  RetryPolicy // Cached policy for interface method
+
+	// Usage splits the time the loop spent between attempts and backoff
+	// delays when it gave up on its context (ErrContextCancelled),
+	// WithMaxTotalDuration (ErrTotalDurationExceeded), WithMaxElapsedTime
+	// (ErrElapsedTimeExceeded), or its deadline (ErrDeadlineWouldExceed), and
+	// is nil otherwise or when the context
+	// could not be cancelled.
+	Usage *DeadlineUsage
+
+	wrapped error          // Original error that caused the retry failure
+	policy  RetryPolicy    // Cached policy for interface method
+	groups  []ErrorGroup   // Attempt errors grouped by fingerprint
+	history []AttemptError // Attempt errors in order
+
+	formatter func(*RetryError) string // Custom Error() format, see WithErrorFormatter
+	redactor  Redactor                 // Applied to the Error() message, see WithRedactor
+}
+
+// AttemptError is the error of one attempt of a Retry call.
+type AttemptError struct {
+	// Attempt is the attempt number (1-based).
+	Attempt int
+
+	// Time is when the attempt failed.
+	Time time.Time
+
+	// Err is the error the attempt returned.
+	Err error
+
+	// Cause is why the attempt context was cancelled while the attempt ran,
+	// such as the error of a StopSignal or a timeout of WithDeadlineSplit or
+	// WithMaxAttemptDuration, when it is more than a bare context.Canceled or
+	// context.DeadlineExceeded (see context.Cause), and nil otherwise.
+	Cause error
+
+	// Inner is the history of the inner loop making up the attempt under
+	// RetryNested, and nil otherwise.
+	Inner []AttemptError
+}
+
+// Error returns the attempt error annotated with its attempt number.
+func (e AttemptError) Error() string {
+	return fmt.Sprintf("attempt %d: %v", e.Attempt, e.Err)
+}
+
+// Unwrap returns the error the attempt returned.
+func (e AttemptError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorGroup counts the attempt errors of a Retry call that share a fingerprint
+// (see WithFingerprinter).
+type ErrorGroup struct {
+	// Fingerprint identifies the errors of the group.
+	Fingerprint string
+
+	// Count is the number of attempts that failed with an error of the group.
+	Count int
+
+	// Err is the most recent error of the group.
+	Err error
 }
 
 // NewRetryError creates a new RetryError with explicit classification.
@@ -87,11 +282,76 @@ func NewRetryError(cause RetryErrorCause, message string, policy RetryPolicy, wr
 }
 
 // Error returns the error message implementing the error interface.
+// When the wrapped error comes from an attempt, its attempt number follows it.
+// When several attempts failed, the message ends with a summary of the attempt
+// errors grouped by fingerprint, such as "[connection refused ×4, timeout ×1]".
+//
+// A formatter set with WithErrorFormatter replaces this format, and a
+// Redactor set with WithRedactor rewrites the result.
 func (e *RetryError) Error() string {
+	msg := e.message()
+	if e.redactor != nil {
+		msg = e.redactor(msg)
+	}
+	return msg
+}
+
+// message renders e with its formatter or the default format.
+func (e *RetryError) message() string {
+	if e.formatter != nil {
+		return e.formatter(e)
+	}
+	msg := fmt.Sprintf("retry error: %s, %s", e.Cause, e.Message)
 	if e.wrapped != nil {
-		return fmt.Sprintf("retry error: %s, %s: %v", e.Cause, e.Message, e.wrapped)
+		msg = fmt.Sprintf("%s: %v", msg, e.wrapped)
+		if attempt := e.Attempt(); attempt > 0 {
+			msg = fmt.Sprintf("%s (attempt %d)", msg, attempt)
+		}
+	}
+	if summary := e.groupSummary(); summary != "" {
+		msg += " [" + summary + "]"
+	}
+	return msg
+}
+
+// History returns the errors of every failed attempt that led to e, in order,
+// annotated with their attempt number and time. It is empty for RetryErrors
+// not produced by Retry.
+func (e *RetryError) History() []AttemptError {
+	return e.history
+}
+
+// Attempt returns the number of the attempt that produced the wrapped error,
+// or 0 if the wrapped error does not come from an attempt (for example, a
+// context error) or e was not produced by Retry.
+func (e *RetryError) Attempt() int {
+	if n := len(e.history); n > 0 && errors.Is(e.wrapped, e.history[n-1].Err) {
+		return e.history[n-1].Attempt
+	}
+	return 0
+}
+
+// Groups returns the attempt errors that led to e grouped by fingerprint, in
+// order of first occurrence. It is empty for RetryErrors not produced by Retry.
+func (e *RetryError) Groups() []ErrorGroup {
+	return e.groups
+}
+
+// groupSummary renders the groups as "fingerprint ×count" items, or "" when
+// fewer than two attempts failed.
+func (e *RetryError) groupSummary() string {
+	total := 0
+	for _, g := range e.groups {
+		total += g.Count
+	}
+	if total < 2 {
+		return ""
+	}
+	items := make([]string, len(e.groups))
+	for i, g := range e.groups {
+		items[i] = fmt.Sprintf("%s ×%d", g.Fingerprint, g.Count)
 	}
-	return fmt.Sprintf("retry error: %s, %s", e.Cause, e.Message)
+	return strings.Join(items, ", ")
 }
 
 // Unwrap returns the wrapped error for error chain support.
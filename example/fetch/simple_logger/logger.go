@@ -27,8 +27,8 @@ func (l *SimpleLogger) Enabled() bool {
 // LogRetry prints retry information to stdout.
 // It shows the attempt number, max attempts, backoff duration, error (if any),
 // and any additional attributes passed via WithLogAttrs.
-func (l *SimpleLogger) LogRetry(_ context.Context, attempt, maxAttempts int, backoff time.Duration, err error, attrs ...any) {
-	timestamp := time.Now().Format("15:04:05.000")
+func (l *SimpleLogger) LogRetry(ctx context.Context, attempt, maxAttempts int, backoff time.Duration, err error, attrs ...any) {
+	timestamp := retrier.LogTime(ctx).Format("15:04:05.000")
 
 	// Format attrs as key=value pairs
 	var attrStr string
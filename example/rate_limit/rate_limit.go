@@ -7,10 +7,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/httpretry"
 )
 
 // RateLimitError implements both retrier.RetryableError and retrier.DelaySuggestioner.
@@ -37,24 +37,6 @@ func (e *RateLimitError) SuggestedDelay() time.Duration {
 	return e.RetryAfter
 }
 
-// parseRetryAfter parses the Retry-After header value.
-// It supports both delta-seconds and HTTP-date formats.
-// For simplicity, this example only handles delta-seconds.
-func parseRetryAfter(value string) time.Duration {
-	if value == "" {
-		return 0
-	}
-
-	// Try parsing as seconds (delta-seconds format)
-	if secs, err := strconv.Atoi(value); err == nil {
-		return time.Duration(secs) * time.Second
-	}
-
-	// TODO: Handle HTTP-date format if needed
-	// Example: "Fri, 31 Dec 1999 23:59:59 GMT"
-	return 0
-}
-
 // SimpleLogger is a minimal logger for demonstration
 type SimpleLogger struct{}
 
@@ -104,7 +86,7 @@ func main() {
 			body, _ := io.ReadAll(resp.Body)
 			resp.Body.Close()
 
-			retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+			retryAfter, _ := httpretry.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
 			return nil, &RateLimitError{
 				StatusCode: resp.StatusCode,
 				RetryAfter: retryAfter,
@@ -0,0 +1,73 @@
+// Package execretry runs external commands with retries.
+//
+// An exec.Cmd can only run once, so Run takes a function creating a fresh
+// command for each attempt:
+//
+//	result := execretry.Run(ctx, logger, execretry.Command("pg_isready", "-h", host),
+//	    retrier.WithMaxAttempts(10),
+//	)
+//	if code, ok := execretry.ExitCode(result.Err()); ok {
+//	    os.Exit(code)
+//	}
+package execretry
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"slices"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// Run runs the command newCmd creates, creating a fresh one for each attempt,
+// until it exits with status 0. Non-zero exit statuses are retried like
+// standard errors; commands that cannot be started because they are missing
+// or not executable fail right away. newCmd receives the context of the
+// attempt and should bind the command to it (see exec.CommandContext).
+func Run(ctx context.Context, logger retrier.DebugLogger, newCmd func(ctx context.Context) *exec.Cmd, opts ...retrier.RetryOption) retrier.Result[struct{}] {
+	return retrier.RetryCtx(ctx, logger, func(ctx context.Context) (struct{}, error) {
+		err := newCmd(ctx).Run()
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+			return struct{}{}, retrier.Permanent(err)
+		}
+		return struct{}{}, err
+	}, opts...)
+}
+
+// Command returns a newCmd function for Run running name with args, bound to
+// the context of the attempt, with the standard output and error of the
+// current process. Standard input is not connected, since it cannot be
+// replayed across attempts.
+func Command(name string, args ...string) func(ctx context.Context) *exec.Cmd {
+	return func(ctx context.Context) *exec.Cmd {
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd
+	}
+}
+
+// ExitCode returns the exit status carried by err, and false if err does not
+// come from a command that exited.
+func ExitCode(err error) (int, bool) {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() < 0 {
+		return 0, false
+	}
+	return exitErr.ExitCode(), true
+}
+
+// RetryIfExitCode returns a predicate for retrier.WithRetryIf that does not
+// retry commands exiting with one of the permanent statuses, such as a usage
+// error, and retries the other errors retrier.IsTransient reports transient.
+func RetryIfExitCode(permanent ...int) func(err error) bool {
+	return func(err error) bool {
+		if code, ok := ExitCode(err); ok && slices.Contains(permanent, code) {
+			return false
+		}
+		return retrier.IsTransient(err)
+	}
+}
@@ -0,0 +1,97 @@
+package retrier
+
+import (
+	"context"
+	"time"
+)
+
+// Exemplar links a retry metric to an example call, through the trace the
+// call belonged to, so dashboards can jump from a spike of failures to
+// traces of failing calls (see WithExemplars).
+type Exemplar struct {
+	// TraceID identifies the trace of the call.
+	TraceID string
+
+	// Value is what the call added to the metric: 1 for a failure, or the
+	// number of retries it made.
+	Value int64
+
+	// Time is when the call returned, according to the configured Clock.
+	Time time.Time
+}
+
+// RetrierExemplars holds the latest Exemplar of each failure metric of a
+// Retrier. A nil or missing Exemplar means no traced call added to the
+// metric yet.
+type RetrierExemplars struct {
+	// Failures is the latest traced call that returned an error.
+	Failures *Exemplar
+
+	// Retries is the latest traced call that made retries.
+	Retries *Exemplar
+
+	// Errors holds the latest traced failure by error fingerprint, like
+	// Retrier.ErrorCounts.
+	Errors map[string]Exemplar
+}
+
+// WithExemplars makes calls through a Retrier (see Do) record the trace
+// they belong to as the exemplar of the failure metrics they add to, for
+// Retrier.Exemplars and Registry.WriteOpenMetrics. traceID returns the ID of
+// the trace of the context of the call, or "" if it has none. Only the
+// latest exemplar of each metric is kept. Plain Retry calls record nothing.
+// Default is none.
+//
+// Example:
+//
+//	// With OpenTelemetry
+//	retrier.WithExemplars(func(ctx context.Context) string {
+//	    if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+//	        return sc.TraceID().String()
+//	    }
+//	    return ""
+//	})
+func WithExemplars(traceID func(ctx context.Context) string) RetryOption {
+	return func(c *retryConfig) {
+		c.traceID = traceID
+	}
+}
+
+// Exemplars returns the latest exemplars of the failure metrics of r.
+func (r *Retrier) Exemplars() RetrierExemplars {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	exemplars := RetrierExemplars{
+		Failures: r.exemplars.Failures,
+		Retries:  r.exemplars.Retries,
+		Errors:   make(map[string]Exemplar, len(r.exemplars.Errors)),
+	}
+	for fingerprint, e := range r.exemplars.Errors {
+		exemplars.Errors[fingerprint] = e
+	}
+	return exemplars
+}
+
+// recordExemplars records the trace of a call run with ctx, which returned
+// err, fingerprinted as fingerprint, after attempts, as the exemplar of the
+// metrics it added to. r.mu must be held.
+func (r *Retrier) recordExemplars(ctx context.Context, config *retryConfig, attempts int, err error, fingerprint string) {
+	if config.traceID == nil {
+		return
+	}
+	traceID := config.traceID(ctx)
+	if traceID == "" {
+		return
+	}
+	now := config.clock.Now()
+	if err != nil {
+		r.exemplars.Failures = &Exemplar{TraceID: traceID, Value: 1, Time: now}
+		if r.exemplars.Errors == nil {
+			r.exemplars.Errors = make(map[string]Exemplar)
+		}
+		r.exemplars.Errors[fingerprint] = Exemplar{TraceID: traceID, Value: 1, Time: now}
+	}
+	if attempts > 1 {
+		r.exemplars.Retries = &Exemplar{TraceID: traceID, Value: int64(attempts - 1), Time: now}
+	}
+}
@@ -0,0 +1,152 @@
+package retrier
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// Experiment splits calls between two Retriers, a control and a treatment,
+// and records comparable outcomes for each, so a retry policy can be tuned
+// against production traffic instead of by guess-and-redeploy.
+// An Experiment is safe for concurrent use.
+type Experiment struct {
+	control   *Retrier
+	treatment *Retrier
+	percent   float64
+
+	arms [2]armCounters
+}
+
+// armCounters accumulates the outcomes of one arm of an Experiment.
+type armCounters struct {
+	calls        atomic.Int64
+	successes    atomic.Int64
+	attempts     atomic.Int64
+	addedLatency atomic.Int64
+}
+
+// ArmStats reports the outcomes of one arm of an Experiment.
+type ArmStats struct {
+	// Calls is the number of calls routed to the arm.
+	Calls int64 `json:"calls"`
+
+	// Successes is the number of calls that returned a value.
+	Successes int64 `json:"successes"`
+
+	// Attempts is the total number of attempts made.
+	Attempts int64 `json:"attempts"`
+
+	// AddedLatency is the total time spent after the first attempt of each
+	// call, that is, the latency added by retrying.
+	AddedLatency time.Duration `json:"addedLatency"`
+}
+
+// SuccessRate returns the fraction of calls that succeeded, or 0 without calls.
+func (s ArmStats) SuccessRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Successes) / float64(s.Calls)
+}
+
+// MeanAttempts returns the average number of attempts per call, or 0 without calls.
+func (s ArmStats) MeanAttempts() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Attempts) / float64(s.Calls)
+}
+
+// MeanAddedLatency returns the average latency added by retrying per call,
+// or 0 without calls.
+func (s ArmStats) MeanAddedLatency() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.AddedLatency / time.Duration(s.Calls)
+}
+
+// ExperimentStats reports the outcomes of both arms of an Experiment.
+type ExperimentStats struct {
+	Control   ArmStats `json:"control"`
+	Treatment ArmStats `json:"treatment"`
+}
+
+// NewExperiment creates an Experiment routing percent (0 to 100) of calls to
+// treatment and the rest to control.
+func NewExperiment(control, treatment *Retrier, percent float64) *Experiment {
+	return &Experiment{
+		control:   control,
+		treatment: treatment,
+		percent:   min(max(percent, 0), 100),
+	}
+}
+
+// RunExperiment runs fn through one arm of e. With an empty key, the arm is
+// chosen at random; otherwise it is derived from a hash of key, so the same
+// key (a user or tenant ID, say) always lands in the same arm.
+func RunExperiment[T any](ctx context.Context, e *Experiment, key string, fn func() (T, error), opts ...RetryOption) Result[T] {
+	arm := 0
+	r := e.control
+	if e.inTreatment(key) {
+		arm = 1
+		r = e.treatment
+	}
+
+	// Latencies follow the Clock of the arm, so simulations stay in virtual time
+	config := newConfig(r.callOptions(opts))
+	clock := config.clock
+	start := clock.Now()
+	var firstAttempt time.Duration
+	attempted := false
+	timed := func() (T, error) {
+		if attempted {
+			return fn()
+		}
+		attempted = true
+		defer func() { firstAttempt = clock.Now().Sub(start) }()
+		return fn()
+	}
+
+	result := doConfig(ctx, r, timed, &config)
+
+	counters := &e.arms[arm]
+	counters.calls.Add(1)
+	if result.err == nil {
+		counters.successes.Add(1)
+	}
+	counters.attempts.Add(int64(result.attempts))
+	if attempted {
+		counters.addedLatency.Add(int64(clock.Now().Sub(start) - firstAttempt))
+	}
+	return result
+}
+
+// inTreatment reports whether a call with key goes to the treatment arm.
+func (e *Experiment) inTreatment(key string) bool {
+	fraction := rand.Float64()
+	if key != "" {
+		fraction = keyFraction(key)
+	}
+	return fraction*100 < e.percent
+}
+
+// Stats returns the outcomes recorded for both arms.
+func (e *Experiment) Stats() ExperimentStats {
+	return ExperimentStats{
+		Control:   e.arms[0].stats(),
+		Treatment: e.arms[1].stats(),
+	}
+}
+
+// stats returns a snapshot of c.
+func (c *armCounters) stats() ArmStats {
+	return ArmStats{
+		Calls:        c.calls.Load(),
+		Successes:    c.successes.Load(),
+		Attempts:     c.attempts.Load(),
+		AddedLatency: time.Duration(c.addedLatency.Load()),
+	}
+}
@@ -0,0 +1,205 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DecisionRule identifies the rule behind a Decision.
+type DecisionRule string
+
+const (
+	// RuleRetryIf is the predicate set with WithRetryIf.
+	RuleRetryIf DecisionRule = "retry_if"
+
+	// RuleErrorPolicy is the RetryPolicy of a RetryableError in the error chain.
+	RuleErrorPolicy DecisionRule = "error_policy"
+
+	// RuleDefaultPolicy is the policy applied to standard errors (see
+	// WithDefaultPolicy).
+	RuleDefaultPolicy DecisionRule = "default_policy"
+
+	// RuleMaxAttempts is the attempt limit of WithMaxAttempts.
+	RuleMaxAttempts DecisionRule = "max_attempts"
+
+	// RuleContext is the context of the loop being done.
+	RuleContext DecisionRule = "context"
+
+	// RuleStopSignal is the StopSignal of WithStopSignal.
+	RuleStopSignal DecisionRule = "stop_signal"
+
+	// RuleDisabled is the switch of WithEnabledFunc.
+	RuleDisabled DecisionRule = "disabled"
+
+	// RuleRetryGuard is the RetryGuard of WithRetryGuard.
+	RuleRetryGuard DecisionRule = "retry_guard"
+
+	// RuleBudget is the RetryBudget of WithBudget.
+	RuleBudget DecisionRule = "budget"
+
+	// RuleCoordination is the retry lease of WithCoordinator.
+	RuleCoordination DecisionRule = "coordination"
+
+	// RuleBackoffStopped is the BackoffStrategy of WithBackoff stopping.
+	RuleBackoffStopped DecisionRule = "backoff_stopped"
+
+	// RuleMaxTotalBackoff is the cap of WithMaxTotalBackoff.
+	RuleMaxTotalBackoff DecisionRule = "max_total_backoff"
+
+	// RuleMaxTotalDuration is the limit of WithMaxTotalDuration, or the
+	// duration limits contradicting each other.
+	RuleMaxTotalDuration DecisionRule = "max_total_duration"
+
+	// RuleMaxElapsedTime is the limit of WithMaxElapsedTime.
+	RuleMaxElapsedTime DecisionRule = "max_elapsed_time"
+
+	// RuleDeadline is the deadline of the context, which the backoff delay
+	// would pass.
+	RuleDeadline DecisionRule = "deadline"
+
+	// RuleStrict is the StrictFail mode of WithStrictClassification.
+	RuleStrict DecisionRule = "strict"
+
+	// RuleResultCheck is the check of WithRetryOnResult, whose type does not
+	// match the results of the retried function.
+	RuleResultCheck DecisionRule = "result_check"
+)
+
+// Decision records why a retry loop retried or stopped after a failed
+// attempt, as recorded by WithExplain.
+type Decision struct {
+	// Attempt is the number of the failed attempt the decision follows, or 0
+	// for decisions made before the first attempt.
+	Attempt int
+
+	// Retry is true if the loop decided to retry.
+	Retry bool
+
+	// Rule is the rule that decided: for retries, the one classifying the
+	// error as retryable; for stops, the one that stopped the loop.
+	Rule DecisionRule
+
+	// Reason explains the decision in words, with the state the rule saw.
+	Reason string
+
+	// Delay is the backoff delay before the next attempt, for retries.
+	Delay time.Duration
+
+	// DelaySource is where Delay came from, for retries: "fast" (see
+	// WithFastRetries), "server" (a DelaySuggestioner), "strategy" (see
+	// WithBackoff), or "backoff" (the exponential curve).
+	DelaySource string
+}
+
+// String returns the decision as a log line.
+func (d Decision) String() string {
+	if d.Retry {
+		return fmt.Sprintf("attempt %d: retry in %v (%s delay) by %s: %s", d.Attempt, d.Delay, d.DelaySource, d.Rule, d.Reason)
+	}
+	return fmt.Sprintf("attempt %d: stop by %s: %s", d.Attempt, d.Rule, d.Reason)
+}
+
+// WithExplain records a Decision each time the loop retries or stops after
+// a failed attempt, saying which rule fired and why. The decisions are
+// available from Result.Decisions, and each RetryEvent of a retry or a
+// failure carries its decision. Use it to find out why a call did not retry
+// when it should have. Default is off.
+//
+// Example:
+//
+//	result := retrier.Retry(ctx, logger, fn, retrier.WithExplain())
+//	for _, d := range result.Decisions() {
+//	    log.Println(d) // attempt 2: stop by budget: retry budget exhausted (50 retries for 400 requests, ratio 0.1, min 10 retries)
+//	}
+func WithExplain() RetryOption {
+	return func(c *retryConfig) {
+		c.explain = true
+	}
+}
+
+// decisionLog collects the decisions of a loop. Its zero value, used without
+// WithExplain, records nothing.
+type decisionLog struct {
+	enabled   bool
+	decisions []Decision
+}
+
+// stop records that the loop stopped after attempt by rule.
+func (l *decisionLog) stop(attempt int, rule DecisionRule, format string, args ...any) {
+	if l.enabled {
+		l.decisions = append(l.decisions, Decision{Attempt: attempt, Rule: rule, Reason: fmt.Sprintf(format, args...)})
+	}
+}
+
+// notRetried records that the loop stopped because c does not retry err.
+func (l *decisionLog) notRetried(c *retryConfig, attempt int, err error) {
+	if l.enabled {
+		rule, reason := c.classification(err, false)
+		l.decisions = append(l.decisions, Decision{Attempt: attempt, Rule: rule, Reason: reason})
+	}
+}
+
+// retry records that the loop retries err after attempt, waiting delay.
+func (l *decisionLog) retry(c *retryConfig, attempt int, err error, delay time.Duration) {
+	if l.enabled {
+		rule, reason := c.classification(err, true)
+		l.decisions = append(l.decisions, Decision{
+			Attempt:     attempt,
+			Retry:       true,
+			Rule:        rule,
+			Reason:      reason,
+			Delay:       delay,
+			DelaySource: c.delaySource(attempt, err),
+		})
+	}
+}
+
+// last returns the latest decision, or nil if none was recorded.
+func (l *decisionLog) last() *Decision {
+	if len(l.decisions) == 0 {
+		return nil
+	}
+	return &l.decisions[len(l.decisions)-1]
+}
+
+// classification returns the rule by which shouldRetry decided retry for
+// err, and why, without calling the WithRetryIf predicate again.
+func (c *retryConfig) classification(err error, retry bool) (rule DecisionRule, reason string) {
+	if c.retryIf != nil {
+		return RuleRetryIf, fmt.Sprintf("the WithRetryIf predicate returned %t", retry)
+	}
+	var retryErr RetryableError
+	if errors.As(err, &retryErr) {
+		return RuleErrorPolicy, fmt.Sprintf("the error chain has a %T with policy %s", retryErr, policyName(retryErr.RetryPolicy()))
+	}
+	return RuleDefaultPolicy, fmt.Sprintf("standard error with default policy %s", policyName(c.defaultRetryPolicy))
+}
+
+// delaySource names where the delay before retry following err comes from.
+func (c *retryConfig) delaySource(retry int, err error) string {
+	var suggested time.Duration
+	if ds, ok := err.(DelaySuggestioner); ok {
+		suggested = ds.SuggestedDelay()
+	}
+	switch {
+	case retry <= c.fastRetries && suggested <= c.fastDelay:
+		return "fast"
+	case suggested > 0:
+		return "server"
+	case c.backoff != nil:
+		return "strategy"
+	default:
+		return "backoff"
+	}
+}
+
+// budgetState describes the counters of b, for the reason of a decision.
+func budgetState(ctx context.Context, b *RetryBudget) string {
+	stats, err := b.Stats(ctx)
+	if err != nil {
+		return fmt.Sprintf("retry budget exhausted (stats unavailable: %v)", err)
+	}
+	return fmt.Sprintf("retry budget exhausted (%d retries for %d requests, ratio %g, min %d retries)", stats.Retries, stats.Requests, b.ratio, b.minRetries)
+}
@@ -0,0 +1,88 @@
+package retrier
+
+import (
+	"errors"
+	"regexp"
+)
+
+// Fingerprinter maps an error to a low-cardinality identity, so that errors
+// differing only in IDs, addresses, or counts share one metric label or
+// summary line.
+type Fingerprinter func(err error) string
+
+// WithFingerprinter sets the Fingerprinter used to identify errors in
+// statistics and summaries. Default is DefaultFingerprint.
+func WithFingerprinter(f Fingerprinter) RetryOption {
+	return func(c *retryConfig) {
+		c.fingerprinter = f
+	}
+}
+
+// fingerprintRules replace the dynamic parts of error messages, in order.
+var fingerprintRules = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`"[^"]*"`), `"<q>"`},
+	{regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), "<uuid>"},
+	{regexp.MustCompile(`\[[0-9a-fA-F:]*:[0-9a-fA-F:.]*\](:\d+)?`), "<addr>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<addr>"},
+	{regexp.MustCompile(`\b(?:0x[0-9a-fA-F]+|[0-9a-fA-F]*[a-fA-F][0-9a-fA-F]*\d[0-9a-fA-F]*|[0-9a-fA-F]*\d[0-9a-fA-F]*[a-fA-F][0-9a-fA-F]*)\b`), "<hex>"},
+	{regexp.MustCompile(`\d+`), "<n>"},
+}
+
+// DefaultFingerprint returns the message of err with its dynamic parts
+// collapsed: quoted strings become "<q>", UUIDs <uuid>, IP addresses with
+// optional ports <addr>, hexadecimal IDs <hex>, and remaining numbers <n>.
+// For example, "dial tcp 10.0.0.7:5432: connection refused" becomes
+// "dial tcp <addr>: connection refused".
+func DefaultFingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	msg := err.Error()
+	for _, rule := range fingerprintRules {
+		msg = rule.pattern.ReplaceAllString(msg, rule.replacement)
+	}
+	return msg
+}
+
+// attemptError returns the error of the last attempt behind err, which may
+// be a RetryError produced by Retry.
+func attemptError(err error) error {
+	var retryErr *RetryError
+	if errors.As(err, &retryErr) && retryErr.wrapped != nil {
+		return retryErr.wrapped
+	}
+	return err
+}
+
+// retryError creates a RetryError carrying the attempt history, also grouped
+// by fingerprint.
+func (c *retryConfig) retryError(history []AttemptError, cause RetryErrorCause, message string, policy RetryPolicy, wrapped error) *RetryError {
+	retryErr := NewRetryError(cause, message, policy, wrapped)
+	retryErr.history = history
+	retryErr.groups = groupErrors(history, c.fingerprint)
+	retryErr.formatter = c.errorFormatter
+	retryErr.redactor = c.redactor
+	return retryErr
+}
+
+// groupErrors groups the errors of history by fingerprint in order of first occurrence.
+func groupErrors(history []AttemptError, fingerprinter Fingerprinter) []ErrorGroup {
+	var groups []ErrorGroup
+	index := make(map[string]int)
+	for _, attempt := range history {
+		err := attempt.Err
+		fingerprint := fingerprinter(err)
+		i, ok := index[fingerprint]
+		if !ok {
+			i = len(groups)
+			index[fingerprint] = i
+			groups = append(groups, ErrorGroup{Fingerprint: fingerprint})
+		}
+		groups[i].Count++
+		groups[i].Err = err
+	}
+	return groups
+}
@@ -0,0 +1,41 @@
+package retrier
+
+import "time"
+
+// FlagSet is the subset of *flag.FlagSet used by BindFlags.
+// *pflag.FlagSet from github.com/spf13/pflag satisfies it as well.
+type FlagSet interface {
+	IntVar(p *int, name string, value int, usage string)
+	Float64Var(p *float64, name string, value float64, usage string)
+	DurationVar(p *time.Duration, name string, value time.Duration, usage string)
+}
+
+// BindFlags registers the standard retry flags on fs and returns the Options
+// they fill in when fs is parsed. With prefix "retry", the flags are:
+//
+//	-retry-max-attempts  maximum number of attempts
+//	-retry-initial       initial backoff delay
+//	-retry-multiplier    backoff multiplier
+//	-retry-max           maximum backoff delay
+//	-retry-jitter        maximum random delay added to each backoff
+//
+// An empty prefix registers the flags without one. Flag defaults are the
+// settings resolved from defaults, so tools can ship their own defaults.
+//
+// Example:
+//
+//	retryOpts := retrier.BindFlags(flag.CommandLine, "retry")
+//	flag.Parse()
+//	result := retrier.Retry(ctx, logger, fn, retryOpts.RetryOptions()...)
+func BindFlags(fs FlagSet, prefix string, defaults ...RetryOption) *Options {
+	o := ResolveOptions(defaults...)
+	if prefix != "" {
+		prefix += "-"
+	}
+	fs.IntVar(&o.MaxAttempts, prefix+"max-attempts", o.MaxAttempts, "maximum number of attempts")
+	fs.DurationVar(&o.InitialDuration, prefix+"initial", o.InitialDuration, "initial backoff delay")
+	fs.Float64Var(&o.Multiplier, prefix+"multiplier", o.Multiplier, "backoff multiplier")
+	fs.DurationVar(&o.MaxDuration, prefix+"max", o.MaxDuration, "maximum backoff delay")
+	fs.DurationVar(&o.Jitter, prefix+"jitter", o.Jitter, "maximum random delay added to each backoff")
+	return &o
+}
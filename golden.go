@@ -0,0 +1,273 @@
+package retrier
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// describeMaxRetries is the number of retries the schedule of a policy
+// description lists.
+const describeMaxRetries = 20
+
+// DescribePolicy returns a canonical description of the policy opts
+// configure: its attempt limits, backoff schedule, caps, classification
+// rules, and the limits shared with other calls. The description is
+// deterministic: the jittered delays of the schedule are drawn from seed,
+// and functions such as predicates are only reported as set. Check it into
+// a golden file (see WriteGoldenPolicy) to review retry behavior changes as
+// data rather than as option diffs.
+//
+// The schedule lists the first 20 retries of a call failing with standard
+// errors, with the range jitter spreads each delay over and a sample drawn
+// from seed.
+func DescribePolicy(seed uint64, opts ...RetryOption) string {
+	c := newConfig(opts)
+	var b strings.Builder
+	fmt.Fprintf(&b, "retrier policy\nseed: %d\n", seed)
+
+	section := func(name string, fields ...string) {
+		fmt.Fprintf(&b, "\n%s\n", name)
+		for i := 0; i+1 < len(fields); i += 2 {
+			fmt.Fprintf(&b, "  %s: %s\n", fields[i], fields[i+1])
+		}
+	}
+
+	softMax := "none"
+	if c.softMaxAttempts > 0 {
+		softMax = fmt.Sprint(c.softMaxAttempts)
+	}
+	maxAttempts := fmt.Sprint(c.maxAttempts)
+	if c.unlimited() {
+		maxAttempts = "unlimited"
+	}
+	section("attempts",
+		"max_attempts", maxAttempts,
+		"soft_max_attempts", softMax,
+	)
+
+	curve := "exponential"
+	switch b := c.backoff.(type) {
+	case constantBackoff:
+		curve = fmt.Sprintf("constant %v", time.Duration(b))
+	case nil:
+		if c.linearIncrement > 0 {
+			curve = fmt.Sprintf("linear +%v", c.linearIncrement)
+		}
+	default:
+		curve = fmt.Sprintf("strategy %T", c.backoff)
+	}
+	fast := "none"
+	if c.fastRetries > 0 {
+		fast = fmt.Sprintf("%d at %v", c.fastRetries, c.fastDelay)
+	}
+	instance := "none"
+	if c.instanceKey != "" {
+		instance = fmt.Sprintf("%q (first delay shifted by %v)", c.instanceKey, instancePhase(c.instanceKey, min(c.initialDuration, c.maxDuration)))
+	}
+	maxTotal := "none"
+	if c.maxTotalBackoff > 0 {
+		maxTotal = c.maxTotalBackoff.String()
+	}
+	section("backoff",
+		"curve", curve,
+		"initial_duration", c.initialDuration.String(),
+		"multiplier", fmt.Sprint(c.multiplier),
+		"max_duration", c.maxDuration.String(),
+		"jitter", c.jitter.String(),
+		"jitter_mode", c.jitterMode.String(),
+		"fast_retries", fast,
+		"instance_key", instance,
+		"max_total_backoff", maxTotal,
+		"severity", describeSet(c.severity != nil),
+	)
+
+	softFail := "off"
+	if c.softFail {
+		softFail = "on"
+	}
+	section("classification",
+		"retry_if", describeSet(c.retryIf != nil),
+		"default_policy", policyName(c.defaultRetryPolicy),
+		"retry_on_result", describeSet(c.resultCheck != nil),
+		"sampled_validation", describeValidation(c.validation),
+		"strict", c.strict.String(),
+		"soft_fail", softFail,
+	)
+
+	budget := "none"
+	if c.budget != nil {
+		budget = fmt.Sprintf("ratio %g, min %d retries, window %v", c.budget.ratio, c.budget.minRetries, c.budget.window)
+	}
+	guard := "none"
+	if c.guard != nil {
+		guard = fmt.Sprintf("max %d", c.guard.max)
+	}
+	coordinator := "none"
+	if c.coordination != nil {
+		coordinator = fmt.Sprintf("key %q, ttl %v", c.coordination.key, c.coordination.ttl)
+	}
+	attemptLimit, totalLimit, elapsedLimit := "none", "none", "none"
+	if c.maxAttemptDuration != 0 {
+		attemptLimit = c.maxAttemptDuration.String()
+	}
+	if c.maxTotalDuration != 0 {
+		totalLimit = c.maxTotalDuration.String()
+	}
+	if c.maxElapsedTime != 0 {
+		elapsedLimit = c.maxElapsedTime.String()
+	}
+	section("limits",
+		"max_attempt_duration", attemptLimit,
+		"max_total_duration", totalLimit,
+		"max_elapsed_time", elapsedLimit,
+		"budget", budget,
+		"retry_guard", guard,
+		"coordinator", coordinator,
+		"enabled_func", describeSet(c.enabled != nil),
+		"health_check", describeSet(c.healthCheck != nil),
+		"deadline_split", describeSplit(c.deadlineSplit),
+	)
+
+	b.WriteString("\nschedule\n")
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  retry\tdelay\tmax\tsampled\ttotal")
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	var total time.Duration
+	retries := c.maxAttempts - 1
+	if c.unlimited() {
+		retries = describeMaxRetries
+	}
+	for retry := 1; retry <= min(retries, describeMaxRetries); retry++ {
+		// Draw the delay once, as strategies may keep state across retries
+		var span, drawn time.Duration
+		sampled, _, ok := c.delayWith(retry, nil, func(max time.Duration) time.Duration {
+			if max > 0 {
+				span, drawn = max, time.Duration(rng.Int64N(int64(max)))
+			}
+			return drawn
+		})
+		if !ok {
+			fmt.Fprintf(tw, "  %d\tstop\n", retry)
+			break
+		}
+		low := sampled - drawn
+		total = addDurations(total, sampled)
+		fmt.Fprintf(tw, "  %d\t%v\t%v\t%v\t%v\n", retry, low, addDurations(low, span), sampled, total)
+	}
+	_ = tw.Flush()
+	switch {
+	case c.unlimited():
+		b.WriteString("  ... no attempt limit\n")
+	case retries > describeMaxRetries:
+		fmt.Fprintf(&b, "  ... %d more retries\n", retries-describeMaxRetries)
+	}
+	return b.String()
+}
+
+// WriteGoldenPolicy writes the description of the policy opts configure
+// (see DescribePolicy) to the file at path, creating or truncating it.
+//
+// Example:
+//
+//	// go test ./... -update regenerates the golden file
+//	if *update {
+//	    _ = retrier.WriteGoldenPolicy("testdata/payments.policy", 1, paymentsOptions...)
+//	}
+func WriteGoldenPolicy(path string, seed uint64, opts ...RetryOption) error {
+	return os.WriteFile(path, []byte(DescribePolicy(seed, opts...)), 0o644)
+}
+
+// DiffGoldenPolicy compares the current policy of r with the golden file at
+// path, written by WriteGoldenPolicy with the same seed. It returns the lines
+// that differ, prefixed with "-" for the golden file and "+" for r, or ""
+// if r still behaves as recorded.
+//
+// Example:
+//
+//	diff, err := retrier.DiffGoldenPolicy("testdata/payments.policy", 1, payments)
+//	if err != nil || diff != "" {
+//	    t.Errorf("retry policy changed (%v):\n%s", err, diff)
+//	}
+func DiffGoldenPolicy(path string, seed uint64, r *Retrier) (string, error) {
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return diffLines(string(golden), DescribePolicy(seed, r.RetryOptions()...)), nil
+}
+
+// describeSet describes whether an optional function is set.
+func describeSet(set bool) string {
+	if set {
+		return "set"
+	}
+	return "none"
+}
+
+// describeValidation describes the sampled validation v.
+func describeValidation(v *sampledValidation) string {
+	if v == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%g of successes, and the last attempt", v.fraction)
+}
+
+// describeSplit names a DeadlineSplit.
+func describeSplit(split DeadlineSplit) string {
+	switch split {
+	case SplitNone:
+		return "none"
+	case SplitEqual:
+		return "equal"
+	case SplitDecaying:
+		return "decaying"
+	}
+	return "unknown"
+}
+
+// diffLines returns the lines removed from a ("-") and added in b ("+"),
+// along their longest common subsequence, or "" if a and b are equal.
+func diffLines(a, b string) string {
+	if a == b {
+		return ""
+	}
+	x := strings.Split(strings.TrimSuffix(a, "\n"), "\n")
+	y := strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			i, j = i+1, j+1
+		case j == len(y) || (i < len(x) && lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&out, "-%s\n", x[i])
+			i++
+		default:
+			fmt.Fprintf(&out, "+%s\n", y[j])
+			j++
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,146 @@
+// Package graphqlretry classifies GraphQL responses for retrying.
+//
+// GraphQL servers usually answer HTTP 200 even when the operation failed,
+// listing the failures in the "errors" array of the response, so status-based
+// classification never sees them. Check inspects the response body instead
+// and, through retrier.WithRetryOnResult, turns a 200-with-errors response
+// into a failed attempt:
+//
+//	result := retrier.Retry(ctx, logger, func() ([]byte, error) {
+//	    return postQuery(ctx, query)
+//	}, retrier.WithRetryOnResult(graphqlretry.Check))
+//
+// Whether such a failure is retried follows the "extensions.code" of its
+// errors (see Error.RetryPolicy).
+package graphqlretry
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// transientCodes lists the extensions.code values of failures worth retrying.
+var transientCodes = map[string]bool{
+	"RATE_LIMITED":          true,
+	"THROTTLED":             true,
+	"TOO_MANY_REQUESTS":     true,
+	"INTERNAL":              true,
+	"INTERNAL_SERVER_ERROR": true,
+	"SERVICE_UNAVAILABLE":   true,
+	"UNAVAILABLE":           true,
+	"TIMEOUT":               true,
+	"DEADLINE_EXCEEDED":     true,
+}
+
+// permanentCodes lists the extensions.code values of failures that retrying
+// the same operation cannot fix.
+var permanentCodes = map[string]bool{
+	"GRAPHQL_PARSE_FAILED":      true,
+	"GRAPHQL_VALIDATION_FAILED": true,
+	"BAD_USER_INPUT":            true,
+	"BAD_REQUEST":               true,
+	"UNAUTHENTICATED":           true,
+	"FORBIDDEN":                 true,
+	"NOT_FOUND":                 true,
+}
+
+// Response is the envelope of a GraphQL response.
+type Response struct {
+	Data   json.RawMessage `json:"data,omitempty"`
+	Errors []Error         `json:"errors,omitempty"`
+}
+
+// Error is an entry of the errors array of a GraphQL response.
+type Error struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// Code returns extensions.code, or "" if the error has none.
+func (e Error) Code() string {
+	code, _ := e.Extensions["code"].(string)
+	return strings.ToUpper(code)
+}
+
+// ResponseError is the failure of a GraphQL operation whose response listed
+// errors. It implements retrier.RetryableError and retrier.DelaySuggestioner.
+type ResponseError struct {
+	// Errors are the entries of the errors array.
+	Errors []Error
+}
+
+// Error joins the messages of the errors, prefixed by their codes.
+func (e *ResponseError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		if code := err.Code(); code != "" {
+			msgs[i] = code + ": " + err.Message
+		} else {
+			msgs[i] = err.Message
+		}
+	}
+	return "graphql: " + strings.Join(msgs, "; ")
+}
+
+// RetryPolicy classifies the errors by their extensions.code:
+//   - any permanent code (GRAPHQL_VALIDATION_FAILED, BAD_USER_INPUT,
+//     UNAUTHENTICATED, FORBIDDEN, ...) makes the failure permanent
+//     (RetryPolicyNever);
+//   - otherwise, any transient code (RATE_LIMITED, INTERNAL, TIMEOUT, ...)
+//     makes it retried (RetryPolicyAuto);
+//   - errors without a known code are left to the caller (RetryPolicyManual).
+func (e *ResponseError) RetryPolicy() retrier.RetryPolicy {
+	policy := retrier.RetryPolicyManual
+	for _, err := range e.Errors {
+		code := err.Code()
+		if permanentCodes[code] {
+			return retrier.RetryPolicyNever
+		}
+		if transientCodes[code] {
+			policy = retrier.RetryPolicyAuto
+		}
+	}
+	return policy
+}
+
+// SuggestedDelay returns the longest "retryAfter" extension, in seconds, of
+// the errors, or 0 if none has one.
+func (e *ResponseError) SuggestedDelay() time.Duration {
+	var delay time.Duration
+	for _, err := range e.Errors {
+		seconds, ok := err.Extensions["retryAfter"].(float64)
+		if !ok || seconds <= 0 {
+			continue
+		}
+		if d := time.Duration(seconds * float64(time.Second)); d > delay {
+			delay = d
+		}
+	}
+	return delay
+}
+
+// Classify returns a *ResponseError for resp if it lists errors, and nil
+// otherwise.
+func Classify(resp Response) error {
+	if len(resp.Errors) == 0 {
+		return nil
+	}
+	return &ResponseError{Errors: resp.Errors}
+}
+
+// Check decodes a GraphQL response body and classifies it. It returns nil for
+// a response without errors, a *ResponseError for one with errors, and a
+// permanent error for a body that is not a GraphQL response. It suits
+// retrier.WithRetryOnResult for functions returning the raw body.
+func Check(body []byte) error {
+	var resp Response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return retrier.Permanent(fmt.Errorf("graphqlretry: decode response: %w", err))
+	}
+	return Classify(resp)
+}
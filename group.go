@@ -0,0 +1,84 @@
+package retrier
+
+import (
+	"context"
+	"sync"
+)
+
+// Group runs retried operations concurrently, like errgroup.Group with Retry
+// built in. Operations share one context, which is cancelled when any of them
+// fails, and at most limit of them run at the same time. Every operation is
+// retried with the options of the Group, so a RetryBudget or RetryGuard among
+// them is shared by the whole fan-out.
+//
+// A Group must not be reused after Wait returns.
+type Group[T any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	logger DebugLogger
+	opts   []RetryOption
+	sem    chan struct{}
+
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	results []Result[T]
+	err     error
+}
+
+// NewGroup creates a Group whose operations run under a context derived from
+// ctx and are retried with opts. A limit of 0 or less means no limit.
+//
+// Example:
+//
+//	g := retrier.NewGroup[User](ctx, logger, 8, retrier.WithBudget(budget))
+//	for _, id := range ids {
+//	    g.Go(func(ctx context.Context) (User, error) { return fetchUser(ctx, id) })
+//	}
+//	results, err := g.Wait()
+func NewGroup[T any](ctx context.Context, logger DebugLogger, limit int, opts ...RetryOption) *Group[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	g := &Group[T]{ctx: ctx, cancel: cancel, logger: logger, opts: opts}
+	if limit > 0 {
+		g.sem = make(chan struct{}, limit)
+	}
+	return g
+}
+
+// Go retries fn in a new goroutine. It blocks while limit operations are
+// running. fn receives the attempt context (see RetryCtx), derived from the
+// context of the Group, and should stop when it is cancelled.
+func (g *Group[T]) Go(fn func(ctx context.Context) (T, error)) {
+	g.mu.Lock()
+	i := len(g.results)
+	g.results = append(g.results, Result[T]{})
+	g.mu.Unlock()
+
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+
+		result := RetryCtx(g.ctx, g.logger, fn, g.opts...)
+
+		g.mu.Lock()
+		g.results[i] = result
+		if result.err != nil && g.err == nil {
+			g.err = result.err
+			g.cancel()
+		}
+		g.mu.Unlock()
+	}()
+}
+
+// Wait blocks until all operations have returned. It returns their Results in
+// the order of the Go calls, and the error of the first operation that failed.
+func (g *Group[T]) Wait() ([]Result[T], error) {
+	g.wg.Wait()
+	g.cancel()
+	return g.results, g.err
+}
@@ -0,0 +1,241 @@
+// Package grpcretry translates gRPC service config retry policies into retrier
+// options, without importing google.golang.org/grpc.
+//
+// Policies use the retryPolicy format of the gRPC service config (gRFC A6):
+//
+//	{
+//	  "methodConfig": [{
+//	    "name": [{"service": "echo.Echo"}],
+//	    "retryPolicy": {
+//	      "maxAttempts": 4,
+//	      "initialBackoff": "0.1s",
+//	      "maxBackoff": "1s",
+//	      "backoffMultiplier": 2,
+//	      "retryableStatusCodes": ["UNAVAILABLE"]
+//	    }
+//	  }]
+//	}
+package grpcretry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// maxAttemptsLimit is the cap gRPC applies to maxAttempts.
+const maxAttemptsLimit = 5
+
+// codes maps gRPC status code names to their values.
+var codes = map[string]uint32{
+	"OK":                  0,
+	"CANCELLED":           1,
+	"UNKNOWN":             2,
+	"INVALID_ARGUMENT":    3,
+	"DEADLINE_EXCEEDED":   4,
+	"NOT_FOUND":           5,
+	"ALREADY_EXISTS":      6,
+	"PERMISSION_DENIED":   7,
+	"RESOURCE_EXHAUSTED":  8,
+	"FAILED_PRECONDITION": 9,
+	"ABORTED":             10,
+	"OUT_OF_RANGE":        11,
+	"UNIMPLEMENTED":       12,
+	"INTERNAL":            13,
+	"UNAVAILABLE":         14,
+	"DATA_LOSS":           15,
+	"UNAUTHENTICATED":     16,
+}
+
+// Policy is a validated gRPC retry policy.
+type Policy struct {
+	// MaxAttempts is the maximum number of attempts, including the first.
+	// Values above 5 are capped to 5, as gRPC does.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between attempts.
+	MaxBackoff time.Duration
+
+	// BackoffMultiplier is the factor applied to the delay after each retry.
+	BackoffMultiplier float64
+
+	// RetryableStatusCodes lists the status codes that are retried.
+	RetryableStatusCodes []uint32
+}
+
+// rawPolicy is the JSON form of a retryPolicy.
+type rawPolicy struct {
+	MaxAttempts          int               `json:"maxAttempts"`
+	InitialBackoff       string            `json:"initialBackoff"`
+	MaxBackoff           string            `json:"maxBackoff"`
+	BackoffMultiplier    float64           `json:"backoffMultiplier"`
+	RetryableStatusCodes []json.RawMessage `json:"retryableStatusCodes"`
+}
+
+// rawServiceConfig is the JSON form of the parts of a service config used here.
+type rawServiceConfig struct {
+	MethodConfig []struct {
+		Name []struct {
+			Service string `json:"service"`
+			Method  string `json:"method"`
+		} `json:"name"`
+		RetryPolicy *rawPolicy `json:"retryPolicy"`
+	} `json:"methodConfig"`
+}
+
+// ParsePolicy parses and validates a retryPolicy JSON object.
+func ParsePolicy(data []byte) (Policy, error) {
+	var raw rawPolicy
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Policy{}, fmt.Errorf("grpcretry: %w", err)
+	}
+	return raw.policy()
+}
+
+// policy validates raw following gRFC A6.
+func (raw rawPolicy) policy() (Policy, error) {
+	if raw.MaxAttempts < 2 {
+		return Policy{}, errors.New("grpcretry: maxAttempts must be greater than 1")
+	}
+	initial, err := parseDuration(raw.InitialBackoff)
+	if err != nil || initial <= 0 {
+		return Policy{}, fmt.Errorf("grpcretry: invalid initialBackoff %q", raw.InitialBackoff)
+	}
+	maxBackoff, err := parseDuration(raw.MaxBackoff)
+	if err != nil || maxBackoff <= 0 {
+		return Policy{}, fmt.Errorf("grpcretry: invalid maxBackoff %q", raw.MaxBackoff)
+	}
+	if raw.BackoffMultiplier <= 0 {
+		return Policy{}, errors.New("grpcretry: backoffMultiplier must be greater than 0")
+	}
+	if len(raw.RetryableStatusCodes) == 0 {
+		return Policy{}, errors.New("grpcretry: retryableStatusCodes must not be empty")
+	}
+
+	p := Policy{
+		MaxAttempts:       min(raw.MaxAttempts, maxAttemptsLimit),
+		InitialBackoff:    initial,
+		MaxBackoff:        maxBackoff,
+		BackoffMultiplier: raw.BackoffMultiplier,
+	}
+	for _, rawCode := range raw.RetryableStatusCodes {
+		code, err := parseCode(rawCode)
+		if err != nil {
+			return Policy{}, err
+		}
+		p.RetryableStatusCodes = append(p.RetryableStatusCodes, code)
+	}
+	return p, nil
+}
+
+// parseDuration parses a protobuf JSON duration such as "0.1s".
+func parseDuration(s string) (time.Duration, error) {
+	seconds, ok := strings.CutSuffix(s, "s")
+	if !ok {
+		return 0, fmt.Errorf("grpcretry: invalid duration %q", s)
+	}
+	f, err := strconv.ParseFloat(seconds, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("grpcretry: invalid duration %q", s)
+	}
+	return time.Duration(f * float64(time.Second)), nil
+}
+
+// parseCode parses a status code given by name ("UNAVAILABLE") or number (14).
+func parseCode(raw json.RawMessage) (uint32, error) {
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		if code, ok := codes[strings.ToUpper(name)]; ok {
+			return code, nil
+		}
+		return 0, fmt.Errorf("grpcretry: unknown status code %q", name)
+	}
+	code, err := strconv.ParseUint(string(raw), 10, 32)
+	if err != nil || code > uint64(codes["UNAUTHENTICATED"]) {
+		return 0, fmt.Errorf("grpcretry: invalid status code %s", raw)
+	}
+	return uint32(code), nil
+}
+
+// ServiceConfig holds the retry policies of a gRPC service config by method.
+type ServiceConfig struct {
+	policies map[string]Policy
+}
+
+// ParseServiceConfig parses the retry policies of a service config JSON document.
+// Method configs without a retryPolicy are ignored.
+func ParseServiceConfig(data []byte) (*ServiceConfig, error) {
+	var raw rawServiceConfig
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("grpcretry: %w", err)
+	}
+
+	c := &ServiceConfig{policies: make(map[string]Policy)}
+	for _, mc := range raw.MethodConfig {
+		if mc.RetryPolicy == nil {
+			continue
+		}
+		p, err := mc.RetryPolicy.policy()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range mc.Name {
+			if name.Service == "" && name.Method != "" {
+				return nil, fmt.Errorf("grpcretry: method %q has no service", name.Method)
+			}
+			key := "/" + name.Service + "/" + name.Method
+			if _, dup := c.policies[key]; dup {
+				return nil, fmt.Errorf("grpcretry: duplicate name %q", key)
+			}
+			c.policies[key] = p
+		}
+	}
+	return c, nil
+}
+
+// Policy returns the retry policy for fullMethod ("/package.Service/Method",
+// as passed to gRPC interceptors). As in gRPC, a config for the exact method
+// takes precedence over one for its service, which takes precedence over the
+// default config (empty name).
+func (c *ServiceConfig) Policy(fullMethod string) (Policy, bool) {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	for _, key := range []string{fullMethod, "/" + service + "/", "//"} {
+		if p, ok := c.policies[key]; ok {
+			return p, true
+		}
+	}
+	return Policy{}, false
+}
+
+// RetryOptions translates p into retrier options. code extracts the gRPC status
+// code of an error; pass status.Code from google.golang.org/grpc/status.
+// Errors whose code is not in RetryableStatusCodes are not retried. As gRPC
+// specifies, each delay is drawn at random up to the backoff curve (full
+// jitter).
+//
+// Example, inside a unary client interceptor:
+//
+//	p, ok := config.Policy(method)
+//	result := retrier.Retry(ctx, logger, call, grpcretry.RetryOptions(p, status.Code)...)
+func RetryOptions[C ~uint32](p Policy, code func(error) C) []retrier.RetryOption {
+	return []retrier.RetryOption{
+		retrier.WithMaxAttempts(p.MaxAttempts),
+		retrier.WithInitialDuration(p.InitialBackoff),
+		retrier.WithMaxDuration(p.MaxBackoff),
+		retrier.WithMultiplier(p.BackoffMultiplier),
+		retrier.WithJitterMode(retrier.JitterFull),
+		retrier.WithRetryIf(func(err error) bool {
+			return slices.Contains(p.RetryableStatusCodes, uint32(code(err)))
+		}),
+	}
+}
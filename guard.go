@@ -0,0 +1,120 @@
+package retrier
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// RetryGuard caps the number of operations that are in a retry state at the
+// same time. Share one RetryGuard across all Retry calls of a process to
+// protect it against retry storms: during a dependency outage, operations that
+// fail while the cap is reached return immediately instead of queueing up
+// retries that consume memory and then slam the recovering service.
+//
+// An operation enters the retry state when its first attempt fails with a
+// retryable error, and leaves it when Retry returns.
+type RetryGuard struct {
+	max    int64
+	active atomic.Int64
+
+	mu       sync.Mutex
+	watchers []*pressureWatcher
+	watched  atomic.Bool
+}
+
+// pressureWatcher is a threshold callback registered with OnPressure.
+type pressureWatcher struct {
+	threshold float64
+	fn        func(pressure float64)
+	above     bool
+}
+
+// NewRetryGuard creates a RetryGuard allowing at most max concurrent retrying operations.
+func NewRetryGuard(max int) *RetryGuard {
+	return &RetryGuard{max: int64(max)}
+}
+
+// Active returns the number of operations currently in a retry state.
+func (g *RetryGuard) Active() int {
+	return int(g.active.Load())
+}
+
+// Pressure returns the fraction of the guard's capacity in use, from 0 (no
+// operation retrying) to 1 (full: further retries are suppressed). Producers
+// can poll it to slow intake while too much work is stuck in retry.
+func (g *RetryGuard) Pressure() float64 {
+	if g.max <= 0 {
+		return 1
+	}
+	return float64(g.active.Load()) / float64(g.max)
+}
+
+// OnPressure registers fn to be called with the current pressure when it
+// rises to threshold or above, and again when it falls back below threshold.
+// fn runs synchronously in the retrying goroutine, without any lock of g
+// held, so it may call g; it should return quickly.
+//
+// Example:
+//
+//	guard.OnPressure(0.8, func(p float64) { intake.SetPaused(p >= 0.8) })
+func (g *RetryGuard) OnPressure(threshold float64, fn func(pressure float64)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.watchers = append(g.watchers, &pressureWatcher{
+		threshold: threshold,
+		fn:        fn,
+		above:     g.Pressure() >= threshold,
+	})
+	g.watched.Store(true)
+}
+
+// notifyPressure calls the OnPressure callbacks whose threshold was crossed.
+// They run after g.mu is released, so a slow callback does not hold up other
+// retrying goroutines, and a callback may call back into g.
+func (g *RetryGuard) notifyPressure() {
+	if !g.watched.Load() {
+		return
+	}
+	g.mu.Lock()
+	pressure := g.Pressure()
+	var crossed []func(pressure float64)
+	for _, w := range g.watchers {
+		if above := pressure >= w.threshold; above != w.above {
+			w.above = above
+			crossed = append(crossed, w.fn)
+		}
+	}
+	g.mu.Unlock()
+
+	for _, fn := range crossed {
+		fn(pressure)
+	}
+}
+
+// WithRetryGuard makes Retry register with g before its first retry.
+// When g is full, Retry returns immediately with ErrRetrySuppressed.
+func WithRetryGuard(g *RetryGuard) RetryOption {
+	return func(c *retryConfig) {
+		c.guard = g
+	}
+}
+
+// tryEnter claims a slot, returning false if the guard is full.
+func (g *RetryGuard) tryEnter() bool {
+	for {
+		active := g.active.Load()
+		if active >= g.max {
+			return false
+		}
+		if g.active.CompareAndSwap(active, active+1) {
+			g.notifyPressure()
+			return true
+		}
+	}
+}
+
+// leave releases a slot claimed by tryEnter.
+func (g *RetryGuard) leave() {
+	g.active.Add(-1)
+	g.notifyPressure()
+}
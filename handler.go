@@ -2,11 +2,12 @@ package retrier
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"reflect"
 	"time"
-
-	exponentialbackoff "github.com/rohmanhakim/exponential-backoff"
 )
 
 // Retry executes the provided function with retry logic.
@@ -19,20 +20,67 @@ import (
 //
 // The ctx parameter allows cancellation of the retry operation. If the context
 // is cancelled during a backoff delay, the function returns immediately with
-// ErrContextCancelled.
+// ErrContextCancelled. If a backoff delay would end past the deadline of the
+// context, it returns without waiting, with ErrDeadlineWouldExceed.
 //
 // The logger parameter provides debug logging capabilities. When debug mode is
 // disabled (NoOpLogger), there is zero overhead from logging.
 //
 // opts are functional options to configure retry behavior:
 //   - WithMaxAttempts(n int): Maximum retry attempts (default: 3)
+//   - WithZeroAttempts(mode ZeroAttempts): Whether a limit of 0 is an error or means no limit (default: ZeroAttemptsInvalid)
+//   - WithSoftMaxAttempts(n int): Attempt count past which a warning is emitted, without stopping (default: none)
 //   - WithJitter(d time.Duration): Random delay added to backoff (default: 0)
+//   - WithJitterMode(mode JitterMode): How backoff delays are randomized (default: JitterAdditive)
 //   - WithInitialDuration(d time.Duration): Initial backoff duration (default: 1s)
 //   - WithMultiplier(m float64): Backoff multiplier (default: 2.0)
 //   - WithMaxDuration(d time.Duration): Maximum backoff duration (default: 1m)
-//   - WithRetryPolicy(p RetryPolicy): Default retry policy for standard errors (default: RetryPolicyAuto)
+//   - WithMaxAttemptDuration(d time.Duration): Limit on each attempt, through the attempt context (default: none)
+//   - WithMaxTotalDuration(d time.Duration): Limit on the whole call, attempts and delays included (default: none)
+//   - WithMaxElapsedTime(d time.Duration): Time since the first attempt after which no retry starts (default: none)
+//   - WithMaxTotalBackoff(d time.Duration): Cap on the sum of backoff delays of one call (default: none)
+//   - ConstantBackoff(d time.Duration): Every retry waits d, ignoring the exponential settings (default: exponential)
+//   - WithLinearBackoff(increment time.Duration): Delays grow by increment instead of the multiplier (default: exponential)
+//   - WithFastRetries(n int, delay time.Duration): First n retries at a short fixed delay before the backoff curve (default: none)
+//   - WithSeverity(weigh func(err error) float64): Weight scaling the backoff delay after each error (default: none)
+//   - WithWatchdog(threshold time.Duration, onStuck func(ctx context.Context, s StuckAttempt)): Reports attempts running longer than threshold (default: none)
+//   - WithWatchdogStacks(): Captures goroutine stacks when the watchdog fires (default: off)
+//   - WithDefaultPolicy(p RetryPolicy): Policy for errors that do not implement RetryableError (default: RetryPolicyAuto)
+//   - WithCoordinator(c Coordinator, key string, ttl time.Duration): Cross-process retry lease (default: none)
+//   - WithBudget(b *RetryBudget): Retry-to-request ratio limit (default: none)
+//   - WithInstanceKey(key string): Per-instance offset of the first backoff (default: none)
+//   - WithRetryGuard(g *RetryGuard): Process-wide cap on concurrently retrying operations (default: none)
+//   - WithWakeSignal(s *WakeSignal): External signal that ends backoff delays early (default: none)
+//   - WithBackoff(newStrategy func() BackoffStrategy): Custom delay computation (default: exponential)
+//   - WithRetryIf(retryIf func(error) bool): Predicate replacing the RetryPolicy decision (default: none)
+//   - WithRetryOnResult(check func(T) error): Fails attempts whose result check returns an error (default: none)
+//   - WithStrictClassification(mode StrictMode): Reports or rejects errors only the default policy would decide (default: StrictOff)
+//   - WithSampledValidation(fraction float64, validate func(ctx context.Context, result T) error): Validates a fraction of successful results, always the last attempt (default: none)
+//   - WithSoftFail(): Returns the usable value of a Degraded attempt, with warnings, instead of failing (default: off)
+//   - WithOnRetry(onRetry func(attempt int, err error)): Callback before each backoff delay (default: none)
+//   - WithPolicyProvider(p PolicyProvider): Runtime-replaceable options applied on top of opts (default: none)
+//   - WithHealthCheck(check func(ctx context.Context) bool): Probe extending the backoff delay while the dependency is down (default: none)
+//   - WithEnabledFunc(enabled func(ctx context.Context) bool): Kill switch checked before each retry (default: enabled)
+//   - WithFingerprinter(f Fingerprinter): Error identity used for stats and failure summaries (default: DefaultFingerprint)
+//   - WithErrorFormatter(format func(*RetryError) string): Custom RetryError message format (default: built-in)
+//   - WithRedactor(r Redactor): Rewrites error text reaching logs, callbacks and messages (default: none)
+//   - WithSemaphore(s Semaphore): Concurrency limit held while each attempt runs (default: none)
+//   - WithStopSignal(s *StopSignal): External signal that aborts the retry loop (default: none)
+//   - WithAttemptContext(hook AttemptHook): Derives the context of each attempt under RetryCtx (default: none)
+//   - WithSummarySink(sink SummarySink): Receives attempts, outcome and latencies of each loop when it returns (default: none)
+//   - WithExemplars(traceID func(ctx context.Context) string): Records the trace of failing calls through a Retrier as metric exemplars (default: none)
+//   - WithRuntimeTrace(name string): Runs each call as a runtime/trace task, with attempt and backoff regions (default: none)
+//   - WithDeadlineSplit(split DeadlineSplit): Share of the context deadline given to each attempt (default: SplitNone)
+//   - WithClock(c Clock): Time source for attempt timestamps and backoff delays (default: system clock)
+//   - WithNotifyChannel(ch chan<- RetryEvent): Channel receiving structured retry events (default: none)
+//   - WithNotifyMode(mode NotifyMode): Drop or block when the notify channel is full (default: NotifyDrop)
+//   - WithLatencyTuner(t *LatencyTuner): Initial backoff tuned to the p95 latency of successful attempts (default: none)
+//   - WithExplain(): Records why each retry or stop decision was made, see Result.Decisions (default: off)
+//   - WithRecording(sink RecordingSink): Receives the timeline of each loop, for Replay and Simulate (default: none)
+//   - WithChaos(prob float64, errFactory func() error): Injected attempt failures, behind a build tag or env (default: none)
 //
 // Error handling:
+//   - If WithRetryIf is set, its predicate decides alone
 //   - If the error implements RetryableError, its RetryPolicy() is used
 //   - Standard errors use the configured DefaultRetryPolicy (defaults to RetryPolicyAuto)
 //
@@ -45,20 +93,73 @@ import (
 //	)
 func Retry[T any](ctx context.Context, logger DebugLogger, fn func() (T, error), opts ...RetryOption) Result[T] {
 	// Apply defaults and options
-	config := defaults()
-	for _, opt := range opts {
-		opt(&config)
+	config := newConfig(opts)
+	return retry(ctx, logger, ignoreContext(fn), &config)
+}
+
+// RetryCtx is Retry for functions taking a context. Each attempt receives a
+// context derived from ctx, which is also cancelled when a StopSignal aborts
+// the retry loop mid-attempt (see WithStopSignal and CheckCancel).
+//
+// Example:
+//
+//	result := retrier.RetryCtx(ctx, logger, func(ctx context.Context) (*User, error) {
+//	    return client.GetUser(ctx, id)
+//	})
+func RetryCtx[T any](ctx context.Context, logger DebugLogger, fn func(ctx context.Context) (T, error), opts ...RetryOption) Result[T] {
+	config := newConfig(opts)
+	return retry(ctx, logger, fn, &config)
+}
+
+// ignoreContext adapts fn to the signature of RetryCtx.
+func ignoreContext[T any](fn func() (T, error)) func(context.Context) (T, error) {
+	return func(context.Context) (T, error) {
+		return fn()
 	}
+}
 
+// retry runs the retry loop of Retry with a resolved config.
+func retry[T any](ctx context.Context, logger DebugLogger, fn func(ctx context.Context) (T, error), config *retryConfig) (result Result[T]) {
 	var lastErr error
+	var history []AttemptError
 	var zero T
+	ctx, endTask := config.traceTask(ctx)
+	defer endTask()
+	timer := config.newLoopTimer(ctx)
+	decisions := decisionLog{enabled: config.explain}
+	rec := config.newRecorder()
+
+	// The usable value of a failed attempt, with WithSoftFail
+	var degraded T
+	var degradedErr error
 
-	if config.maxAttempts < 1 {
+	// Every outcome carries the attempt history and decisions, and is published
+	defer func() {
+		if result.err != nil && degradedErr != nil {
+			softFail(&result, degraded, degradedErr)
+		}
+		result.history = history
+		result.maxAttempts = config.maxAttempts
+		result.decisions = decisions.decisions
+		config.traceOutcome(ctx, result.attempts, result.err)
+		config.publishOutcome(ctx, result.attempts, result.err, decisions.last())
+		config.publishSummary(ctx, &timer, result.attempts, result.err)
+		rec.finish(ctx, result.err, decisions.last())
+	}()
+	var leaseHeld bool
+	var guardEntered bool
+
+	if config.maxAttempts < 1 && !config.unlimited() {
+		message := "max attempt cannot be 0"
+		if config.maxAttempts < 0 {
+			message = fmt.Sprintf("max attempts cannot be negative, got %d", config.maxAttempts)
+		}
+		decisions.stop(0, RuleMaxAttempts, "max attempts is %d", config.maxAttempts)
 		return Result[T]{
 			value: zero,
 			err: NewRetryError(
 				ErrZeroAttempt,
-				"max attempt cannot be 0",
+				message,
 				RetryPolicyNever, // Zero attempt is a configuration error
 				nil,
 			),
@@ -66,24 +167,127 @@ func Retry[T any](ctx context.Context, logger DebugLogger, fn func() (T, error),
 		}
 	}
 
-	for attempt := 1; attempt <= config.maxAttempts; attempt++ {
-		result, err := fn()
+	if message := config.validateDurations(); message != "" {
+		decisions.stop(0, RuleMaxTotalDuration, "%s", message)
+		return Result[T]{
+			value:    zero,
+			err:      NewRetryError(ErrInvalidDurations, message, RetryPolicyNever, nil),
+			attempts: 0,
+		}
+	}
+
+	if message := config.resultCheckMismatch(reflect.TypeFor[T]()); message != "" {
+		decisions.stop(0, RuleResultCheck, "%s", message)
+		return Result[T]{
+			value:    zero,
+			err:      NewRetryError(ErrResultTypeMismatch, message, RetryPolicyNever, nil),
+			attempts: 0,
+		}
+	}
+
+	if config.budget != nil {
+		config.budget.recordRequest(ctx)
+	}
+
+	for attempt := 1; config.unlimited() || attempt <= config.maxAttempts; attempt++ {
+		// Attempts, not backoff delays, count against the semaphore
+		if config.semaphore != nil {
+			if acquireErr := config.semaphore.Acquire(ctx, 1); acquireErr != nil {
+				if ctx.Err() != nil && errors.Is(acquireErr, ctx.Err()) {
+					acquireErr = contextError(ctx)
+				}
+				decisions.stop(attempt-1, RuleContext, "context done while waiting for the semaphore: %v", acquireErr)
+				return Result[T]{
+					value: zero,
+					err: config.deadlineError(
+						ctx,
+						&timer,
+						history,
+						ErrContextCancelled,
+						fmt.Sprintf("context cancelled while waiting for the semaphore before attempt %d", attempt),
+						RetryPolicyNever,
+						acquireErr,
+					),
+					attempts: attempt - 1,
+				}
+			}
+		}
+		attemptCtx, release := config.attemptContext(ctx, attempt, timer.elapsed())
+		rec.begin()
+		var value T
+		var err error
+		if config.chaos != nil {
+			err = config.chaos.inject()
+		}
+		if err == nil {
+			var start time.Time
+			if config.tuner != nil {
+				start = config.clock.Now()
+			}
+			unwatch := config.watch(ctx, logger, attempt)
+			endRegion := config.traceRegion(attemptCtx, "retrier.attempt")
+			value, err = fn(attemptCtx)
+			if err == nil && config.resultCheck != nil {
+				err = config.resultCheck(value)
+			}
+			if err == nil && config.validation != nil {
+				err = config.validation.check(attemptCtx, config.lastAttempt(attempt), value)
+			}
+			if err != nil && config.usableValue(attemptCtx, value, err) {
+				degraded, degradedErr = value, err
+			}
+			endRegion()
+			unwatch()
+			if err == nil && config.tuner != nil {
+				config.tuner.Observe(config.clock.Now().Sub(start))
+			}
+		}
+		// Read the cause before release cancels the attempt context
+		cause := contextCause(attemptCtx)
+		release()
+		rec.end(err)
+		if config.semaphore != nil {
+			config.semaphore.Release(1)
+		}
 
 		// Success case: no error
 		if err == nil {
 			// Log successful retry if debug enabled
 			if logger.Enabled() {
-				logger.LogRetry(ctx, attempt, config.maxAttempts, 0, nil, config.attrs...)
+				logger.LogRetry(config.logContext(ctx), attempt, config.maxAttempts, 0, nil, config.attrs...)
 			}
-			return NewSuccessResult(result, attempt)
+			return NewSuccessResult(value, attempt)
 		}
 
 		lastErr = err
+		history = append(history, AttemptError{Attempt: attempt, Time: config.clock.Now(), Err: err, Cause: cause})
+
+		// Errors must be classified explicitly in strict mode
+		if config.strict != StrictOff && config.unclassified(err) {
+			config.reportUnclassified(ctx, logger, attempt, err)
+			if config.strict == StrictFail {
+				decisions.stop(attempt, RuleStrict, "%T is not classified by a RetryableError or WithRetryIf", err)
+				return Result[T]{
+					value: zero,
+					err: config.retryError(
+						history,
+						ErrUnclassified,
+						fmt.Sprintf("attempt %d failed with an unclassified %T", attempt, err),
+						RetryPolicyNever,
+						lastErr,
+					),
+					attempts: attempt,
+				}
+			}
+		}
 
 		// Check if the error should be auto-retried based on RetryPolicy
 		// RetryableError with explicit policy takes precedence
 		// Standard errors use DefaultRetryPolicy
-		if !shouldAutoRetry(err, config.defaultRetryPolicy) {
+		if !config.shouldRetry(err) {
+			timer.notRetried = true
+			rec.notRetried()
+			decisions.notRetried(config, attempt, err)
 			return Result[T]{
 				value:    zero,
 				err:      err,
@@ -92,62 +296,298 @@ func Retry[T any](ctx context.Context, logger DebugLogger, fn func() (T, error),
 		}
 
 		// If this was the last attempt, break and return exhausted error
-		if attempt == config.maxAttempts {
+		if config.lastAttempt(attempt) {
+			decisions.stop(attempt, RuleMaxAttempts, "attempt %d of %d failed", attempt, config.maxAttempts)
 			break
 		}
 
-		// Compute delay for the next retry using exponential backoff with jitter
-		// Ensure initialDuration doesn't exceed maxDuration for valid config
-		initialDuration := config.initialDuration
-		if initialDuration > config.maxDuration {
-			initialDuration = config.maxDuration
+		// Retries may be aborted from outside
+		if config.stop != nil && config.stop.Stopped() {
+			decisions.stop(attempt, RuleStopSignal, "the stop signal fired")
+			return Result[T]{
+				value:    zero,
+				err:      config.stopError(history, attempt, lastErr),
+				attempts: attempt,
+			}
+		}
+
+		// Retries may be switched off at runtime
+		if config.enabled != nil && !config.enabled(ctx) {
+			decisions.stop(attempt, RuleDisabled, "the WithEnabledFunc switch returned false")
+			return Result[T]{
+				value: zero,
+				err: config.retryError(
+					history,
+					ErrRetriesDisabled,
+					fmt.Sprintf("retries disabled after %d attempts", attempt),
+					RetryPolicyManual,
+					lastErr,
+				),
+				attempts: attempt,
+			}
 		}
-		backoffConfig := exponentialbackoff.MustConfig(
-			initialDuration,
-			config.maxDuration,
-			config.multiplier,
-		)
 
-		// Check for server-suggested delay (e.g., HTTP Retry-After, gRPC retry-info)
-		var serverDelay time.Duration
-		if ds, ok := lastErr.(DelaySuggestioner); ok {
-			serverDelay = ds.SuggestedDelay()
+		// Enter the retry state only if the process-wide guard has room
+		if config.guard != nil && !guardEntered {
+			if !config.guard.tryEnter() {
+				decisions.stop(attempt, RuleRetryGuard, "%d operations already retrying, at most %d allowed", config.guard.Active(), config.guard.max)
+				return Result[T]{
+					value: zero,
+					err: config.retryError(
+						history,
+						ErrRetrySuppressed,
+						"too many operations are already retrying",
+						RetryPolicyManual,
+						lastErr,
+					),
+					attempts: attempt,
+				}
+			}
+			guardEntered = true
+			defer config.guard.leave()
 		}
 
-		backoffDelay := exponentialbackoff.CalculateDelay(attempt, config.jitter, backoffConfig,
-			exponentialbackoff.WithServerDelay(serverDelay))
+		// Retries must fit in the retry budget
+		if config.budget != nil && !config.budget.allowRetry(ctx) {
+			if decisions.enabled {
+				decisions.stop(attempt, RuleBudget, "%s", budgetState(ctx, config.budget))
+			}
+			return Result[T]{
+				value: zero,
+				err: config.retryError(
+					history,
+					ErrBudgetExhausted,
+					fmt.Sprintf("retry budget exhausted after %d attempts", attempt),
+					RetryPolicyManual,
+					lastErr,
+				),
+				attempts: attempt,
+			}
+		}
 
-		// Log retry attempt if debug enabled
-		if logger.Enabled() {
-			logger.LogRetry(ctx, attempt, config.maxAttempts, backoffDelay, err, config.attrs...)
+		// Take the retry lease before the first retry so that only one instance
+		// runs the retry ladder for this operation
+		if config.coordination != nil && !leaseHeld {
+			c := config.coordination
+			acquired, acquireErr := c.coordinator.Acquire(ctx, c.key, c.ttl)
+			if acquireErr != nil || !acquired {
+				message := fmt.Sprintf("retry lease for %q is held by another instance", c.key)
+				if acquireErr != nil {
+					message = fmt.Sprintf("failed to acquire retry lease for %q: %v", c.key, acquireErr)
+				}
+				decisions.stop(attempt, RuleCoordination, "%s", message)
+				return Result[T]{
+					value:    zero,
+					err:      config.retryError(history, ErrCoordinationDenied, message, RetryPolicyManual, lastErr),
+					attempts: attempt,
+				}
+			}
+			leaseHeld = true
+			// Release even if ctx is cancelled, otherwise the lease lingers until its TTL
+			defer func() {
+				_ = c.coordinator.Release(context.WithoutCancel(ctx), c.key)
+			}()
 		}
 
-		// Wait for backoff delay or context cancellation
-		select {
-		case <-ctx.Done():
+		// Compute delay for the next retry
+		backoffDelay, rawDelay, ok := config.nextDelay(attempt, lastErr)
+		if !ok {
+			decisions.stop(attempt, RuleBackoffStopped, "the backoff strategy returned false for retry %d", attempt)
 			return Result[T]{
 				value: zero,
-				err: NewRetryError(
-					ErrContextCancelled,
-					fmt.Sprintf("context cancelled after %d attempts", attempt),
-					RetryPolicyNever,
-					ctx.Err(),
+				err: config.retryError(
+					history,
+					ErrBackoffStopped,
+					fmt.Sprintf("backoff strategy stopped after %d attempts", attempt),
+					RetryPolicyManual,
+					lastErr,
 				),
 				attempts: attempt,
 			}
-		case <-time.After(backoffDelay):
 		}
+
+		// Backoff delays must fit in what is left of the total backoff
+		if config.maxTotalBackoff > 0 {
+			waited := timer.waited()
+			if waited >= config.maxTotalBackoff {
+				decisions.stop(attempt, RuleMaxTotalBackoff, "waited %v in backoff delays, at most %v allowed", waited, config.maxTotalBackoff)
+				return Result[T]{
+					value: zero,
+					err: config.retryError(
+						history,
+						ErrTotalBackoffExceeded,
+						fmt.Sprintf("total backoff of %v exhausted after %d attempts", config.maxTotalBackoff, attempt),
+						RetryPolicyManual,
+						lastErr,
+					),
+					attempts: attempt,
+				}
+			}
+			backoffDelay = min(backoffDelay, config.maxTotalBackoff-waited)
+		}
+
+		// The next attempt must start before the total duration is spent
+		if config.maxTotalDuration > 0 {
+			elapsed := timer.elapsed()
+			if elapsed+backoffDelay >= config.maxTotalDuration {
+				decisions.stop(attempt, RuleMaxTotalDuration, "%v elapsed and a %v delay, at most %v allowed", elapsed, backoffDelay, config.maxTotalDuration)
+				return Result[T]{
+					value: zero,
+					err: config.deadlineError(
+						ctx,
+						&timer,
+						history,
+						ErrTotalDurationExceeded,
+						fmt.Sprintf("total duration of %v exhausted after %d attempts", config.maxTotalDuration, attempt),
+						RetryPolicyManual,
+						lastErr,
+					),
+					attempts: attempt,
+				}
+			}
+		}
+
+		// The next attempt must start within the elapsed time allowed
+		if config.maxElapsedTime > 0 {
+			elapsed := timer.elapsed()
+			if elapsed+backoffDelay > config.maxElapsedTime {
+				decisions.stop(attempt, RuleMaxElapsedTime, "%v elapsed and a %v delay, at most %v allowed", elapsed, backoffDelay, config.maxElapsedTime)
+				return Result[T]{
+					value: zero,
+					err: config.deadlineError(
+						ctx,
+						&timer,
+						history,
+						ErrElapsedTimeExceeded,
+						fmt.Sprintf("elapsed time of %v exhausted after %d attempts", config.maxElapsedTime, attempt),
+						RetryPolicyManual,
+						lastErr,
+					),
+					attempts: attempt,
+				}
+			}
+		}
+
+		// Sleeping past the context deadline would only end in its cancellation
+		if deadline, ok := ctx.Deadline(); ok && ctx.Err() == nil {
+			if now := config.clock.Now(); !now.Add(backoffDelay).Before(deadline) {
+				decisions.stop(attempt, RuleDeadline, "a %v delay would end past the context deadline, %v away", backoffDelay, deadline.Sub(now))
+				return Result[T]{
+					value: zero,
+					err: config.deadlineError(
+						ctx,
+						&timer,
+						history,
+						ErrDeadlineWouldExceed,
+						fmt.Sprintf("context deadline would pass during the %v backoff delay after %d attempts", backoffDelay, attempt),
+						RetryPolicyManual,
+						lastErr,
+					),
+					attempts: attempt,
+				}
+			}
+		}
+
+		decisions.retry(config, attempt, err, backoffDelay)
+		rec.retry(backoffDelay, decisions.last())
+		if config.capped(attempt, rawDelay) {
+			timer.capped++
+		}
+
+		// Subscribe to the wake signal before sleeping so a Wake is not missed
+		var wake <-chan struct{}
+		if config.wake != nil {
+			wake = config.wake.wait()
+		}
+
+		if config.onRetry != nil {
+			config.onRetry(attempt, config.redactError(err))
+		}
+		config.publish(ctx, RetryEvent{Kind: EventRetry, Attempt: attempt, Backoff: backoffDelay, RawBackoff: rawDelay, Err: config.redactError(err), Cause: config.redactError(cause), Decision: decisions.last()})
+		if attempt == config.softMaxAttempts {
+			config.warnSoftLimit(ctx, logger, attempt, err)
+		}
+
+		// Log retry attempt if debug enabled
+		if logger.Enabled() {
+			logger.LogRetry(config.logContext(ctx), attempt, config.maxAttempts, backoffDelay, config.redactError(err), config.attrs...)
+		}
+
+		// Wait for backoff delay, an early wake-up, or context cancellation,
+		// and again for as long as the dependency is reported down
+		config.traceLog(ctx, "attempt %d failed, retrying in %v: %v", attempt, backoffDelay, config.redactError(err))
+		endWait := config.traceRegion(ctx, "retrier.backoff")
+		for {
+			delay := config.clock.NewTimer(backoffDelay)
+			timer.sleep()
+			select {
+			case <-ctx.Done():
+				delay.Stop()
+				endWait()
+				decisions.stop(attempt, RuleContext, "context done during the backoff delay: %v", contextError(ctx))
+				return Result[T]{
+					value: zero,
+					err: config.deadlineError(
+						ctx,
+						&timer,
+						history,
+						ErrContextCancelled,
+						fmt.Sprintf("context cancelled after %d attempts", attempt),
+						RetryPolicyNever,
+						contextError(ctx),
+					),
+					attempts: attempt,
+				}
+			case <-delay.C():
+			case <-wake:
+				delay.Stop()
+			case <-config.stopped():
+				delay.Stop()
+				endWait()
+				decisions.stop(attempt, RuleStopSignal, "the stop signal fired during the backoff delay")
+				return Result[T]{
+					value:    zero,
+					err:      config.stopError(history, attempt, lastErr),
+					attempts: attempt,
+				}
+			}
+
+			// Probe the dependency before spending an attempt on it
+			if config.healthCheck == nil || config.healthCheck(ctx) {
+				break
+			}
+			if config.maxTotalBackoff > 0 {
+				// Stop extending the delay once the total backoff is spent
+				waited := timer.waited()
+				if waited >= config.maxTotalBackoff {
+					break
+				}
+				backoffDelay = min(backoffDelay, config.maxTotalBackoff-waited)
+			}
+			if config.maxTotalDuration > 0 && timer.elapsed() >= config.maxTotalDuration {
+				break
+			}
+			if config.maxElapsedTime > 0 && timer.elapsed() >= config.maxElapsedTime {
+				break
+			}
+			if config.wake != nil {
+				wake = config.wake.wait()
+			}
+		}
+		endWait()
+		timer.wake()
 	}
 
 	// Log exhausted attempts if debug enabled
 	if logger.Enabled() {
-		logger.LogRetry(ctx, config.maxAttempts, config.maxAttempts, 0, lastErr, config.attrs...)
+		logger.LogRetry(config.logContext(ctx), config.maxAttempts, config.maxAttempts, 0, config.redactError(lastErr), config.attrs...)
 	}
 
 	// Return failure result when max attempts are exhausted
 	return Result[T]{
 		value: zero,
-		err: NewRetryError(
+		err: config.retryError(
+			history,
 			ErrExhaustedAttempts,
 			fmt.Sprintf("exhausted %d attempts. Last error: %v", config.maxAttempts, lastErr),
 			RetryPolicyManual, // Exhausted auto-retry → manual retry eligible
@@ -157,6 +597,15 @@ func Retry[T any](ctx context.Context, logger DebugLogger, fn func() (T, error),
 	}
 }
 
+// shouldRetry determines whether err should be retried, using the WithRetryIf
+// predicate if one is set and the retry policy otherwise.
+func (c *retryConfig) shouldRetry(err error) bool {
+	if c.retryIf != nil {
+		return c.retryIf(err)
+	}
+	return shouldAutoRetry(err, c.defaultRetryPolicy)
+}
+
 // shouldAutoRetry determines whether an error should trigger automatic retry.
 // If the error implements RetryableError, its RetryPolicy() is used.
 // Otherwise, the defaultPolicy is applied.
@@ -168,3 +617,14 @@ func shouldAutoRetry(err error, defaultPolicy RetryPolicy) bool {
 	// Standard error: use default policy
 	return defaultPolicy == RetryPolicyAuto
 }
+
+// instancePhase maps key to a deterministic offset in [0, span).
+func instancePhase(key string, span time.Duration) time.Duration {
+	return time.Duration(keyFraction(key) * float64(span))
+}
+
+// keyFraction maps key to a deterministic, uniformly distributed value in [0, 1).
+func keyFraction(key string) float64 {
+	sum := sha256.Sum256([]byte(key))
+	return float64(binary.BigEndian.Uint64(sum[:8])) / (1 << 64)
+}
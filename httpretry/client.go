@@ -0,0 +1,662 @@
+package httpretry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/netretry"
+)
+
+// CheckRetry decides whether a request should be retried, like
+// retryablehttp.CheckRetry. Returning a non-nil error stops retrying with that error.
+type CheckRetry func(ctx context.Context, resp *http.Response, err error) (bool, error)
+
+// ErrorHandler is called when retries stop without success, like
+// retryablehttp.ErrorHandler. It receives the last response (with its body
+// still open) and error, and decides what Do returns.
+type ErrorHandler func(resp *http.Response, err error, numTries int) (*http.Response, error)
+
+// ReaderFunc returns a fresh reader of a request body for each attempt.
+type ReaderFunc func() (io.Reader, error)
+
+// DefaultMaxBufferedBody is the size up to which the standard client of a
+// Client buffers request bodies that cannot be replayed otherwise.
+const DefaultMaxBufferedBody = 1 << 20
+
+// DefaultMaxErrorBody is the number of bytes of failed responses' bodies a
+// Client captures into their ResponseError.
+const DefaultMaxErrorBody = 4 << 10
+
+// ErrBodyNotReplayable is wrapped by the errors of requests that were not
+// retried because their body was already consumed and cannot be sent again.
+var ErrBodyNotReplayable = errors.New("httpretry: request body cannot be replayed")
+
+// Request wraps an http.Request whose body can be replayed across attempts,
+// like retryablehttp.Request.
+type Request struct {
+	body ReaderFunc
+	*http.Request
+
+	// oneShot is set when body can only be read once, so the request
+	// cannot be retried once sent
+	oneShot bool
+}
+
+// NewRequest creates a Request with a replayable body.
+// rawBody may be nil, a ReaderFunc or func() (io.Reader, error), []byte,
+// string, *bytes.Buffer, *bytes.Reader, *strings.Reader, or any io.Reader,
+// which is read into memory.
+func NewRequest(method, url string, rawBody any) (*Request, error) {
+	return NewRequestWithContext(context.Background(), method, url, rawBody)
+}
+
+// NewRequestWithContext is NewRequest with a context.
+func NewRequestWithContext(ctx context.Context, method, url string, rawBody any) (*Request, error) {
+	body, contentLength, err := bodyReader(rawBody)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.ContentLength = contentLength
+	return &Request{body: body, Request: httpReq}, nil
+}
+
+// FromRequest wraps an http.Request whose body can be replayed: through its
+// GetBody function if set, as for the requests of http.NewRequest with an
+// in-memory body, and otherwise by reading the body into memory, in which
+// case the Request wraps a clone of r with the length of the body read. r
+// itself is left as is: its body is read, but not closed.
+func FromRequest(r *http.Request) (*Request, error) {
+	if req, ok := fromGetBody(r); ok {
+		return req, nil
+	}
+	body, contentLength, err := bodyReader(struct{ io.Reader }{r.Body})
+	if err != nil {
+		return nil, err
+	}
+	clone := r.Clone(r.Context())
+	clone.ContentLength = contentLength
+	return &Request{body: body, Request: clone}, nil
+}
+
+// fromRequestLimited is FromRequest for requests sent through the standard
+// client of a Client: bodies without GetBody are buffered up to limit bytes,
+// into a clone of r, and larger ones are sent once, without retries.
+func fromRequestLimited(r *http.Request, limit int64) (*Request, error) {
+	if req, ok := fromGetBody(r); ok {
+		return req, nil
+	}
+	buf, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(buf)) <= limit {
+		clone := r.Clone(r.Context())
+		clone.ContentLength = int64(len(buf))
+		return &Request{body: bytesReader(buf), Request: clone}, nil
+	}
+
+	// Too large to buffer: send the read prefix followed by the rest, once
+	rest := r.Body
+	sent := false
+	body := func() (io.Reader, error) {
+		if sent {
+			return nil, ErrBodyNotReplayable
+		}
+		sent = true
+		return struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(buf), rest), rest}, nil
+	}
+	return &Request{body: body, Request: r, oneShot: true}, nil
+}
+
+// fromGetBody wraps r if its body is empty or replayable through GetBody,
+// which provides the body of every attempt, leaving r.Body unread.
+func fromGetBody(r *http.Request) (*Request, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return &Request{Request: r}, true
+	}
+	if r.GetBody == nil {
+		return nil, false
+	}
+	body := func() (io.Reader, error) {
+		return r.GetBody()
+	}
+	return &Request{body: body, Request: r}, true
+}
+
+// WithContext returns a copy of r with its context changed to ctx.
+func (r *Request) WithContext(ctx context.Context) *Request {
+	return &Request{body: r.body, Request: r.Request.WithContext(ctx), oneShot: r.oneShot}
+}
+
+// bodyReader converts rawBody into a ReaderFunc and its length (-1 if unknown).
+func bodyReader(rawBody any) (ReaderFunc, int64, error) {
+	switch body := rawBody.(type) {
+	case nil:
+		return nil, 0, nil
+	case ReaderFunc:
+		return body, -1, nil
+	case func() (io.Reader, error):
+		return body, -1, nil
+	case []byte:
+		return bytesReader(body), int64(len(body)), nil
+	case string:
+		return bytesReader([]byte(body)), int64(len(body)), nil
+	case *bytes.Buffer:
+		return bytesReader(body.Bytes()), int64(body.Len()), nil
+	case *bytes.Reader:
+		buf, err := io.ReadAll(body)
+		return bytesReader(buf), int64(len(buf)), err
+	case *strings.Reader:
+		buf, err := io.ReadAll(body)
+		return bytesReader(buf), int64(len(buf)), err
+	case io.Reader:
+		buf, err := io.ReadAll(body)
+		if closer, ok := body.(io.Closer); ok {
+			_ = closer.Close()
+		}
+		return bytesReader(buf), int64(len(buf)), err
+	default:
+		return nil, 0, fmt.Errorf("httpretry: cannot handle request body of type %T", rawBody)
+	}
+}
+
+// bytesReader returns a ReaderFunc replaying buf.
+func bytesReader(buf []byte) ReaderFunc {
+	return func() (io.Reader, error) {
+		return bytes.NewReader(buf), nil
+	}
+}
+
+// Client is an HTTP client with automatic retries, API-compatible with
+// hashicorp/go-retryablehttp's Client and backed by retrier.Retry.
+//
+// Delays grow exponentially from RetryWaitMin to RetryWaitMax. A Retry-After
+// header on 429 and 503 responses is honored, as are retry delays found in
+// JSON error bodies (see ParseErrorBody).
+type Client struct {
+	// HTTPClient sends the requests.
+	HTTPClient *http.Client
+
+	// Logger receives retry logs. Default is no logging.
+	Logger retrier.DebugLogger
+
+	// RetryWaitMin is the delay before the first retry.
+	RetryWaitMin time.Duration
+
+	// RetryWaitMax is the maximum delay between retries.
+	RetryWaitMax time.Duration
+
+	// RetryMax is the maximum number of retries.
+	RetryMax int
+
+	// CheckRetry decides which responses and errors are retried.
+	// Default is DefaultRetryPolicy.
+	CheckRetry CheckRetry
+
+	// ErrorHandler decides what Do returns when retries stop without success.
+	// By default, Do closes the last response and returns an error.
+	ErrorHandler ErrorHandler
+
+	// Idempotency decides which requests may be retried, typically keeping
+	// POST and PATCH requests without an idempotency key from being sent
+	// twice. Failures of other requests that would be retried return the
+	// response as is, or an error wrapping ErrNotIdempotent. Default is
+	// none: every request is retried, like go-retryablehttp does.
+	Idempotency *IdempotencyPolicy
+
+	// ProxyRotator chooses the proxy or source address of each attempt.
+	// It requires HTTPClient's Transport to be nil or an *http.Transport,
+	// which is cloned per egress. Default is none.
+	ProxyRotator ProxyRotator
+
+	// ReResolve makes the attempt after a transport error dial a new
+	// connection, resolving the host again, instead of reusing an idle
+	// connection to an address that may have stopped answering, as happens
+	// after load balancer changes. It closes the idle connections of the
+	// transport. Default is false.
+	ReResolve bool
+
+	// MaxBufferedBody is the size up to which the standard client (see
+	// StandardClient) reads request bodies that cannot be replayed through
+	// their GetBody function into memory, to retry them. Larger requests are
+	// sent once: failures that would be retried return the response as is,
+	// or an error wrapping ErrBodyNotReplayable, since sending a partly
+	// consumed body again would corrupt the request. Default is
+	// DefaultMaxBufferedBody; a negative value buffers nothing.
+	MaxBufferedBody int64
+
+	// BufferLargeBodies makes the standard client read request bodies of any
+	// size into memory, so all of them can be retried. Default is false.
+	BufferLargeBodies bool
+
+	// Hosts keeps retry state per destination host, such as a RetryBudget
+	// or RetryGuard created in its options function, so a failing upstream
+	// exhausts only its own budget and does not hold back the retries of
+	// healthy hosts sharing the Client. Requests run through the Retrier of
+	// their URL's host (including the port), which logs to the logger of
+	// Hosts; RetryMax, RetryWaitMin, and RetryWaitMax apply on top of its
+	// options. Default is none: all hosts share the state of the Client.
+	Hosts *retrier.KeyedRetriers
+
+	// MaxErrorBody is the number of bytes of the body of each failed
+	// response captured into its ResponseError, for diagnostics. The body
+	// is peeked, not consumed: ErrorHandler still receives it in full.
+	// Default is DefaultMaxErrorBody; a negative value captures nothing.
+	MaxErrorBody int
+
+	// AttemptHeaders adds headers such as the attempt number, an
+	// idempotency key, and a request ID to every attempt. Default is none.
+	AttemptHeaders *AttemptHeaders
+
+	// RotateAddresses makes ReResolve also move to the next address the
+	// host (or the proxy, when one is used) resolves to, through a
+	// netretry.Dialer. It requires HTTPClient's Transport to be nil or an
+	// *http.Transport, which is cloned. Default is false.
+	RotateAddresses bool
+
+	// Pacer paces the attempts of every request by the rate limit state of
+	// the responses: each attempt waits for its slot, and each response is
+	// observed. Share one Pacer between the Clients of a rate-limited API.
+	// Default is none.
+	Pacer *Pacer
+
+	transports egressTransports
+}
+
+// NewClient creates a Client with the go-retryablehttp defaults:
+// 4 retries waiting between 1 and 30 seconds.
+func NewClient() *Client {
+	return &Client{
+		HTTPClient:   &http.Client{},
+		Logger:       retrier.NewNoOpLogger(),
+		RetryWaitMin: 1 * time.Second,
+		RetryWaitMax: 30 * time.Second,
+		RetryMax:     4,
+		CheckRetry:   DefaultRetryPolicy,
+	}
+}
+
+// DefaultRetryPolicy retries transport errors, 429 responses, and 5xx
+// responses other than 501 Not Implemented. Transport errors that
+// netretry.TLSRules classifies as permanent, such as certificate validation
+// failures, are not retried. It stops when ctx is done.
+func DefaultRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	if err != nil {
+		if policy, ok := netretry.Classify(err, netretry.TLSRules()...); ok && policy != retrier.RetryPolicyAuto {
+			return false, nil
+		}
+		return true, nil
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == 0 ||
+		(resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented) {
+		return true, nil
+	}
+	return false, nil
+}
+
+// ResponseError describes a failed response, with a prefix of its body for
+// diagnostics. The errors of Do for responses that were retried until giving
+// up wrap the ResponseError of the last one, as do the attempt errors of the
+// retry history.
+//
+// Example:
+//
+//	var respErr *httpretry.ResponseError
+//	if errors.As(err, &respErr) {
+//	    log.Printf("last response: %d %q", respErr.StatusCode, respErr.Body)
+//	}
+type ResponseError struct {
+	// StatusCode is the status code of the response.
+	StatusCode int
+
+	// Status is the status line of the response, such as "503 Service Unavailable".
+	Status string
+
+	// Body is the captured prefix of the response body, up to the
+	// MaxErrorBody of the Client.
+	Body []byte
+
+	// Truncated is true if the body was longer than Body.
+	Truncated bool
+}
+
+func (e *ResponseError) Error() string {
+	msg := fmt.Sprintf("unexpected HTTP status %s", e.Status)
+	if len(e.Body) == 0 {
+		return msg
+	}
+	ellipsis := ""
+	if e.Truncated {
+		ellipsis = "..."
+	}
+	return fmt.Sprintf("%s: %s%s", msg, bytes.TrimSpace(e.Body), ellipsis)
+}
+
+// newResponseError creates the ResponseError of resp, capturing up to limit
+// bytes of its body. The body is peeked, not consumed: resp.Body still yields
+// the full body afterwards.
+func newResponseError(resp *http.Response, limit int) *ResponseError {
+	respErr := &ResponseError{StatusCode: resp.StatusCode, Status: resp.Status}
+	if limit <= 0 || resp.Body == nil || resp.Body == http.NoBody {
+		return respErr
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	if len(body) > limit {
+		body, respErr.Truncated = body[:limit], true
+	}
+	respErr.Body = body
+	return respErr
+}
+
+// attemptError reports a failed attempt to retrier.Retry.
+type attemptError struct {
+	err   error
+	retry bool
+	delay time.Duration
+}
+
+func (e *attemptError) Error() string { return e.err.Error() }
+
+func (e *attemptError) Unwrap() error { return e.err }
+
+// RetryPolicy retries the attempt if CheckRetry asked for it.
+func (e *attemptError) RetryPolicy() retrier.RetryPolicy {
+	if e.retry {
+		return retrier.RetryPolicyAuto
+	}
+	return retrier.RetryPolicyNever
+}
+
+// SuggestedDelay returns the delay from the response's Retry-After header.
+func (e *attemptError) SuggestedDelay() time.Duration {
+	return e.delay
+}
+
+// Do sends req, retrying as configured.
+func (c *Client) Do(req *Request) (*http.Response, error) {
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	checkRetry := c.CheckRetry
+	if checkRetry == nil {
+		checkRetry = DefaultRetryPolicy
+	}
+	logger := c.Logger
+	if logger == nil {
+		logger = retrier.NewNoOpLogger()
+	}
+	maxErrorBody := c.MaxErrorBody
+	if maxErrorBody == 0 {
+		maxErrorBody = DefaultMaxErrorBody
+	}
+	ctx := req.Context()
+
+	// Attempts are clones of base, so the request of the caller is never
+	// modified, as http.RoundTripper requires of StandardClient
+	base := req.Request
+	if c.AttemptHeaders != nil {
+		base = base.Clone(ctx)
+		c.AttemptHeaders.setRequest(base)
+	}
+
+	var unsafe error
+	switch {
+	case req.oneShot:
+		unsafe = ErrBodyNotReplayable
+	case c.Idempotency != nil && !c.Idempotency.Retryable(base):
+		unsafe = ErrNotIdempotent
+	}
+
+	var lastResp *http.Response
+	var lastErr error
+	var prevFailure *Failure
+	var attemptCtx context.Context
+	attempt := 0
+	fn := func() (*http.Response, error) {
+		attempt++
+		attemptReq := base.Clone(ctx)
+		if c.AttemptHeaders != nil {
+			c.AttemptHeaders.setAttempt(attemptCtx, attemptReq, attempt)
+		}
+		// Free the connection of the previous attempt's response
+		if lastResp != nil {
+			drainBody(lastResp)
+			lastResp = nil
+		}
+
+		if req.body != nil {
+			body, err := req.body()
+			if err != nil {
+				return nil, &attemptError{err: err}
+			}
+			if rc, ok := body.(io.ReadCloser); ok {
+				attemptReq.Body = rc
+			} else {
+				attemptReq.Body = io.NopCloser(body)
+			}
+		}
+
+		attemptClient := httpClient
+		var egress Egress
+		var dialer *netretry.Dialer
+		if c.ProxyRotator != nil || (c.ReResolve && c.RotateAddresses) {
+			if c.ProxyRotator != nil {
+				egress = c.ProxyRotator.Egress(attempt, prevFailure)
+			}
+			cached, err := c.transports.get(httpClient.Transport, egress, c.ReResolve && c.RotateAddresses)
+			if err != nil {
+				return nil, &attemptError{err: err}
+			}
+			egressClient := *httpClient
+			egressClient.Transport = cached.transport
+			attemptClient = &egressClient
+			dialer = cached.dialer
+		}
+
+		// Do not reuse a connection to an address that just failed
+		if c.ReResolve && prevFailure != nil && prevFailure.Kind == FailureTransport {
+			if dialer != nil {
+				dialHost := req.URL.Hostname()
+				if egress.Proxy != nil {
+					dialHost = egress.Proxy.Hostname()
+				}
+				dialer.Rotate(dialHost)
+			}
+			attemptClient.CloseIdleConnections()
+		}
+
+		if c.Pacer != nil {
+			if err := c.Pacer.Wait(ctx); err != nil {
+				if attemptReq.Body != nil {
+					attemptReq.Body.Close()
+				}
+				return nil, &attemptError{err: err}
+			}
+		}
+		resp, err := attemptClient.Do(attemptReq)
+		if c.Pacer != nil && resp != nil {
+			c.Pacer.Observe(resp.Header)
+		}
+		shouldRetry, checkErr := checkRetry(ctx, resp, err)
+		if checkErr != nil {
+			if resp != nil {
+				drainBody(resp)
+			}
+			return nil, &attemptError{err: checkErr}
+		}
+		// Consumed bodies and non-idempotent requests cannot be sent again
+		if shouldRetry && unsafe != nil {
+			if err != nil {
+				return nil, &attemptError{err: fmt.Errorf("%w (%w)", err, unsafe)}
+			}
+			shouldRetry = false
+		}
+		if !shouldRetry {
+			if err != nil {
+				return nil, &attemptError{err: err}
+			}
+			return resp, nil
+		}
+
+		lastResp, lastErr = resp, err
+		prevFailure = &Failure{Kind: ClassifyFailure(resp, err), Err: err, Egress: egress}
+		if resp != nil {
+			prevFailure.StatusCode = resp.StatusCode
+		}
+		attemptErr := &attemptError{err: err, retry: true}
+		if resp != nil {
+			attemptErr.delay = retryDelay(resp)
+			attemptErr.err = newResponseError(resp, maxErrorBody)
+		}
+		return nil, attemptErr
+	}
+
+	opts := []retrier.RetryOption{
+		retrier.WithMaxAttempts(c.RetryMax + 1),
+		retrier.WithInitialDuration(c.RetryWaitMin),
+		retrier.WithMaxDuration(c.RetryWaitMax),
+	}
+	if c.AttemptHeaders != nil && c.AttemptHeaders.FromContext != nil {
+		// Last, so the context derives from every other hook
+		opts = append(opts, retrier.WithAttemptContext(func(ctx context.Context, _ int) (context.Context, func()) {
+			attemptCtx = ctx
+			return ctx, nil
+		}))
+	}
+	var result retrier.Result[*http.Response]
+	if c.Hosts != nil {
+		result = retrier.Do(ctx, c.Hosts.Get(req.URL.Host), fn, opts...)
+	} else {
+		result = retrier.Retry(ctx, logger, fn, opts...)
+	}
+	resp, attempts, err := result.Decompose()
+	if err == nil {
+		return resp, nil
+	}
+
+	var attemptErr *attemptError
+	if errors.As(err, &attemptErr) && !attemptErr.retry {
+		// Stopped by CheckRetry or a non-retryable error
+		return nil, attemptErr.err
+	}
+
+	if c.ErrorHandler != nil {
+		return c.ErrorHandler(lastResp, lastErr, attempts)
+	}
+	if lastResp != nil {
+		drainBody(lastResp)
+	}
+	return nil, fmt.Errorf("%s %s giving up after %d attempt(s): %w", req.Method, req.URL, attempts, err)
+}
+
+// Get sends a GET request to url.
+func (c *Client) Get(url string) (*http.Response, error) {
+	req, err := NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Head sends a HEAD request to url.
+func (c *Client) Head(url string) (*http.Response, error) {
+	req, err := NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Post sends a POST request to url with the given body and Content-Type.
+// See NewRequest for the supported body types.
+func (c *Client) Post(url, bodyType string, body any) (*http.Response, error) {
+	req, err := NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", bodyType)
+	return c.Do(req)
+}
+
+// PostForm sends a POST request to url with data URL-encoded as the body.
+func (c *Client) PostForm(url string, data url.Values) (*http.Response, error) {
+	return c.Post(url, "application/x-www-form-urlencoded", data.Encode())
+}
+
+// StandardClient returns an *http.Client sending its requests through c,
+// for APIs that expect a standard client. Request bodies are replayed
+// through their GetBody function, or buffered up to MaxBufferedBody.
+func (c *Client) StandardClient() *http.Client {
+	return &http.Client{Transport: &roundTripper{client: c}}
+}
+
+// roundTripper is an http.RoundTripper sending requests through a Client.
+type roundTripper struct {
+	client *Client
+}
+
+// RoundTrip sends req through the Client, buffering its body as configured
+// by MaxBufferedBody and BufferLargeBodies. req is not modified; its body is
+// closed once the Client is done with it, as http.RoundTripper requires.
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+	var retryableReq *Request
+	var err error
+	switch limit := rt.client.MaxBufferedBody; {
+	case rt.client.BufferLargeBodies:
+		retryableReq, err = FromRequest(req)
+	case limit == 0:
+		retryableReq, err = fromRequestLimited(req, DefaultMaxBufferedBody)
+	default:
+		retryableReq, err = fromRequestLimited(req, max(limit, 0))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rt.client.Do(retryableReq)
+}
+
+// retryDelay returns the delay a response asks for, from its Retry-After
+// header on 429 and 503 responses or from the retry hints of its body.
+func retryDelay(resp *http.Response) time.Duration {
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		if d, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return d
+		}
+	}
+	if hint, ok := ResponseErrorHint(resp); ok {
+		return hint.RetryAfter
+	}
+	return 0
+}
+
+// drainBody reads and closes resp's body so its connection can be reused.
+func drainBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,87 @@
+package httpretry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+)
+
+// DefaultAttemptHeader is the header AttemptHeaders conventionally carries
+// the attempt number in.
+const DefaultAttemptHeader = "X-Retry-Attempt"
+
+// DefaultRequestIDHeader is the header AttemptHeaders conventionally carries
+// the request ID in.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// AttemptHeaders adds headers to every attempt of a request, so servers can
+// correlate the attempts of a request and deduplicate its retries. Headers
+// already set on the request are kept. The headers are set on the copy of
+// the request each attempt sends, never on the request passed to Do.
+//
+// Example:
+//
+//	client.AttemptHeaders = &httpretry.AttemptHeaders{
+//	    Attempt:        httpretry.DefaultAttemptHeader,
+//	    IdempotencyKey: httpretry.DefaultIdempotencyKeyHeader,
+//	    RequestID:      httpretry.DefaultRequestIDHeader,
+//	}
+type AttemptHeaders struct {
+	// Attempt is the header carrying the attempt number, starting at 1,
+	// such as DefaultAttemptHeader. "" sends none.
+	Attempt string
+
+	// IdempotencyKey is the header carrying a key generated once per
+	// request and sent with all its attempts, such as
+	// DefaultIdempotencyKeyHeader. A key generated for the KeyHeader of the
+	// IdempotencyPolicy of the Client makes the request retryable. "" sends
+	// none.
+	IdempotencyKey string
+
+	// RequestID is the header carrying an ID generated once per request and
+	// sent with all its attempts, such as DefaultRequestIDHeader. "" sends
+	// none.
+	RequestID string
+
+	// NewID generates idempotency keys and request IDs. Default is 16
+	// random bytes in hex.
+	NewID func() string
+
+	// FromContext, if not nil, sets more headers on each attempt from its
+	// context, which carries the values of retrier.WithAttemptContext hooks.
+	FromContext func(ctx context.Context, attempt int, h http.Header)
+}
+
+// setRequest sets the headers shared by all the attempts of req.
+func (a *AttemptHeaders) setRequest(req *http.Request) {
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	for _, name := range []string{a.IdempotencyKey, a.RequestID} {
+		if name != "" && req.Header.Get(name) == "" {
+			req.Header.Set(name, a.newID())
+		}
+	}
+}
+
+// setAttempt sets the headers of attempt of req, which runs with ctx.
+func (a *AttemptHeaders) setAttempt(ctx context.Context, req *http.Request, attempt int) {
+	if a.Attempt != "" {
+		req.Header.Set(a.Attempt, strconv.Itoa(attempt))
+	}
+	if a.FromContext != nil {
+		a.FromContext(ctx, attempt, req.Header)
+	}
+}
+
+// newID returns a new idempotency key or request ID.
+func (a *AttemptHeaders) newID() string {
+	if a.NewID != nil {
+		return a.NewID()
+	}
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,97 @@
+package httpretry
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// DefaultIdempotencyKeyHeader is the header marking a request as safe to
+// retry whatever its method.
+const DefaultIdempotencyKeyHeader = "Idempotency-Key"
+
+// ErrNotIdempotent is wrapped by the errors of requests that were not
+// retried because their IdempotencyPolicy does not allow it.
+var ErrNotIdempotent = errors.New("httpretry: request is not idempotent")
+
+// IdempotencyPolicy decides which requests may be retried, since retrying a
+// request the server already processed repeats its effect. By default, the
+// methods RFC 9110 defines as idempotent (GET, HEAD, OPTIONS, TRACE, PUT and
+// DELETE) are retried, and others, such as POST and PATCH, only when they
+// carry an idempotency key. Allow and Deny rules override the default for
+// some methods and paths.
+//
+// Example:
+//
+//	policy := httpretry.NewIdempotencyPolicy().
+//	    Allow(http.MethodPost, "/v1/search").      // read-only despite POST
+//	    Deny(http.MethodDelete, "/v1/jobs/*/run") // triggers a run
+//	client.Idempotency = policy
+type IdempotencyPolicy struct {
+	// KeyHeader is the header whose presence makes any request retryable.
+	// Default is DefaultIdempotencyKeyHeader; "" disables the check.
+	KeyHeader string
+
+	rules []idempotencyRule
+}
+
+// idempotencyRule allows or denies the retries of a method and path pattern.
+type idempotencyRule struct {
+	method  string
+	pattern string
+	allow   bool
+}
+
+// NewIdempotencyPolicy creates an IdempotencyPolicy with the default rules.
+func NewIdempotencyPolicy() *IdempotencyPolicy {
+	return &IdempotencyPolicy{KeyHeader: DefaultIdempotencyKeyHeader}
+}
+
+// Allow makes the requests of method to paths matching pattern retryable.
+// method may be "*" for any method, and pattern is a path.Match pattern,
+// such as "/orders/*", where "*" does not match "/". It returns p.
+func (p *IdempotencyPolicy) Allow(method, pattern string) *IdempotencyPolicy {
+	p.rules = append(p.rules, idempotencyRule{method: method, pattern: pattern, allow: true})
+	return p
+}
+
+// Deny makes the requests of method to paths matching pattern not
+// retryable, even with an idempotency key. See Allow for the patterns. It
+// returns p.
+func (p *IdempotencyPolicy) Deny(method, pattern string) *IdempotencyPolicy {
+	p.rules = append(p.rules, idempotencyRule{method: method, pattern: pattern, allow: false})
+	return p
+}
+
+// Retryable reports whether req may be retried: by the first rule matching
+// it, in the order they were added, then by its idempotency key, then by
+// its method.
+func (p *IdempotencyPolicy) Retryable(req *http.Request) bool {
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	for _, rule := range p.rules {
+		if rule.matches(method, req.URL.Path) {
+			return rule.allow
+		}
+	}
+	if p.KeyHeader != "" && req.Header.Get(p.KeyHeader) != "" {
+		return true
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// matches reports whether r applies to a request of method to urlPath.
+func (r idempotencyRule) matches(method, urlPath string) bool {
+	if r.method != "*" && !strings.EqualFold(r.method, method) {
+		return false
+	}
+	matched, err := path.Match(r.pattern, urlPath)
+	return err == nil && matched
+}
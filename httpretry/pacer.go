@@ -0,0 +1,117 @@
+package httpretry
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Pacer delays requests based on the rate limit state servers advertise, so
+// attempts are not burned against a quota that is known to be exhausted.
+//
+// Feed every response to Observe and call Wait before every request, or set
+// it as the Pacer of a Client, which does both:
+//   - When the quota is exhausted, Wait blocks until it resets.
+//   - When fewer than reserve requests remain, Wait spreads them evenly
+//     over the time left until the reset: each call reserves the next slot,
+//     so concurrent callers are sent one after the other, not together.
+//   - When a response carries Retry-After, Wait honors it.
+//
+// Observe only ever pushes the next request later: a response without rate
+// limit headers, or one that arrives late with more quota left, does not
+// erase what is known about an exhausted quota.
+//
+// A Pacer is safe for concurrent use; share one per rate-limited API.
+type Pacer struct {
+	mu        sync.Mutex
+	reserve   int
+	notBefore time.Time
+
+	// interval spaces the requests sent until paceUntil, the reset of a
+	// quota running low
+	interval  time.Duration
+	paceUntil time.Time
+}
+
+// NewPacer creates a Pacer that starts spreading requests once fewer than
+// reserve requests remain in the quota. With a reserve of 0, it only waits
+// when the quota is exhausted.
+func NewPacer(reserve int) *Pacer {
+	return &Pacer{reserve: reserve}
+}
+
+// Observe updates the pacing state from the headers of a response.
+func (p *Pacer) Observe(h http.Header) {
+	now := time.Now()
+	var notBefore, paceUntil time.Time
+	var interval time.Duration
+
+	if rl, ok := ParseRateLimit(h, now); ok && !rl.Reset.IsZero() {
+		switch {
+		case rl.Remaining <= 0:
+			notBefore = rl.Reset
+		case rl.Remaining < p.reserve:
+			interval = rl.Reset.Sub(now) / time.Duration(rl.Remaining+1)
+			notBefore, paceUntil = now.Add(interval), rl.Reset
+		}
+	}
+	if retryAfter, ok := ParseRetryAfter(h.Get("Retry-After"), now); ok {
+		if t := now.Add(retryAfter); t.After(notBefore) {
+			notBefore = t
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if notBefore.After(p.notBefore) {
+		p.notBefore = notBefore
+	}
+	if interval > 0 && (!now.Before(p.paceUntil) || interval > p.interval) {
+		p.interval, p.paceUntil = interval, paceUntil
+	}
+}
+
+// Delay returns how long the next request should wait, without reserving
+// its slot.
+func (p *Pacer) Delay() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if d := time.Until(p.notBefore); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Wait reserves the next slot to send a request in, and blocks until it
+// comes or ctx is done. The slot of a call that returns early is lost.
+func (p *Pacer) Wait(ctx context.Context) error {
+	d := p.reserveSlot()
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserveSlot returns how long until the next free slot, and moves the
+// following one an interval later while the quota is paced.
+func (p *Pacer) reserveSlot() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	at := now
+	if p.notBefore.After(at) {
+		at = p.notBefore
+	}
+	if p.interval > 0 && at.Before(p.paceUntil) {
+		p.notBefore = at.Add(p.interval)
+	}
+	return at.Sub(now)
+}
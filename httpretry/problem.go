@@ -0,0 +1,175 @@
+package httpretry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxHintBody is the number of body bytes inspected for retry hints.
+const maxHintBody = 64 << 10
+
+// Retryability is whether an error response body marks the failure as transient.
+type Retryability int
+
+const (
+	// RetryabilityUnknown means the body does not say.
+	RetryabilityUnknown Retryability = iota
+
+	// RetryabilityTransient means the body marks the failure as transient.
+	RetryabilityTransient
+
+	// RetryabilityPermanent means the body marks the failure as permanent.
+	RetryabilityPermanent
+)
+
+// ErrorHint is the retry guidance found in an error response body.
+type ErrorHint struct {
+	// Retryability is whether the failure is transient.
+	Retryability Retryability
+
+	// RetryAfter is the delay the body asks for, or 0 if it does not ask for one.
+	RetryAfter time.Duration
+}
+
+// transientStatuses lists the google.rpc.Code names that mark a failure as transient.
+var transientStatuses = map[string]bool{
+	"UNAVAILABLE":        true,
+	"RESOURCE_EXHAUSTED": true,
+	"DEADLINE_EXCEEDED":  true,
+	"ABORTED":            true,
+}
+
+// ParseErrorBody extracts retry hints from a JSON error response body.
+// It returns false if contentType is not JSON or the body carries no hint.
+//
+// Hints are looked up at the top level of the body and inside an "error" object:
+//   - RFC 7807/9457 problem details (application/problem+json) and similar
+//     envelopes with a "retryable", "retriable", or "transient" boolean and a
+//     "retry_after" or "retryAfter" value in seconds, HTTP-date, or "1.5s" form
+//   - Google API errors with a transient "status" (such as "UNAVAILABLE") and
+//     a google.rpc.RetryInfo "retryDelay" detail
+func ParseErrorBody(contentType string, body []byte, now time.Time) (ErrorHint, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+		return ErrorHint{}, false
+	}
+
+	var top map[string]any
+	if err := json.Unmarshal(body, &top); err != nil {
+		return ErrorHint{}, false
+	}
+	objects := []map[string]any{top}
+	if inner, ok := top["error"].(map[string]any); ok {
+		objects = append(objects, inner)
+	}
+
+	var hint ErrorHint
+	found := false
+	for _, obj := range objects {
+		for _, key := range []string{"retryable", "retriable", "transient", "is_transient", "isTransient"} {
+			if v, ok := obj[key].(bool); ok {
+				hint.Retryability = RetryabilityPermanent
+				if v {
+					hint.Retryability = RetryabilityTransient
+				}
+				found = true
+			}
+		}
+		for _, key := range []string{"retry_after", "retryAfter"} {
+			if d, ok := hintDelay(obj[key], now); ok {
+				hint.RetryAfter = d
+				found = true
+			}
+		}
+		if status, ok := obj["status"].(string); ok && transientStatuses[status] {
+			if hint.Retryability == RetryabilityUnknown {
+				hint.Retryability = RetryabilityTransient
+			}
+			found = true
+		}
+		if details, ok := obj["details"].([]any); ok {
+			for _, detail := range details {
+				d, ok := detail.(map[string]any)
+				if !ok {
+					continue
+				}
+				if typ, _ := d["@type"].(string); strings.HasSuffix(typ, "google.rpc.RetryInfo") {
+					if delay, ok := hintDelay(d["retryDelay"], now); ok {
+						hint.RetryAfter = delay
+						found = true
+					}
+				}
+			}
+		}
+	}
+	return hint, found
+}
+
+// hintDelay parses a delay given as a number of seconds, a Retry-After
+// string, or a protobuf JSON duration such as "1.5s".
+func hintDelay(v any, now time.Time) (time.Duration, bool) {
+	switch v := v.(type) {
+	case float64:
+		if v < 0 {
+			return 0, false
+		}
+		return time.Duration(v * float64(time.Second)), true
+	case string:
+		if seconds, ok := strings.CutSuffix(v, "s"); ok {
+			if f, err := strconv.ParseFloat(seconds, 64); err == nil && f >= 0 {
+				return time.Duration(f * float64(time.Second)), true
+			}
+		}
+		return ParseRetryAfter(v, now)
+	}
+	return 0, false
+}
+
+// ResponseErrorHint parses the retry hints of resp's body with ParseErrorBody.
+// The body is peeked, not consumed: resp.Body still yields the full body afterwards.
+func ResponseErrorHint(resp *http.Response) (ErrorHint, bool) {
+	if resp == nil || resp.Body == nil || resp.StatusCode < 400 {
+		return ErrorHint{}, false
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		return ErrorHint{}, false
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHintBody))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	if err != nil {
+		return ErrorHint{}, false
+	}
+	return ParseErrorBody(contentType, body, time.Now())
+}
+
+// ErrorBodyRetryPolicy is a CheckRetry that follows the retry hints of error
+// response bodies (see ParseErrorBody) and falls back to DefaultRetryPolicy
+// when the body does not say. It lets APIs that signal "transient" only in the
+// body get retried, and stops retrying 5xx responses marked permanent.
+func ErrorBodyRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	if err == nil {
+		if hint, ok := ResponseErrorHint(resp); ok {
+			switch hint.Retryability {
+			case RetryabilityTransient:
+				return true, nil
+			case RetryabilityPermanent:
+				return false, nil
+			}
+		}
+	}
+	return DefaultRetryPolicy(ctx, resp, err)
+}
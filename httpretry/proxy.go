@@ -0,0 +1,178 @@
+package httpretry
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/rohmanhakim/retrier/netretry"
+)
+
+// Egress is the route an attempt leaves through: a proxy, a local source
+// address, or both. The zero Egress sends directly from the default address.
+type Egress struct {
+	// Proxy is the proxy URL, or nil for none.
+	Proxy *url.URL
+
+	// LocalAddr is the local address to dial from, or nil for the default.
+	LocalAddr net.Addr
+}
+
+// key identifies e in the transport cache.
+func (e Egress) key() string {
+	var key string
+	if e.Proxy != nil {
+		key = e.Proxy.String()
+	}
+	if e.LocalAddr != nil {
+		key += "|" + e.LocalAddr.Network() + ":" + e.LocalAddr.String()
+	}
+	return key
+}
+
+// FailureKind classifies why an attempt failed, as far as the egress is concerned.
+type FailureKind int
+
+const (
+	// FailureOther is a failure unrelated to the egress, such as a 5xx
+	// response from the destination.
+	FailureOther FailureKind = iota
+
+	// FailureRateLimited is a 429 response, often counted per source IP.
+	FailureRateLimited
+
+	// FailureBlocked is a 403 response or a 407 from the proxy, typical of
+	// blocked source IPs and rejected proxies.
+	FailureBlocked
+
+	// FailureTransport is a transport error, such as an unreachable proxy.
+	FailureTransport
+)
+
+// Failure describes a failed attempt for a ProxyRotator.
+type Failure struct {
+	// Kind classifies the failure.
+	Kind FailureKind
+
+	// StatusCode is the status of the response, or 0 for transport errors.
+	StatusCode int
+
+	// Err is the transport error, or nil if a response was received.
+	Err error
+
+	// Egress is the egress the attempt used.
+	Egress Egress
+}
+
+// ClassifyFailure classifies the outcome of a failed attempt.
+func ClassifyFailure(resp *http.Response, err error) FailureKind {
+	switch {
+	case err != nil || resp == nil:
+		return FailureTransport
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return FailureRateLimited
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusProxyAuthRequired:
+		return FailureBlocked
+	default:
+		return FailureOther
+	}
+}
+
+// ProxyRotator chooses the egress of each attempt of a Client, so retries can
+// move away from a proxy or source address that is rate limited or blocked.
+// It is shared by the requests of the Client and must be safe for concurrent use.
+type ProxyRotator interface {
+	// Egress returns the egress of attempt (1-based). prev is the failure of
+	// the previous attempt of the same request, or nil for the first attempt.
+	Egress(attempt int, prev *Failure) Egress
+}
+
+// RoundRobinRotator cycles through a fixed list of egresses. It moves to the
+// next egress after rate-limited, blocked, and transport failures, and keeps
+// the current one after other failures, which retrying from another address
+// would not fix. The current egress is shared by all requests, so later
+// requests also avoid an egress that was just rate limited.
+type RoundRobinRotator struct {
+	mu       sync.Mutex
+	egresses []Egress
+	current  int
+}
+
+// NewRoundRobinRotator creates a RoundRobinRotator over egresses. With no
+// egresses, every attempt uses the zero Egress.
+func NewRoundRobinRotator(egresses ...Egress) *RoundRobinRotator {
+	return &RoundRobinRotator{egresses: egresses}
+}
+
+// Egress returns the current egress, after moving to the next one if prev
+// failed because of its egress.
+func (r *RoundRobinRotator) Egress(attempt int, prev *Failure) Egress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.egresses) == 0 {
+		return Egress{}
+	}
+	// Rotate once per failure, even if another request already moved past its egress
+	if prev != nil && prev.Kind != FailureOther && r.egresses[r.current].key() == prev.Egress.key() {
+		r.current = (r.current + 1) % len(r.egresses)
+	}
+	return r.egresses[r.current]
+}
+
+// errNoTransport is returned when a ProxyRotator or RotateAddresses is set but
+// the transport of the HTTP client cannot be cloned.
+var errNoTransport = errors.New("httpretry: ProxyRotator and RotateAddresses require the HTTP client's Transport to be nil or an *http.Transport")
+
+// egressTransport is a transport cloned for an egress.
+type egressTransport struct {
+	transport *http.Transport
+
+	// dialer rotates across resolved addresses, or is nil if the
+	// transport dials as its base does.
+	dialer *netretry.Dialer
+}
+
+// egressTransports caches one transport per egress, cloned from a base transport.
+type egressTransports struct {
+	mu         sync.Mutex
+	transports map[string]egressTransport
+}
+
+// get returns the transport for e, cloning base on first use. With rotate,
+// the transport dials through a netretry.Dialer.
+func (t *egressTransports) get(base http.RoundTripper, e Egress, rotate bool) (egressTransport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	baseTransport, ok := base.(*http.Transport)
+	if !ok {
+		return egressTransport{}, errNoTransport
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := e.key()
+	if cached, ok := t.transports[key]; ok {
+		return cached, nil
+	}
+	cached := egressTransport{transport: baseTransport.Clone()}
+	if e.Proxy != nil {
+		cached.transport.Proxy = http.ProxyURL(e.Proxy)
+	}
+	if e.LocalAddr != nil || rotate {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, LocalAddr: e.LocalAddr}
+		cached.transport.DialContext = dialer.DialContext
+		if rotate {
+			cached.dialer = &netretry.Dialer{Dialer: dialer}
+			cached.transport.DialContext = cached.dialer.DialContext
+		}
+	}
+	if t.transports == nil {
+		t.transports = make(map[string]egressTransport)
+	}
+	t.transports[key] = cached
+	return cached, nil
+}
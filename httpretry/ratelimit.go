@@ -0,0 +1,99 @@
+// Package httpretry provides HTTP-specific building blocks for the retrier package.
+package httpretry
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit is the quota state a server advertises in its response headers.
+type RateLimit struct {
+	// Limit is the quota size, or -1 if the server did not send it.
+	Limit int
+
+	// Remaining is the number of requests left in the current quota window.
+	Remaining int
+
+	// Reset is when the quota window resets, or the zero time if unknown.
+	Reset time.Time
+}
+
+// rateLimitHeaders lists the header name prefixes of the supported conventions,
+// in order of precedence:
+//   - IETF draft "RateLimit" fields (RateLimit-Remaining, reset in delta seconds)
+//   - X-RateLimit-* used by GitHub, GitLab, Discord, and most others
+//   - X-Rate-Limit-* used by Twitter and some older APIs
+var rateLimitHeaders = []string{"RateLimit-", "X-RateLimit-", "X-Rate-Limit-"}
+
+// ParseRateLimit extracts the rate limit state from h.
+// It returns false if h does not carry a recognized Remaining header.
+//
+// Reset values are interpreted as Unix timestamps (seconds or milliseconds)
+// when they are large enough to be one, and as delta seconds from now otherwise.
+// A Reset-After header (Discord) takes precedence over Reset.
+func ParseRateLimit(h http.Header, now time.Time) (RateLimit, bool) {
+	for _, prefix := range rateLimitHeaders {
+		remaining, err := strconv.Atoi(strings.TrimSpace(h.Get(prefix + "Remaining")))
+		if err != nil {
+			continue
+		}
+
+		rl := RateLimit{Limit: -1, Remaining: remaining}
+		if limit, err := strconv.Atoi(firstField(h.Get(prefix + "Limit"))); err == nil {
+			rl.Limit = limit
+		}
+		if after, err := strconv.ParseFloat(h.Get(prefix+"Reset-After"), 64); err == nil {
+			rl.Reset = now.Add(time.Duration(after * float64(time.Second)))
+		} else if reset, err := strconv.ParseFloat(h.Get(prefix+"Reset"), 64); err == nil {
+			rl.Reset = resetTime(reset, now)
+		}
+		return rl, true
+	}
+	return RateLimit{}, false
+}
+
+// ParseRetryAfter parses a Retry-After header value in either delta-seconds
+// or HTTP-date format. It returns false if value is empty or malformed.
+// Dates in the past yield a zero delay.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// resetTime converts a reset header value to an absolute time.
+func resetTime(value float64, now time.Time) time.Time {
+	switch {
+	case value >= 1e12: // Unix milliseconds
+		return time.UnixMilli(int64(value))
+	case value >= 1e9: // Unix seconds
+		return time.Unix(0, int64(value*float64(time.Second)))
+	default: // Delta seconds
+		return now.Add(time.Duration(value * float64(time.Second)))
+	}
+}
+
+// firstField returns the first comma- or semicolon-separated field of a header
+// value, so policy-annotated limits such as "100, 100;w=60" parse as 100.
+func firstField(value string) string {
+	if i := strings.IndexAny(value, ",;"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
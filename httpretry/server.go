@@ -0,0 +1,116 @@
+package httpretry
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rohmanhakim/retrier"
+)
+
+// Default bounds of the Retry-After an Unavailable handler advertises.
+const (
+	DefaultMinRetryAfter = 1 * time.Second
+	DefaultMaxRetryAfter = 5 * time.Minute
+)
+
+// HandlerFunc is an HTTP handler that returns the error it failed with
+// instead of writing an error response itself (see Unavailable).
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Unavailable turns the failures of downstream retry loops into
+// 503 Service Unavailable responses with a Retry-After header, so clients
+// back off for as long as the server itself would before retrying the
+// dependency, instead of piling more load on it.
+//
+// A handler fails with 503 when its error wraps a *retrier.RetryError that
+// may be retried later: attempts exhausted, retry budget or guard denied,
+// and so on. Other errors, including permanent ones and cancelled contexts,
+// go to OnError.
+//
+// The zero value is ready to use, and an Unavailable is safe for concurrent
+// use once configured.
+//
+// Example:
+//
+//	u := &httpretry.Unavailable{Options: inventoryOptions}
+//	mux.Handle("/stock", u.Handler(func(w http.ResponseWriter, r *http.Request) error {
+//	    result := retrier.RetryCtx(r.Context(), logger, fetchStock, inventoryOptions...)
+//	    if result.Err() != nil {
+//	        return result.Err()
+//	    }
+//	    return json.NewEncoder(w).Encode(result.Value())
+//	}))
+type Unavailable struct {
+	// Options are the retry options of the downstream retry loop. When the
+	// failure carries no delay suggested by the dependency, Retry-After is
+	// the backoff delay the loop would wait before its next attempt.
+	Options []retrier.RetryOption
+
+	// MinRetryAfter and MaxRetryAfter bound the Retry-After advertised.
+	// Zero selects DefaultMinRetryAfter and DefaultMaxRetryAfter.
+	MinRetryAfter time.Duration
+	MaxRetryAfter time.Duration
+
+	// OnError writes the response of errors that are not retryable
+	// failures of a retry loop. Default writes a 500 Internal Server Error.
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// Handler returns an http.Handler running h and writing the response of the
+// error it returns, if any.
+func (u *Unavailable) Handler(h HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h(w, r)
+		if err == nil {
+			return
+		}
+		if retryAfter, ok := u.RetryAfter(err); ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		if u.OnError != nil {
+			u.OnError(w, r, err)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	})
+}
+
+// RetryAfter returns the delay to advertise in the Retry-After header of the
+// 503 response to err, rounded up to whole seconds, and false if err is not a
+// retryable failure of a retry loop.
+//
+// The delay is the one the dependency suggested, such as its own
+// Retry-After, if the last attempt error carries one (see
+// retrier.DelaySuggestioner). Otherwise it is the backoff delay, jitter
+// included, that a loop configured with Options would wait after the
+// attempts the failure made.
+func (u *Unavailable) RetryAfter(err error) (time.Duration, bool) {
+	var retryErr *retrier.RetryError
+	if !errors.As(err, &retryErr) || retryErr.RetryPolicy() == retrier.RetryPolicyNever {
+		return 0, false
+	}
+
+	var delay time.Duration
+	var suggester retrier.DelaySuggestioner
+	if errors.As(retryErr.Unwrap(), &suggester) {
+		delay = suggester.SuggestedDelay()
+	}
+	if delay <= 0 {
+		delay = retrier.ResolveOptions(u.Options...).Delay(max(len(retryErr.History()), 1))
+	}
+
+	minDelay, maxDelay := u.MinRetryAfter, u.MaxRetryAfter
+	if minDelay <= 0 {
+		minDelay = DefaultMinRetryAfter
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxRetryAfter
+	}
+	delay = min(max(delay, minDelay), max(maxDelay, minDelay))
+	return time.Duration(math.Ceil(delay.Seconds())) * time.Second, true
+}
@@ -0,0 +1,95 @@
+package retrier
+
+import "time"
+
+// JitterMode is how WithJitterMode randomizes backoff delays, following the
+// strategies of the AWS Architecture Blog post "Exponential Backoff And
+// Jitter".
+type JitterMode int
+
+const (
+	// JitterAdditive adds a random duration below the WithJitter maximum to
+	// each delay.
+	JitterAdditive JitterMode = iota
+
+	// JitterFull draws each delay at random between 0 and the delay of the
+	// backoff curve. It spreads retries the most, at the cost of some
+	// retries coming almost immediately.
+	JitterFull
+
+	// JitterEqual keeps half of the delay of the backoff curve and draws the
+	// other half at random, so delays never drop below half the curve.
+	JitterEqual
+
+	// JitterDecorrelated draws each delay at random between the initial
+	// duration and three times the previous delay, capped by WithMaxDuration.
+	// Delays grow on average without following the multiplier, and calls
+	// that started together drift apart. With WithBackoff, it acts like
+	// JitterFull.
+	JitterDecorrelated
+)
+
+// String returns the name of m, such as "full".
+func (m JitterMode) String() string {
+	switch m {
+	case JitterAdditive:
+		return "additive"
+	case JitterFull:
+		return "full"
+	case JitterEqual:
+		return "equal"
+	case JitterDecorrelated:
+		return "decorrelated"
+	default:
+		return "unknown"
+	}
+}
+
+// decorrelatedGrowth is the factor by which JitterDecorrelated may grow a
+// delay from one retry to the next.
+const decorrelatedGrowth = 3
+
+// WithJitterMode sets how backoff delays are randomized. JitterAdditive, the
+// default, adds up to the WithJitter maximum to each delay; the other modes
+// randomize the delay itself and ignore WithJitter. Server-suggested delays
+// remain a minimum in every mode, and fast retries are not jittered.
+//
+// Example:
+//
+//	result := retrier.Retry(ctx, logger, fn,
+//	    retrier.WithInitialDuration(100*time.Millisecond),
+//	    retrier.WithMaxDuration(10*time.Second),
+//	    retrier.WithJitterMode(retrier.JitterDecorrelated),
+//	)
+func WithJitterMode(mode JitterMode) RetryOption {
+	return func(c *retryConfig) {
+		c.jitterMode = mode
+	}
+}
+
+// jitterDelay randomizes delay, the delay of the backoff curve before retry,
+// according to the jitter mode of c, drawing random durations with jitter.
+func (c *retryConfig) jitterDelay(delay time.Duration, jitter func(max time.Duration) time.Duration) time.Duration {
+	switch c.jitterMode {
+	case JitterFull, JitterDecorrelated:
+		return jitter(delay)
+	case JitterEqual:
+		half := delay / 2
+		return addDurations(half, jitter(delay-half))
+	default:
+		return addDurations(delay, jitter(c.jitter))
+	}
+}
+
+// decorrelatedDelay draws the delay of JitterDecorrelated following the
+// previous one, with jitter, scaled by weight, and returns it with the upper
+// bound it was drawn below before WithMaxDuration capped it.
+func (c *retryConfig) decorrelatedDelay(weight float64, jitter func(max time.Duration) time.Duration) (delay, raw time.Duration) {
+	base := min(c.initialDuration, c.maxDuration)
+	raw = scaleDuration(max(c.prevDelay, base), decorrelatedGrowth*weight)
+	delay = base
+	if upper := min(raw, c.maxDuration); upper > base {
+		delay = addDurations(base, jitter(upper-base))
+	}
+	return delay, raw
+}
@@ -0,0 +1,148 @@
+// Package k8sbackoff converts between retrier policies and the backoff types
+// used by Kubernetes libraries, so operators and controllers can share one
+// retry configuration language.
+//
+// The package does not import Kubernetes modules. WaitBackoff has the same
+// fields as k8s.io/apimachinery/pkg/util/wait.Backoff and converts to and from
+// it directly, and BackoffManager implements the client-go rest.BackoffManager
+// and rest.BackoffManagerWithContext interfaces structurally:
+//
+//	backoff := wait.Backoff(k8sbackoff.ToWaitBackoff(opts...))
+//	opts := k8sbackoff.FromWaitBackoff(k8sbackoff.WaitBackoff(backoff))
+package k8sbackoff
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/rohmanhakim/retrier"
+)
+
+// WaitBackoff mirrors k8s.io/apimachinery/pkg/util/wait.Backoff.
+// The two types are convertible to each other.
+type WaitBackoff struct {
+	// Duration is the initial delay.
+	Duration time.Duration
+
+	// Factor multiplies Duration after each step.
+	Factor float64
+
+	// Jitter adds up to Jitter*Duration of random delay to each step.
+	Jitter float64
+
+	// Steps is the number of times the condition is evaluated.
+	Steps int
+
+	// Cap limits Duration. Note that wait.Backoff stops stepping once the cap
+	// is reached, whereas retrier keeps retrying at the cap until MaxAttempts.
+	Cap time.Duration
+}
+
+// ToWaitBackoff converts retrier options to a WaitBackoff.
+// Jitter is converted from an absolute duration to a factor of the initial duration.
+func ToWaitBackoff(opts ...retrier.RetryOption) WaitBackoff {
+	o := retrier.ResolveOptions(opts...)
+	var jitter float64
+	if o.InitialDuration > 0 {
+		jitter = float64(o.Jitter) / float64(o.InitialDuration)
+	}
+	return WaitBackoff{
+		Duration: o.InitialDuration,
+		Factor:   o.Multiplier,
+		Jitter:   jitter,
+		Steps:    o.MaxAttempts,
+		Cap:      o.MaxDuration,
+	}
+}
+
+// FromWaitBackoff converts a WaitBackoff to retrier options.
+// A zero Factor becomes a multiplier of 1 (constant delay) and a zero Cap keeps
+// the retrier default maximum duration.
+func FromWaitBackoff(b WaitBackoff) []retrier.RetryOption {
+	factor := b.Factor
+	if factor <= 0 {
+		factor = 1
+	}
+	opts := []retrier.RetryOption{
+		retrier.WithMaxAttempts(b.Steps),
+		retrier.WithInitialDuration(b.Duration),
+		retrier.WithMultiplier(factor),
+		retrier.WithJitter(time.Duration(b.Jitter * float64(b.Duration))),
+	}
+	if b.Cap > 0 {
+		opts = append(opts, retrier.WithMaxDuration(b.Cap))
+	}
+	return opts
+}
+
+// BackoffManager implements client-go's rest.BackoffManager with a retrier
+// policy, tracking consecutive failures per host.
+// Transport errors, 429, and 5xx responses advance the host's backoff;
+// any other response resets it.
+type BackoffManager struct {
+	options  retrier.Options
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+// NewBackoffManager creates a BackoffManager using the delays of opts.
+func NewBackoffManager(opts ...retrier.RetryOption) *BackoffManager {
+	return &BackoffManager{
+		options:  retrier.ResolveOptions(opts...),
+		failures: make(map[string]int),
+	}
+}
+
+// UpdateBackoff records the outcome of a request to actualURL.
+func (m *BackoffManager) UpdateBackoff(actualURL *url.URL, err error, responseCode int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err != nil || responseCode == 429 || responseCode >= 500 {
+		m.failures[actualURL.Host]++
+		return
+	}
+	delete(m.failures, actualURL.Host)
+}
+
+// CalculateBackoff returns the delay before the next request to actualURL.
+func (m *BackoffManager) CalculateBackoff(actualURL *url.URL) time.Duration {
+	m.mu.Lock()
+	failures := m.failures[actualURL.Host]
+	m.mu.Unlock()
+
+	if failures == 0 {
+		return 0
+	}
+	return m.options.Delay(failures)
+}
+
+// Sleep sleeps for d.
+func (m *BackoffManager) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// UpdateBackoffWithContext implements rest.BackoffManagerWithContext.
+func (m *BackoffManager) UpdateBackoffWithContext(_ context.Context, actualURL *url.URL, err error, responseCode int) {
+	m.UpdateBackoff(actualURL, err, responseCode)
+}
+
+// CalculateBackoffWithContext implements rest.BackoffManagerWithContext.
+func (m *BackoffManager) CalculateBackoffWithContext(_ context.Context, actualURL *url.URL) time.Duration {
+	return m.CalculateBackoff(actualURL)
+}
+
+// SleepWithContext sleeps for d or until ctx is done.
+func (m *BackoffManager) SleepWithContext(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,95 @@
+package retrier
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// KeyedRetriers holds one Retrier per key (a host, shard, or tenant), so that
+// targets with different health do not share stats, budgets, or guards.
+// Retriers are created on first use and dropped once idle for longer than the
+// idle timeout. A KeyedRetriers is safe for concurrent use.
+type KeyedRetriers struct {
+	logger  DebugLogger
+	newOpts func(key string) []RetryOption
+	idle    time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*keyedEntry
+}
+
+type keyedEntry struct {
+	retrier  *Retrier
+	lastUsed time.Time
+}
+
+// NewKeyedRetriers creates a KeyedRetriers whose Retrier for a key logs to
+// logger and retries with newOpts(key). Create per-key state such as a
+// RetryBudget inside newOpts to isolate it per key. An idle timeout of 0 or
+// less keeps Retriers forever.
+//
+// Example:
+//
+//	hosts := retrier.NewKeyedRetriers(logger, 10*time.Minute, func(host string) []retrier.RetryOption {
+//	    return []retrier.RetryOption{retrier.WithBudget(retrier.NewRetryBudget(0.1))}
+//	})
+//	result := retrier.Do(ctx, hosts.Get(req.URL.Host), send)
+func NewKeyedRetriers(logger DebugLogger, idle time.Duration, newOpts func(key string) []RetryOption) *KeyedRetriers {
+	return &KeyedRetriers{
+		logger:  logger,
+		newOpts: newOpts,
+		idle:    idle,
+		entries: make(map[string]*keyedEntry),
+	}
+}
+
+// Get returns the Retrier of key, creating it if needed.
+func (k *KeyedRetriers) Get(key string) *Retrier {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := time.Now()
+	k.evictIdle(now)
+
+	entry, ok := k.entries[key]
+	if !ok {
+		entry = &keyedEntry{retrier: NewRetrier(k.logger, k.newOpts(key)...)}
+		k.entries[key] = entry
+	}
+	entry.lastUsed = now
+	return entry.retrier
+}
+
+// Remove drops the Retrier of key, if any.
+func (k *KeyedRetriers) Remove(key string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.entries, key)
+}
+
+// Keys returns the keys that currently have a Retrier, sorted.
+func (k *KeyedRetriers) Keys() []string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.evictIdle(time.Now())
+	keys := make([]string, 0, len(k.entries))
+	for key := range k.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// evictIdle drops Retriers that have not been used within the idle timeout.
+func (k *KeyedRetriers) evictIdle(now time.Time) {
+	if k.idle <= 0 {
+		return
+	}
+	for key, entry := range k.entries {
+		if now.Sub(entry.lastUsed) > k.idle {
+			delete(k.entries, key)
+		}
+	}
+}
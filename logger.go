@@ -17,7 +17,7 @@ type DebugLogger interface {
 	// Parameters:
 	//   - ctx: context for the retry operation
 	//   - attempt: current attempt number (1-based)
-	//   - maxAttempts: maximum number of attempts allowed
+	//   - maxAttempts: maximum number of attempts allowed (0 if unlimited)
 	//   - backoff: the delay before the next retry (0 for success/exhausted)
 	//   - err: the error that triggered the retry (nil on success)
 	//   - attrs: optional alternating key-value pairs (string, any, string, any, ...)
@@ -25,6 +25,43 @@ type DebugLogger interface {
 	LogRetry(ctx context.Context, attempt int, maxAttempts int, backoff time.Duration, err error, attrs ...any)
 }
 
+// WarningLogger is an optional interface for DebugLogger implementations that
+// also log warnings: conditions that do not stop the retry loop but deserve
+// attention, such as an exceeded soft attempt limit. Warnings are logged even
+// when Enabled returns false.
+type WarningLogger interface {
+	// LogWarning logs msg with optional alternating key-value pairs,
+	// following Go's slog convention.
+	LogWarning(ctx context.Context, msg string, attrs ...any)
+}
+
+// logTimeKey is the context key of the time of a logged event.
+type logTimeKey struct{}
+
+// LogTime returns the time of the event a DebugLogger or WarningLogger call
+// logs, according to the Clock of the retry loop (see WithClock), or the
+// current time if ctx does not come from a log call of a retry loop.
+// Timestamp log lines with it rather than time.Now, so logs produced under
+// a fake clock, in tests or simulations, follow its virtual time.
+//
+// Example:
+//
+//	func (l *Logger) LogRetry(ctx context.Context, attempt, maxAttempts int, backoff time.Duration, err error, attrs ...any) {
+//	    fmt.Printf("[%s] attempt %d/%d: %v\n", retrier.LogTime(ctx).Format(time.TimeOnly), attempt, maxAttempts, err)
+//	}
+func LogTime(ctx context.Context) time.Time {
+	if t, ok := ctx.Value(logTimeKey{}).(time.Time); ok {
+		return t
+	}
+	return time.Now()
+}
+
+// logContext returns ctx carrying the current time of the Clock of c, for
+// LogTime.
+func (c *retryConfig) logContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, logTimeKey{}, c.clock.Now())
+}
+
 // NoOpLogger is a no-operation implementation of DebugLogger.
 // It provides zero overhead when debug mode is disabled.
 // All methods are empty and Enabled() always returns false.
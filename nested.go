@@ -0,0 +1,72 @@
+package retrier
+
+import "context"
+
+// RetryNested runs fn under two policies: an inner loop retrying fn with
+// inner, nested in an outer loop retrying the whole inner loop with outer.
+// A typical pair is a short per-endpoint policy inside an across-endpoints
+// one, with fn picking the endpoint from outerAttempt (1-based).
+//
+// Attempts are attributed to their level rather than double counted:
+//   - Attempts of the Result counts outer attempts, and TotalAttempts the
+//     calls of fn across all inner loops.
+//   - An inner loop that exhausted its attempts fails its outer attempt with
+//     the error of its last attempt, not with a RetryError wrapping it, so
+//     the outer loop classifies the error fn returned and the final error
+//     wraps it once. Other inner failures, such as a permanent error or a
+//     cancelled context, fail the outer attempt unchanged.
+//   - Each AttemptError of the outer history (see Result.History and
+//     RetryError.History) carries the history of its inner loop in Inner.
+//
+// Both loops log to logger; add WithLogAttrs to inner or outer to tell their
+// lines apart.
+//
+// Example:
+//
+//	endpoints := []string{"https://eu.example.com", "https://us.example.com"}
+//	result := retrier.RetryNested(ctx, logger,
+//	    []retrier.RetryOption{retrier.WithMaxAttempts(len(endpoints))},
+//	    []retrier.RetryOption{retrier.WithMaxAttempts(3), retrier.WithInitialDuration(100 * time.Millisecond)},
+//	    func(ctx context.Context, outerAttempt int) (*Response, error) {
+//	        return call(ctx, endpoints[outerAttempt-1])
+//	    },
+//	)
+func RetryNested[T any](ctx context.Context, logger DebugLogger, outer, inner []RetryOption, fn func(ctx context.Context, outerAttempt int) (T, error)) Result[T] {
+	var outerAttempt, total int
+	inners := make(map[int][]AttemptError)
+
+	config := newConfig(outer)
+	// Outer attempts may fail before fn runs, so take their number from a hook
+	config.attemptHooks = append(config.attemptHooks, func(ctx context.Context, attempt int) (context.Context, func()) {
+		outerAttempt = attempt
+		return ctx, nil
+	})
+	result := retry(ctx, logger, func(ctx context.Context) (T, error) {
+		innerResult := RetryCtx(ctx, logger, func(ctx context.Context) (T, error) {
+			return fn(ctx, outerAttempt)
+		}, inner...)
+		total += innerResult.attempts
+		inners[outerAttempt] = innerResult.history
+
+		err := innerResult.err
+		if retryErr, ok := err.(*RetryError); ok && retryErr.Cause == ErrExhaustedAttempts && retryErr.wrapped != nil {
+			err = retryErr.wrapped
+		}
+		return innerResult.value, err
+	}, &config)
+
+	result.totalAttempts = total
+	attachInner(result.history, inners)
+	if retryErr, ok := result.err.(*RetryError); ok {
+		attachInner(retryErr.history, inners)
+	}
+	return result
+}
+
+// attachInner sets the Inner history of each outer attempt in history from
+// inners, the inner histories by outer attempt.
+func attachInner(history []AttemptError, inners map[int][]AttemptError) {
+	for i, h := range history {
+		history[i].Inner = inners[h.Attempt]
+	}
+}
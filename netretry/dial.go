@@ -0,0 +1,130 @@
+package netretry
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"slices"
+	"sync"
+)
+
+// Dialer resolves the host on every dial and rotates across its addresses.
+// Rotate a host after a failure that points at its current address, such as
+// a connection reset or timeout on an established connection, so the next
+// dials start at its next address instead of returning to a dead one.
+//
+// Addresses are tried in sorted order, starting at the rotation of the host,
+// until one connects. A Dialer is safe for concurrent use; its zero value
+// uses a zero net.Dialer and net.DefaultResolver.
+//
+// Example:
+//
+//	dialer := &netretry.Dialer{}
+//	transport := &http.Transport{DialContext: dialer.DialContext}
+type Dialer struct {
+	// Dialer connects to the resolved addresses. Default is a zero net.Dialer.
+	Dialer *net.Dialer
+
+	// Resolver resolves hosts. Default is net.DefaultResolver.
+	Resolver Resolver
+
+	mu    sync.Mutex
+	start map[string]int
+}
+
+// Resolver resolves host names. *net.Resolver implements it.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// DialContext connects to address on network, resolving its host first.
+// Addresses that are already IPs are dialed directly.
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := d.Dialer
+	if dialer == nil {
+		dialer = &net.Dialer{}
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := netip.ParseAddr(host); err == nil {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	addrs, err := d.resolve(ctx, network, host)
+	if err != nil {
+		return nil, err
+	}
+	start := d.startOf(host, len(addrs))
+	var firstErr error
+	for i := range addrs {
+		n := (start + i) % len(addrs)
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addrs[n].String(), port))
+		if err == nil {
+			d.setStart(host, n)
+			return conn, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, firstErr
+}
+
+// Rotate makes the next dials of host start at its next address.
+func (d *Dialer) Rotate(host string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.start == nil {
+		d.start = make(map[string]int)
+	}
+	d.start[host]++
+}
+
+// resolve looks up the addresses of host suitable for network, sorted.
+func (d *Dialer) resolve(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	var resolver Resolver = net.DefaultResolver
+	if d.Resolver != nil {
+		resolver = d.Resolver
+	}
+	ipNetwork := "ip"
+	switch network {
+	case "tcp4", "udp4":
+		ipNetwork = "ip4"
+	case "tcp6", "udp6":
+		ipNetwork = "ip6"
+	}
+	addrs, err := resolver.LookupNetIP(ctx, ipNetwork, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	for i, addr := range addrs {
+		addrs[i] = addr.Unmap()
+	}
+	slices.SortFunc(addrs, netip.Addr.Compare)
+	return slices.Compact(addrs), nil
+}
+
+// startOf returns the index of the first address to dial for host.
+func (d *Dialer) startOf(host string, n int) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.start[host] % n
+}
+
+// setStart records that host connected at index n, so later dials start there.
+func (d *Dialer) setStart(host string, n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.start == nil {
+		d.start = make(map[string]int)
+	}
+	d.start[host] = n
+}
@@ -0,0 +1,153 @@
+// Package netretry classifies network errors for retrying, and dials with a
+// fresh resolution on every connection (see Dialer).
+//
+// Classification is made of rules, each recognizing some errors and giving
+// them a retry policy. The first rule recognizing an error decides; errors no
+// rule recognizes follow the retrier defaults. The default rules are exported
+// so they can be reordered, dropped, or complemented:
+//
+//	result := retrier.Retry(ctx, logger, dial,
+//	    retrier.WithRetryIf(netretry.RetryIf(netretry.TLSRules()...)),
+//	)
+package netretry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// Rule classifies err, returning false if it does not recognize it.
+type Rule func(err error) (policy retrier.RetryPolicy, ok bool)
+
+// Classify returns the policy of the first rule recognizing err, or false if
+// none does.
+func Classify(err error, rules ...Rule) (retrier.RetryPolicy, bool) {
+	if err == nil {
+		return 0, false
+	}
+	for _, rule := range rules {
+		if policy, ok := rule(err); ok {
+			return policy, true
+		}
+	}
+	return 0, false
+}
+
+// RetryIf returns a predicate for retrier.WithRetryIf retrying the errors the
+// first matching rule classifies as RetryPolicyAuto. Errors no rule
+// recognizes are retried if retrier.IsTransient reports them transient.
+func RetryIf(rules ...Rule) func(err error) bool {
+	return func(err error) bool {
+		if policy, ok := Classify(err, rules...); ok {
+			return policy == retrier.RetryPolicyAuto
+		}
+		return retrier.IsTransient(err)
+	}
+}
+
+// DefaultTransientAlerts are the TLS alerts TLSRules treats as transient:
+// alerts a peer sends when it is shutting down or failing internally, or when
+// a record was corrupted in transit.
+var DefaultTransientAlerts = []tls.AlertError{
+	0,  // close_notify
+	20, // bad_record_mac
+	80, // internal_error
+	90, // user_canceled
+}
+
+// TLSRules returns the default TLS rules, in order: CertificateRule,
+// RecordHeaderRule, HandshakeTimeoutRule, and AlertRule with
+// DefaultTransientAlerts.
+func TLSRules() []Rule {
+	return []Rule{
+		CertificateRule,
+		RecordHeaderRule,
+		HandshakeTimeoutRule,
+		AlertRule(DefaultTransientAlerts...),
+	}
+}
+
+// CertificateRule classifies certificate validation failures (unknown
+// authority, expired or otherwise invalid certificate, hostname mismatch,
+// missing system roots) as permanent: they come from misconfiguration that
+// retrying only hides.
+func CertificateRule(err error) (retrier.RetryPolicy, bool) {
+	var (
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		invalidErr   x509.CertificateInvalidError
+		hostnameErr  x509.HostnameError
+		rootsErr     x509.SystemRootsError
+		extensionErr x509.UnhandledCriticalExtension
+	)
+	if errors.As(err, &verifyErr) || errors.As(err, &authorityErr) || errors.As(err, &invalidErr) ||
+		errors.As(err, &hostnameErr) || errors.As(err, &rootsErr) || errors.As(err, &extensionErr) {
+		return retrier.RetryPolicyNever, true
+	}
+	return 0, false
+}
+
+// RecordHeaderRule classifies a malformed TLS record header, typically from
+// speaking TLS to a plaintext port, as permanent.
+func RecordHeaderRule(err error) (retrier.RetryPolicy, bool) {
+	var headerErr tls.RecordHeaderError
+	if errors.As(err, &headerErr) {
+		return retrier.RetryPolicyNever, true
+	}
+	return 0, false
+}
+
+// HandshakeTimeoutRule classifies timeouts during the TLS handshake, such as
+// net/http's "TLS handshake timeout", as transient.
+func HandshakeTimeoutRule(err error) (retrier.RetryPolicy, bool) {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() && strings.Contains(strings.ToLower(err.Error()), "handshake") {
+		return retrier.RetryPolicyAuto, true
+	}
+	return 0, false
+}
+
+// AlertRule returns a rule classifying TLS alerts, sent by the peer or
+// returned by a QUIC connection, as transient if they are among transient
+// and as permanent otherwise. With no transient alerts, every alert is
+// permanent.
+func AlertRule(transient ...tls.AlertError) Rule {
+	return func(err error) (retrier.RetryPolicy, bool) {
+		alert, ok := alertOf(err)
+		if !ok {
+			return 0, false
+		}
+		for _, t := range transient {
+			if alert == t {
+				return retrier.RetryPolicyAuto, true
+			}
+		}
+		return retrier.RetryPolicyNever, true
+	}
+}
+
+// alertOf returns the TLS alert carried by err. Alerts received over TCP are
+// reported as a *net.OpError with Op "remote error" wrapping an unexported
+// type, recognized by its message.
+func alertOf(err error) (tls.AlertError, bool) {
+	var alert tls.AlertError
+	if errors.As(err, &alert) {
+		return alert, true
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) || opErr.Op != "remote error" || opErr.Err == nil {
+		return 0, false
+	}
+	msg := opErr.Err.Error()
+	for code := 0; code < 256; code++ {
+		if tls.AlertError(code).Error() == msg {
+			return tls.AlertError(code), true
+		}
+	}
+	return 0, false
+}
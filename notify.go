@@ -0,0 +1,178 @@
+package retrier
+
+import (
+	"context"
+	"time"
+)
+
+// EventKind is the kind of a RetryEvent.
+type EventKind int
+
+const (
+	// EventRetry is published when an attempt failed and the loop is about
+	// to sleep before the next one.
+	EventRetry EventKind = iota
+
+	// EventSuccess is published when an attempt succeeded.
+	EventSuccess
+
+	// EventFailure is published when the loop gave up, whatever the reason.
+	EventFailure
+
+	// EventSoftLimitExceeded is published once per loop, when the attempt at
+	// the soft limit failed and the loop retries anyway (see
+	// WithSoftMaxAttempts).
+	EventSoftLimitExceeded
+
+	// EventAttemptStuck is published when an attempt has been running longer
+	// than the threshold of WithWatchdog, once per attempt.
+	EventAttemptStuck
+
+	// EventUnclassified is published when an attempt failed with an error
+	// only the default retry policy would decide, under
+	// WithStrictClassification.
+	EventUnclassified
+)
+
+// String returns the name of the kind.
+func (k EventKind) String() string {
+	switch k {
+	case EventRetry:
+		return "retry"
+	case EventSuccess:
+		return "success"
+	case EventFailure:
+		return "failure"
+	case EventSoftLimitExceeded:
+		return "soft_limit_exceeded"
+	case EventAttemptStuck:
+		return "attempt_stuck"
+	case EventUnclassified:
+		return "unclassified"
+	default:
+		return "unknown"
+	}
+}
+
+// RetryEvent describes a step of a retry loop, published by WithNotifyChannel.
+type RetryEvent struct {
+	// Kind is what happened.
+	Kind EventKind
+
+	// Attempt is the number of the attempt that failed (EventRetry,
+	// EventUnclassified) or is stuck (EventAttemptStuck), or the number of
+	// attempts made (EventSuccess, EventFailure).
+	Attempt int
+
+	// MaxAttempts is the configured maximum number of attempts, or 0 if the
+	// loop has none (see WithZeroAttempts).
+	MaxAttempts int
+
+	// Backoff is the delay before the next attempt, for EventRetry.
+	Backoff time.Duration
+
+	// RawBackoff is the delay the backoff curve computed for EventRetry,
+	// before WithMaxDuration capped it and jitter was added; with WithBackoff,
+	// the delay of the strategy. Comparing it with Backoff shows whether the
+	// cap is in effect. It saturates at the largest time.Duration rather than
+	// overflowing.
+	RawBackoff time.Duration
+
+	// Err is the error of the failed attempt (EventRetry) or the error
+	// returned by the loop (EventFailure), redacted like logged errors.
+	Err error
+
+	// Cause is why the context of the failed attempt was cancelled, for
+	// EventRetry, as in AttemptError.Cause, redacted like Err.
+	Cause error
+
+	// Time is when the event happened, according to the configured Clock.
+	Time time.Time
+
+	// Decision explains why the loop retried (EventRetry) or stopped
+	// (EventFailure), when WithExplain is set; nil otherwise.
+	Decision *Decision
+}
+
+// NotifyMode is what WithNotifyChannel does when the channel is full.
+type NotifyMode int
+
+const (
+	// NotifyDrop drops the event, so a slow consumer never delays retries.
+	NotifyDrop NotifyMode = iota
+
+	// NotifyBlock waits until the channel accepts the event or the context of
+	// the retry loop is done, in which case the event is dropped.
+	NotifyBlock
+)
+
+// WithNotifyChannel publishes a RetryEvent to ch before each backoff delay and
+// when the loop returns. The caller owns ch and must not close it while retry
+// loops may publish to it. Events that ch cannot accept are dropped, unless
+// WithNotifyMode selects NotifyBlock. Default is none.
+//
+// Example:
+//
+//	events := make(chan retrier.RetryEvent, 64)
+//	go func() {
+//	    for e := range events {
+//	        metrics.Count("retry_"+e.Kind.String(), 1)
+//	    }
+//	}()
+//	result := retrier.Retry(ctx, logger, fn, retrier.WithNotifyChannel(events))
+func WithNotifyChannel(ch chan<- RetryEvent) RetryOption {
+	return func(c *retryConfig) {
+		c.notify = ch
+	}
+}
+
+// WithNotifyMode sets what WithNotifyChannel does when its channel is full.
+// Default is NotifyDrop.
+func WithNotifyMode(mode NotifyMode) RetryOption {
+	return func(c *retryConfig) {
+		c.notifyMode = mode
+	}
+}
+
+// publish sends e to the notify channel, if any.
+func (c *retryConfig) publish(ctx context.Context, e RetryEvent) {
+	if c.notify == nil {
+		return
+	}
+	e.MaxAttempts = c.maxAttempts
+	e.Time = c.clock.Now()
+	select {
+	case c.notify <- e:
+		return
+	default:
+	}
+	if c.notifyMode == NotifyBlock {
+		select {
+		case c.notify <- e:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// publishOutcome publishes the EventSuccess or EventFailure ending a loop
+// that returned err after attempts, following decision, if any.
+func (c *retryConfig) publishOutcome(ctx context.Context, attempts int, err error, decision *Decision) {
+	if c.notify == nil {
+		return
+	}
+	if err == nil {
+		c.publish(ctx, RetryEvent{Kind: EventSuccess, Attempt: attempts})
+		return
+	}
+	c.publish(ctx, RetryEvent{Kind: EventFailure, Attempt: attempts, Err: c.redactError(err), Decision: decision})
+}
+
+// warnSoftLimit reports that attempt, the soft attempt limit, failed with err
+// and the loop keeps retrying.
+func (c *retryConfig) warnSoftLimit(ctx context.Context, logger DebugLogger, attempt int, err error) {
+	c.publish(ctx, RetryEvent{Kind: EventSoftLimitExceeded, Attempt: attempt, Err: c.redactError(err)})
+	if warner, ok := logger.(WarningLogger); ok {
+		attrs := append([]any{"attempt", attempt, "soft_max_attempts", c.softMaxAttempts, "max_attempts", c.maxAttempts, "error", c.redactError(err)}, c.attrs...)
+		warner.LogWarning(c.logContext(ctx), "soft attempt limit exceeded", attrs...)
+	}
+}
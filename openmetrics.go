@@ -0,0 +1,173 @@
+package retrier
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OpenMetricsContentType is the Content-Type of the exposition written by
+// Registry.WriteOpenMetrics.
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// WriteOpenMetrics writes the telemetry of every registered Retrier to w in
+// the OpenMetrics text format, without depending on a Prometheus client. Each
+// sample carries the name of its Retrier in the "retrier" label:
+//   - retrier_calls_total, retrier_successes_total, retrier_failures_total,
+//     and retrier_retries_total count the calls of RetrierStats.
+//   - retrier_errors_total counts failures by error fingerprint, in the
+//     "fingerprint" label.
+//   - retrier_attempts is a histogram of the attempts made per call.
+//   - retrier_failures_total, retrier_retries_total, and
+//     retrier_errors_total carry the latest exemplar of a traced call, for
+//     Retriers with WithExemplars.
+//   - retrier_budget_requests, retrier_budget_retries, and
+//     retrier_budget_denied are the retry budget counters of the current
+//     window, and retrier_guard_active and retrier_guard_max the state of the
+//     retry guard, for Retriers that have one.
+//
+// Example:
+//
+//	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+//	    w.Header().Set("Content-Type", retrier.OpenMetricsContentType)
+//	    _ = registry.WriteOpenMetrics(r.Context(), w)
+//	})
+func (reg *Registry) WriteOpenMetrics(ctx context.Context, w io.Writer) error {
+	m := metricSet{}
+	for _, name := range reg.Names() {
+		r, ok := reg.Get(name)
+		if !ok {
+			continue
+		}
+		label := metricLabels("retrier", name)
+
+		stats := r.Stats()
+		exemplars := r.Exemplars()
+		m.add("retrier_calls", "counter", "Calls that returned.", label, "_total", stats.Calls)
+		m.add("retrier_successes", "counter", "Calls that returned a value.", label, "_total", stats.Successes)
+		m.add("retrier_failures", "counter", "Calls that returned an error.", label, "_total", stats.Failures)
+		m.exemplar(exemplars.Failures)
+		m.add("retrier_retries", "counter", "Attempts made beyond the first of each call.", label, "_total", stats.Retries)
+		m.exemplar(exemplars.Retries)
+
+		errorCounts := r.ErrorCounts()
+		fingerprints := make([]string, 0, len(errorCounts))
+		for fingerprint := range errorCounts {
+			fingerprints = append(fingerprints, fingerprint)
+		}
+		sort.Strings(fingerprints)
+		for _, fingerprint := range fingerprints {
+			m.add("retrier_errors", "counter", "Failed calls by error fingerprint.",
+				metricLabels("retrier", name, "fingerprint", fingerprint), "_total", errorCounts[fingerprint])
+			if e, ok := exemplars.Errors[fingerprint]; ok {
+				m.exemplar(&e)
+			}
+		}
+
+		var cumulative int64
+		for i := range r.attemptCounts {
+			cumulative += r.attemptCounts[i].Load()
+			le := "+Inf"
+			if i < len(attemptBuckets) {
+				le = strconv.Itoa(attemptBuckets[i])
+			}
+			m.add("retrier_attempts", "histogram", "Attempts made per call.",
+				metricLabels("retrier", name, "le", le), "_bucket", cumulative)
+		}
+		m.add("retrier_attempts", "histogram", "", label, "_count", cumulative)
+		m.add("retrier_attempts", "histogram", "", label, "_sum", r.attempts.Load())
+
+		config := applyOptions(r.RetryOptions())
+		if config.budget != nil {
+			if budget, err := config.budget.Stats(ctx); err == nil {
+				m.add("retrier_budget_requests", "gauge", "Requests counted by the retry budget in the current window.", label, "", budget.Requests)
+				m.add("retrier_budget_retries", "gauge", "Retries granted by the retry budget in the current window.", label, "", budget.Retries)
+				m.add("retrier_budget_denied", "gauge", "Retries denied by the retry budget in the current window.", label, "", budget.Denied)
+			}
+		}
+		if config.guard != nil {
+			m.add("retrier_guard_active", "gauge", "Operations currently retrying under the retry guard.", label, "", int64(config.guard.Active()))
+			m.add("retrier_guard_max", "gauge", "Operations allowed to retry concurrently by the retry guard.", label, "", config.guard.max)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	m.write(bw)
+	fmt.Fprintln(bw, "# EOF")
+	return bw.Flush()
+}
+
+// metricFamily is a metric and its samples, in the order they were added.
+type metricFamily struct {
+	name    string
+	kind    string
+	help    string
+	samples []string
+}
+
+// metricSet collects the samples of metric families, since OpenMetrics
+// requires the samples of a family to be contiguous.
+type metricSet struct {
+	families []*metricFamily
+	byName   map[string]*metricFamily
+	last     *metricFamily // family of the sample added last
+}
+
+// add adds a sample of family name, of type kind, with the given labels,
+// name suffix, and value. help is used when the family is first added.
+func (m *metricSet) add(name, kind, help, labels, suffix string, value int64) {
+	if m.byName == nil {
+		m.byName = make(map[string]*metricFamily)
+	}
+	family, ok := m.byName[name]
+	if !ok {
+		family = &metricFamily{name: name, kind: kind, help: help}
+		m.byName[name] = family
+		m.families = append(m.families, family)
+	}
+	family.samples = append(family.samples, fmt.Sprintf("%s%s%s %d", name, suffix, labels, value))
+	m.last = family
+}
+
+// exemplar attaches e, if not nil, to the sample added last.
+func (m *metricSet) exemplar(e *Exemplar) {
+	if e == nil || m.last == nil {
+		return
+	}
+	family := m.last
+	last := len(family.samples) - 1
+	family.samples[last] += fmt.Sprintf(" # %s %d %s", metricLabels("trace_id", e.TraceID), e.Value,
+		strconv.FormatFloat(float64(e.Time.UnixMilli())/1000, 'f', 3, 64))
+}
+
+// write writes the families of m, with their metadata, to w.
+func (m *metricSet) write(w io.Writer) {
+	for _, family := range m.families {
+		fmt.Fprintf(w, "# TYPE %s %s\n", family.name, family.kind)
+		fmt.Fprintf(w, "# HELP %s %s\n", family.name, family.help)
+		for _, sample := range family.samples {
+			fmt.Fprintln(w, sample)
+		}
+	}
+}
+
+// metricLabels formats pairs of label names and values as a label set.
+func metricLabels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", pairs[i], labelEscaper.Replace(pairs[i+1]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// labelEscaper escapes label values as OpenMetrics requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
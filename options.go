@@ -0,0 +1,103 @@
+package retrier
+
+import (
+	"math"
+	"time"
+
+	exponentialbackoff "github.com/rohmanhakim/exponential-backoff"
+)
+
+// Options is a plain-value view of the backoff settings carried by RetryOptions.
+// It lets adapters and configuration tooling read resolved settings and
+// convert them back into RetryOptions.
+type Options struct {
+	MaxAttempts     int
+	InitialDuration time.Duration
+	Multiplier      float64
+	MaxDuration     time.Duration
+	Jitter          time.Duration
+	RetryPolicy     RetryPolicy
+}
+
+// ResolveOptions applies opts over the defaults and returns the resulting settings.
+// Options that do not affect the fields of Options are ignored.
+func ResolveOptions(opts ...RetryOption) Options {
+	config := newConfig(opts)
+	return Options{
+		MaxAttempts:     config.maxAttempts,
+		InitialDuration: config.initialDuration,
+		Multiplier:      config.multiplier,
+		MaxDuration:     config.maxDuration,
+		Jitter:          config.jitter,
+		RetryPolicy:     config.defaultRetryPolicy,
+	}
+}
+
+// RetryOptions converts o into the equivalent RetryOptions.
+func (o Options) RetryOptions() []RetryOption {
+	return []RetryOption{
+		WithMaxAttempts(o.MaxAttempts),
+		WithInitialDuration(o.InitialDuration),
+		WithMultiplier(o.Multiplier),
+		WithMaxDuration(o.MaxDuration),
+		WithJitter(o.Jitter),
+		WithDefaultPolicy(o.RetryPolicy),
+	}
+}
+
+// Delay returns the backoff delay Retry would wait before retry number retry
+// (1-based), including jitter but without server-suggested delays.
+func (o Options) Delay(retry int) time.Duration {
+	delay, _ := exponentialDelay(o.InitialDuration, o.MaxDuration, o.Multiplier, o.Jitter, retry, 0, 1)
+	return delay
+}
+
+// maxDelay is the largest delay, which saturating computations clamp to.
+const maxDelay = time.Duration(math.MaxInt64)
+
+// exponentialDelay computes the delay before retry number retry using
+// exponential backoff with jitter, scaled by weight. A positive serverDelay
+// raises the initial duration the curve starts from, and is a minimum
+// whatever the weight. raw is the delay of the curve before the
+// cap of maxDuration and jitter. Both saturate at maxDelay instead of
+// overflowing, whatever the multiplier and retry.
+func exponentialDelay(initial, maxDuration time.Duration, multiplier float64, jitter time.Duration, retry int, serverDelay time.Duration, weight float64) (delay, raw time.Duration) {
+	// Ensure initial doesn't exceed max for valid config
+	if initial > maxDuration {
+		initial = maxDuration
+	}
+	config := exponentialbackoff.MustConfig(initial, maxDuration, multiplier)
+	start := max(config.InitialDuration(), serverDelay)
+	raw = max(scaleDuration(start, math.Pow(config.Multiplier(), float64(retry-1))*weight), serverDelay)
+	return addDurations(min(raw, config.MaxDuration()), computeJitter(jitter)), raw
+}
+
+// scaleDuration returns d times factor, clamped to [0, maxDelay].
+func scaleDuration(d time.Duration, factor float64) time.Duration {
+	if d <= 0 {
+		return 0 // also avoids 0 * +Inf, which is NaN
+	}
+	scaled := float64(d) * factor
+	switch {
+	case math.IsNaN(scaled) || scaled >= float64(maxDelay):
+		return maxDelay
+	case scaled <= 0:
+		return 0
+	}
+	return time.Duration(scaled)
+}
+
+// addDurations returns a + b, clamped to [0, maxDelay]. Negative arguments
+// count as 0.
+func addDurations(a, b time.Duration) time.Duration {
+	a, b = max(a, 0), max(b, 0)
+	if a > maxDelay-b {
+		return maxDelay
+	}
+	return a + b
+}
+
+// computeJitter returns a random duration in [0, max).
+func computeJitter(max time.Duration) time.Duration {
+	return exponentialbackoff.ComputeJitter(max)
+}
@@ -0,0 +1,96 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MemoryStore is an in-process Store, for tests and for services that accept
+// losing pending messages on restart. It is safe for concurrent use.
+type MemoryStore struct {
+	mu      sync.Mutex
+	pending []Message
+	done    []Message
+	dead    []DeadMessage
+}
+
+// DeadMessage is a message that was dead-lettered.
+type DeadMessage struct {
+	Message
+	Reason error
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Save stores msg as pending.
+func (s *MemoryStore) Save(_ context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range s.pending {
+		if m.ID == msg.ID {
+			return fmt.Errorf("outbox: message %q already pending", msg.ID)
+		}
+	}
+	s.pending = append(s.pending, msg)
+	return nil
+}
+
+// Pending returns the pending messages, oldest first.
+func (s *MemoryStore) Pending(_ context.Context) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.pending...), nil
+}
+
+// MarkDone moves the message with id from pending to done.
+func (s *MemoryStore) MarkDone(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg, err := s.take(id)
+	if err != nil {
+		return err
+	}
+	s.done = append(s.done, msg)
+	return nil
+}
+
+// MarkDead moves the message with id from pending to dead.
+func (s *MemoryStore) MarkDead(_ context.Context, id string, reason error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg, err := s.take(id)
+	if err != nil {
+		return err
+	}
+	s.dead = append(s.dead, DeadMessage{Message: msg, Reason: reason})
+	return nil
+}
+
+// Done returns the delivered messages, in delivery order.
+func (s *MemoryStore) Done() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.done...)
+}
+
+// Dead returns the dead-lettered messages, in order.
+func (s *MemoryStore) Dead() []DeadMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DeadMessage(nil), s.dead...)
+}
+
+// take removes the pending message with id. s.mu must be held.
+func (s *MemoryStore) take(id string) (Message, error) {
+	for i, msg := range s.pending {
+		if msg.ID == id {
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			return msg, nil
+		}
+	}
+	return Message{}, fmt.Errorf("outbox: no pending message %q", id)
+}
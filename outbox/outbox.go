@@ -0,0 +1,140 @@
+// Package outbox implements a minimal transactional-outbox publisher on top of
+// retrier: a message is stored first, then delivered with retries until it is
+// acknowledged, and marked done or dead-lettered.
+//
+// Storing the message in the same database transaction as the business change
+// that produced it (by implementing Store over that database) guarantees that
+// the message is eventually published exactly when the change commits.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// Message is an event waiting to be published.
+type Message struct {
+	// ID identifies the message in the Store. Consumers can use it to
+	// deduplicate deliveries, which are at least once.
+	ID string
+
+	// Topic is the destination of the message.
+	Topic string
+
+	// Payload is the body of the message.
+	Payload []byte
+
+	// CreatedAt is when the message was stored.
+	CreatedAt time.Time
+}
+
+// Store persists messages until they are delivered or dead-lettered.
+type Store interface {
+	// Save stores msg as pending.
+	Save(ctx context.Context, msg Message) error
+
+	// Pending returns the pending messages, oldest first.
+	Pending(ctx context.Context) ([]Message, error)
+
+	// MarkDone records that the message with id was delivered.
+	MarkDone(ctx context.Context, id string) error
+
+	// MarkDead records that the message with id will not be delivered,
+	// because of reason.
+	MarkDead(ctx context.Context, id string, reason error) error
+}
+
+// DeliverFunc sends msg to its destination, returning nil once the destination
+// acknowledged it.
+type DeliverFunc func(ctx context.Context, msg Message) error
+
+// Publisher stores messages and delivers them with retries.
+type Publisher struct {
+	store   Store
+	deliver DeliverFunc
+	logger  retrier.DebugLogger
+	opts    []retrier.RetryOption
+}
+
+// NewPublisher creates a Publisher storing messages in store and delivering
+// them with deliver, retried with opts.
+//
+// Example:
+//
+//	p := outbox.NewPublisher(store, sendToKafka, logger,
+//	    retrier.WithMaxAttempts(10),
+//	    retrier.WithMaxDuration(5*time.Minute),
+//	)
+//	err := p.Publish(ctx, outbox.Message{ID: orderID, Topic: "orders", Payload: body})
+func NewPublisher(store Store, deliver DeliverFunc, logger retrier.DebugLogger, opts ...retrier.RetryOption) *Publisher {
+	return &Publisher{store: store, deliver: deliver, logger: logger, opts: opts}
+}
+
+// Publish stores msg and then delivers it. Once Save succeeds the message is
+// not lost: if delivery fails for good (attempts exhausted, or a non-retryable
+// error) the message is dead-lettered; if it is interrupted (ctx cancelled,
+// deliveries failing with context.Canceled or context.DeadlineExceeded, retry
+// budget exhausted, ...) it stays pending for Redeliver. Publish returns
+// the error of the failed delivery in both cases.
+func (p *Publisher) Publish(ctx context.Context, msg Message) error {
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now()
+	}
+	if err := p.store.Save(ctx, msg); err != nil {
+		return err
+	}
+	return p.publish(ctx, msg)
+}
+
+// Redeliver delivers every pending message, such as those left by a crash or
+// an interrupted Publish. It stops at the first interrupted delivery and
+// returns its error; dead-lettered messages do not stop it.
+func (p *Publisher) Redeliver(ctx context.Context) error {
+	pending, err := p.store.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	for _, msg := range pending {
+		if err := p.publish(ctx, msg); err != nil && !isFinal(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// publish delivers a stored msg and records the outcome.
+func (p *Publisher) publish(ctx context.Context, msg Message) error {
+	err := retrier.RetryCtx(ctx, p.logger, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, p.deliver(ctx, msg)
+	}, p.opts...).Err()
+
+	// Record the outcome even if ctx was cancelled meanwhile
+	storeCtx := context.WithoutCancel(ctx)
+	switch {
+	case err == nil:
+		return p.store.MarkDone(storeCtx, msg.ID)
+	case isFinal(err):
+		if markErr := p.store.MarkDead(storeCtx, msg.ID, err); markErr != nil {
+			return errors.Join(err, markErr)
+		}
+	}
+	return err
+}
+
+// isFinal reports whether a delivery error means the message will never be
+// delivered, as opposed to an interrupted delivery. Cancellations and
+// deadlines, such as those of a shutdown, only interrupt it.
+func isFinal(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var retryErr *retrier.RetryError
+	if !errors.As(err, &retryErr) {
+		// The delivery error itself, returned because it is not retryable
+		return true
+	}
+	return retryErr.Cause == retrier.ErrExhaustedAttempts
+}
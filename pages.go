@@ -0,0 +1,175 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrJobTimeout is the cause of the context of a RetryPages job once its
+// MaxElapsed budget is spent, and is wrapped by the error it returns then.
+var ErrJobTimeout = errors.New("job time budget exhausted")
+
+// Checkpoint is the progress of a RetryPages job.
+type Checkpoint struct {
+	// Cursor is the cursor of the next page to fetch; "" before the first.
+	Cursor string
+
+	// Pages is the number of pages handled so far.
+	Pages int
+
+	// Done reports whether the last page was handled.
+	Done bool
+}
+
+// CheckpointStore persists the Checkpoint of RetryPages jobs, so that a job
+// interrupted by a crash, a deploy, or its time budget resumes where it
+// stopped.
+type CheckpointStore interface {
+	// LoadCheckpoint returns the checkpoint of job, or the zero Checkpoint if
+	// none was saved.
+	LoadCheckpoint(ctx context.Context, job string) (Checkpoint, error)
+
+	// SaveCheckpoint stores cp as the checkpoint of job.
+	SaveCheckpoint(ctx context.Context, job string, cp Checkpoint) error
+}
+
+// RateLimiter paces calls shared by several loops. *rate.Limiter of
+// golang.org/x/time/rate implements it.
+type RateLimiter interface {
+	// Wait blocks until a call may be made or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// Pagination configures a RetryPages job.
+type Pagination struct {
+	// Job identifies the job in Store.
+	Job string
+
+	// Store persists the progress of the job. Nil means the job always
+	// starts from the first page.
+	Store CheckpointStore
+
+	// Limiter, if not nil, is waited for before every attempt to fetch a
+	// page, retries included.
+	Limiter RateLimiter
+
+	// MaxElapsed bounds the whole run of the job, all pages included; 0
+	// means no bound other than ctx. Once it is spent, RetryPages returns an
+	// error wrapping ErrJobTimeout, and the job resumes from its checkpoint
+	// on the next run.
+	MaxElapsed time.Duration
+}
+
+// RetryPages drives a long paginated job, such as a bulk export, to its end:
+// it fetches every page in order, retrying each fetch with opts, passes it to
+// handle, then saves a Checkpoint to p.Store. The job starts from the saved
+// checkpoint, so a job run again after a failure resumes after the last
+// handled page, and a finished job returns immediately.
+//
+// fetch receives the cursor of the page ("" for the first) and returns the
+// page with the cursor of the next one, "" after the last. handle is not
+// retried: when it fails, the job stops and the page is fetched again on the
+// next run. RetryPages returns the checkpoint reached, with the error of the
+// page that stopped the job, if any.
+//
+// Example:
+//
+//	limiter := rate.NewLimiter(rate.Limit(5), 1) // the API allows 5 calls/s
+//	cp, err := retrier.RetryPages(ctx, logger, retrier.Pagination{
+//	    Job:        "export-" + accountID,
+//	    Store:      checkpoints,
+//	    Limiter:    limiter,
+//	    MaxElapsed: time.Hour,
+//	}, func(ctx context.Context, cursor string) ([]Record, string, error) {
+//	    return api.Export(ctx, accountID, cursor)
+//	}, func(ctx context.Context, records []Record) error {
+//	    return sink.Write(ctx, records)
+//	}, retrier.WithMaxAttempts(8))
+func RetryPages[T any](ctx context.Context, logger DebugLogger, p Pagination, fetch func(ctx context.Context, cursor string) (T, string, error), handle func(ctx context.Context, page T) error, opts ...RetryOption) (Checkpoint, error) {
+	if p.MaxElapsed > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, p.MaxElapsed, ErrJobTimeout)
+		defer cancel()
+	}
+
+	var cp Checkpoint
+	if p.Store != nil {
+		loaded, err := p.Store.LoadCheckpoint(ctx, p.Job)
+		if err != nil {
+			return cp, fmt.Errorf("retrier: load checkpoint of job %q: %w", p.Job, err)
+		}
+		cp = loaded
+	}
+
+	for !cp.Done {
+		result := RetryCtx(ctx, logger, func(ctx context.Context) (fetched[T], error) {
+			if p.Limiter != nil {
+				if err := p.Limiter.Wait(ctx); err != nil {
+					return fetched[T]{}, err
+				}
+			}
+			page, next, err := fetch(ctx, cp.Cursor)
+			return fetched[T]{page: page, next: next}, err
+		}, opts...)
+		if result.err != nil {
+			return cp, fmt.Errorf("retrier: fetch page %d of job %q: %w", cp.Pages+1, p.Job, pageError(ctx, result.err))
+		}
+		if err := handle(ctx, result.value.page); err != nil {
+			return cp, fmt.Errorf("retrier: handle page %d of job %q: %w", cp.Pages+1, p.Job, pageError(ctx, err))
+		}
+
+		cp = Checkpoint{Cursor: result.value.next, Pages: cp.Pages + 1, Done: result.value.next == ""}
+		if p.Store != nil {
+			// The page is handled: save its progress even if ctx just ended
+			if err := p.Store.SaveCheckpoint(context.WithoutCancel(ctx), p.Job, cp); err != nil {
+				return cp, fmt.Errorf("retrier: save checkpoint of job %q: %w", p.Job, err)
+			}
+		}
+	}
+	return cp, nil
+}
+
+// fetched is a page of RetryPages with the cursor of the next one.
+type fetched[T any] struct {
+	page T
+	next string
+}
+
+// pageError returns err, also wrapping ErrJobTimeout if the job budget of
+// ctx is what ended it.
+func pageError(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, ErrJobTimeout) && !errors.Is(err, ErrJobTimeout) {
+		return fmt.Errorf("%w: %w", cause, err)
+	}
+	return err
+}
+
+// MemoryCheckpoints is an in-process CheckpointStore, for tests and for jobs
+// that accept starting over on restart. It is safe for concurrent use.
+type MemoryCheckpoints struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// NewMemoryCheckpoints creates an empty MemoryCheckpoints.
+func NewMemoryCheckpoints() *MemoryCheckpoints {
+	return &MemoryCheckpoints{checkpoints: make(map[string]Checkpoint)}
+}
+
+// LoadCheckpoint returns the checkpoint of job.
+func (s *MemoryCheckpoints) LoadCheckpoint(_ context.Context, job string) (Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkpoints[job], nil
+}
+
+// SaveCheckpoint stores cp as the checkpoint of job.
+func (s *MemoryCheckpoints) SaveCheckpoint(_ context.Context, job string, cp Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[job] = cp
+	return nil
+}
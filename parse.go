@@ -0,0 +1,108 @@
+package retrier
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParsePolicy parses a compact, one-line retry policy into RetryOptions, for
+// use in CLI flags and config files. The format is a backoff kind followed by
+// its arguments in parentheses:
+//
+//	exponential(100ms, x2, jitter=50ms, max=30s, attempts=5)
+//	constant(1s, attempts=3)
+//
+// The first unnamed duration is the initial delay and "xN" is the multiplier.
+// Named arguments are initial, multiplier, max, jitter, attempts, and policy
+// (auto, manual, or never). Durations must not be negative, and max must be
+// positive. constant is exponential with a multiplier of 1.
+// Settings that are not given keep their defaults.
+func ParsePolicy(s string) ([]RetryOption, error) {
+	s = strings.TrimSpace(s)
+	kind, args, ok := strings.Cut(s, "(")
+	if !ok || !strings.HasSuffix(args, ")") {
+		return nil, fmt.Errorf("retrier: invalid policy %q: expected kind(args...)", s)
+	}
+	args = strings.TrimSuffix(args, ")")
+
+	var opts []RetryOption
+	switch strings.TrimSpace(kind) {
+	case "exponential":
+	case "constant":
+		opts = append(opts, WithMultiplier(1))
+	default:
+		return nil, fmt.Errorf("retrier: invalid policy %q: unknown kind %q", s, strings.TrimSpace(kind))
+	}
+
+	positional := 0
+	for _, arg := range strings.Split(args, ",") {
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			continue
+		}
+		name, value, named := strings.Cut(arg, "=")
+		if !named {
+			value = arg
+			switch {
+			case strings.HasPrefix(arg, "x"):
+				name, value = "multiplier", arg[1:]
+			case positional == 0:
+				name = "initial"
+				positional++
+			default:
+				return nil, fmt.Errorf("retrier: invalid policy %q: unexpected argument %q", s, arg)
+			}
+		}
+		opt, err := policyArg(strings.TrimSpace(name), strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("retrier: invalid policy %q: %w", s, err)
+		}
+		opts = append(opts, opt)
+	}
+	return opts, nil
+}
+
+// policyArg converts one named ParsePolicy argument into a RetryOption.
+func policyArg(name, value string) (RetryOption, error) {
+	switch name {
+	case "initial", "max", "jitter":
+		d, err := time.ParseDuration(value)
+		// The backoff curve needs a positive cap
+		if err != nil || d < 0 || (name == "max" && d == 0) {
+			return nil, fmt.Errorf("invalid %s %q", name, value)
+		}
+		switch name {
+		case "initial":
+			return WithInitialDuration(d), nil
+		case "max":
+			return WithMaxDuration(d), nil
+		default:
+			return WithJitter(d), nil
+		}
+	case "multiplier":
+		m, err := strconv.ParseFloat(value, 64)
+		if err != nil || m < 1 {
+			return nil, fmt.Errorf("invalid multiplier %q", value)
+		}
+		return WithMultiplier(m), nil
+	case "attempts":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid attempts %q", value)
+		}
+		return WithMaxAttempts(n), nil
+	case "policy":
+		switch value {
+		case "auto":
+			return WithDefaultPolicy(RetryPolicyAuto), nil
+		case "manual":
+			return WithDefaultPolicy(RetryPolicyManual), nil
+		case "never":
+			return WithDefaultPolicy(RetryPolicyNever), nil
+		}
+		return nil, fmt.Errorf("invalid policy %q", value)
+	}
+	return nil, fmt.Errorf("unknown argument %q", name)
+}
@@ -0,0 +1,53 @@
+package retrier
+
+import "sync/atomic"
+
+// PolicyProvider supplies retry settings that can change at runtime, such as
+// settings from a file watcher, a feature flag system, or an admin endpoint.
+type PolicyProvider interface {
+	// RetryOptions returns the current settings. It is called at the start of
+	// every Retry call, so it must be cheap and safe for concurrent use.
+	RetryOptions() []RetryOption
+}
+
+// WithPolicyProvider makes Retry apply the options of p on top of the other
+// options at the start of every call, so settings can be changed for all
+// subsequent calls without restarting. A call in progress keeps the settings
+// it started with. Default is none.
+func WithPolicyProvider(p PolicyProvider) RetryOption {
+	return func(c *retryConfig) {
+		c.provider = p
+	}
+}
+
+// DynamicPolicy is a PolicyProvider whose options are replaced with Set.
+// Reads are a single atomic load. It is safe for concurrent use.
+type DynamicPolicy struct {
+	opts atomic.Pointer[[]RetryOption]
+}
+
+// NewDynamicPolicy creates a DynamicPolicy providing opts.
+func NewDynamicPolicy(opts ...RetryOption) *DynamicPolicy {
+	p := &DynamicPolicy{}
+	p.Set(opts...)
+	return p
+}
+
+// Set replaces the provided options for subsequent Retry calls.
+// Combined with ParsePolicy, it lets an admin endpoint damp retries at runtime:
+//
+//	opts, err := retrier.ParsePolicy("exponential(5s, attempts=2)")
+//	if err == nil {
+//	    policy.Set(opts...)
+//	}
+func (p *DynamicPolicy) Set(opts ...RetryOption) {
+	p.opts.Store(&opts)
+}
+
+// RetryOptions returns the current options.
+func (p *DynamicPolicy) RetryOptions() []RetryOption {
+	if opts := p.opts.Load(); opts != nil {
+		return *opts
+	}
+	return nil
+}
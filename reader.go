@@ -0,0 +1,115 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// errReaderClosed is returned by reads after Close.
+var errReaderClosed = errors.New("retrier: read from closed RetryReader")
+
+// NewRetryReader returns a reader over a stream that re-opens it when a read
+// fails and resumes where the failure happened, so consumers streaming from
+// object storage or HTTP survive mid-stream resets without buffering the
+// whole payload. factory opens the stream at offset, the number of bytes
+// read so far: 0 initially, more when resuming.
+//
+// Each Read that fails is retried with opts: every attempt re-opens the
+// stream at the current offset and reads from it, so MaxAttempts bounds the
+// consecutive failures, not the failures over the whole stream. Failures
+// that opts do not retry, and exhausted attempts, are returned by Read and
+// every later Read. Close closes the current stream.
+//
+// Example:
+//
+//	body := retrier.NewRetryReader(ctx, logger, func(offset int64) (io.ReadCloser, error) {
+//	    return bucket.NewRangeReader(ctx, key, offset, -1)
+//	}, retrier.WithMaxAttempts(5))
+//	defer body.Close()
+//	_, err := io.Copy(dst, body)
+func NewRetryReader(ctx context.Context, logger DebugLogger, factory func(offset int64) (io.ReadCloser, error), opts ...RetryOption) io.ReadCloser {
+	return &retryReader{ctx: ctx, logger: logger, factory: factory, opts: opts}
+}
+
+// retryReader is the reader of NewRetryReader.
+type retryReader struct {
+	ctx     context.Context
+	logger  DebugLogger
+	factory func(offset int64) (io.ReadCloser, error)
+	opts    []RetryOption
+
+	stream io.ReadCloser
+	offset int64
+	err    error
+	closed bool
+}
+
+// Read reads from the current stream, re-opening it after failures.
+func (r *retryReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, errReaderClosed
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	var eof bool
+	result := Retry(r.ctx, r.logger, func() (int, error) {
+		if r.stream == nil {
+			stream, err := r.factory(r.offset)
+			if err != nil {
+				return 0, err
+			}
+			r.stream = stream
+		}
+		n, err := r.stream.Read(p)
+		r.offset += int64(n)
+		switch {
+		case err == nil:
+		case err == io.EOF:
+			eof = true
+		case n > 0:
+			// Return the bytes read; the next Read resumes after them
+			r.drop()
+		default:
+			r.drop()
+			return 0, err
+		}
+		return n, nil
+	}, r.opts...)
+
+	n, _, err := result.Decompose()
+	if err != nil {
+		r.err = err
+		return 0, err
+	}
+	if eof {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Close closes the current stream. Reads after Close fail.
+func (r *retryReader) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	if r.stream == nil {
+		return nil
+	}
+	err := r.stream.Close()
+	r.stream = nil
+	return err
+}
+
+// drop closes the current stream after a failure, so the next attempt
+// re-opens it.
+func (r *retryReader) drop() {
+	_ = r.stream.Close()
+	r.stream = nil
+}
@@ -0,0 +1,212 @@
+package retrier
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// recommendMinLoops is the number of loops PolicyStats needs before it
+	// recommends anything.
+	recommendMinLoops = 50
+
+	// successQuantile is the share of successes MaxAttempts should cover.
+	successQuantile = 0.99
+
+	// cappedShare is the share of capped retries above which the backoff cap
+	// is considered too low.
+	cappedShare = 0.8
+
+	// exhaustedShare is the share of loops exhausting their attempts above
+	// which MaxAttempts is considered too low, when late attempts still
+	// succeed.
+	exhaustedShare = 0.05
+)
+
+// PolicyStats collects the Summaries of retry loops to recommend changes to
+// their policy (see Recommend). Pass its Record method to WithSummarySink,
+// typically on a Retrier, to collect them. A PolicyStats is safe for
+// concurrent use.
+type PolicyStats struct {
+	mu        sync.Mutex
+	loops     int64
+	successes []int64 // successful loops by number of attempts, from 1
+	exhausted int64
+	retries   int64
+	capped    int64
+	backoff   time.Duration
+	latency   time.Duration
+}
+
+// NewPolicyStats creates an empty PolicyStats.
+func NewPolicyStats() *PolicyStats {
+	return &PolicyStats{}
+}
+
+// Record adds the Summary of a loop to s. It is a SummarySink.
+func (s *PolicyStats) Record(_ context.Context, sum Summary) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.loops++
+	s.retries += int64(max(sum.Attempts-1, 0))
+	s.capped += int64(sum.CappedRetries)
+	s.backoff += sum.BackoffLatency
+	s.latency += sum.TotalLatency
+	switch {
+	case sum.Succeeded() && sum.Attempts > 0:
+		for len(s.successes) < sum.Attempts {
+			s.successes = append(s.successes, 0)
+		}
+		s.successes[sum.Attempts-1]++
+	case sum.FailureKind == string(ErrExhaustedAttempts):
+		s.exhausted++
+	}
+}
+
+// Recommendation is a suggested change to a setting of Options.
+type Recommendation struct {
+	// Setting is the name of the field of Options to change, such as "MaxAttempts".
+	Setting string
+
+	// Current is the current value of the setting.
+	Current string
+
+	// Suggested is the suggested value of the setting.
+	Suggested string
+
+	// Reason is the observation behind the suggestion.
+	Reason string
+}
+
+// String returns the recommendation as a sentence.
+func (r Recommendation) String() string {
+	return fmt.Sprintf("%s; change %s from %s to %s", r.Reason, r.Setting, r.Current, r.Suggested)
+}
+
+// PolicyReport is the analysis of the loops collected by a PolicyStats.
+type PolicyReport struct {
+	// Loops is the number of loops analyzed.
+	Loops int64
+
+	// Successes is the number of loops that succeeded.
+	Successes int64
+
+	// Retries is the number of attempts made beyond the first of each loop.
+	Retries int64
+
+	// CappedRetries is the number of retries whose backoff delay was capped
+	// by MaxDuration.
+	CappedRetries int64
+
+	// BackoffShare is the share of the total latency of the loops spent in
+	// backoff delays, from 0 to 1.
+	BackoffShare float64
+
+	// SuccessesByAttempt counts the successful loops by the attempt they
+	// succeeded at: SuccessesByAttempt[0] succeeded at the first attempt.
+	SuccessesByAttempt []int64
+
+	// Recommendations are the suggested changes, empty if the policy fits
+	// the loops or fewer than 50 loops were collected.
+	Recommendations []Recommendation
+}
+
+// Recommend analyzes the loops collected so far, run with the settings of
+// current, and suggests changes to them:
+//   - MaxAttempts is reduced when 99% of successes happen before the last
+//     attempt, so that failing calls give up sooner.
+//   - MaxAttempts is raised when loops often exhaust their attempts while
+//     the last attempt still turns failures into successes.
+//   - MaxDuration is raised when the cap is reached in 80% of retries, since
+//     the backoff no longer grows.
+//
+// Example:
+//
+//	stats := retrier.NewPolicyStats()
+//	r := retrier.NewRetrier(logger, retrier.WithMaxAttempts(5), retrier.WithSummarySink(stats.Record))
+//	// ... later, from a debug endpoint or a periodic job
+//	for _, rec := range stats.Recommend(r.Options()).Recommendations {
+//	    log.Println(rec) // 99% of successes happen by attempt 2; change MaxAttempts from 5 to 2
+//	}
+func (s *PolicyStats) Recommend(current Options) PolicyReport {
+	s.mu.Lock()
+	report := PolicyReport{
+		Loops:              s.loops,
+		Retries:            s.retries,
+		CappedRetries:      s.capped,
+		SuccessesByAttempt: append([]int64(nil), s.successes...),
+	}
+	exhausted := s.exhausted
+	if s.latency > 0 {
+		report.BackoffShare = float64(s.backoff) / float64(s.latency)
+	}
+	s.mu.Unlock()
+
+	for _, n := range report.SuccessesByAttempt {
+		report.Successes += n
+	}
+	if report.Loops < recommendMinLoops {
+		return report
+	}
+
+	if rec, ok := recommendMaxAttempts(current, report, exhausted); ok {
+		report.Recommendations = append(report.Recommendations, rec)
+	}
+	if report.Retries > 0 && float64(report.CappedRetries) >= cappedShare*float64(report.Retries) {
+		report.Recommendations = append(report.Recommendations, Recommendation{
+			Setting:   "MaxDuration",
+			Current:   current.MaxDuration.String(),
+			Suggested: (2 * current.MaxDuration).String(),
+			Reason:    fmt.Sprintf("backoff cap reached in %.0f%% of retries", 100*float64(report.CappedRetries)/float64(report.Retries)),
+		})
+	}
+	return report
+}
+
+// recommendMaxAttempts suggests a MaxAttempts for the successes of report
+// and the number of loops that exhausted their attempts, if current does not
+// fit them.
+func recommendMaxAttempts(current Options, report PolicyReport, exhausted int64) (Recommendation, bool) {
+	if report.Successes == 0 {
+		return Recommendation{}, false
+	}
+
+	// The attempt by which successQuantile of the successes happened
+	var covered int64
+	quantile := len(report.SuccessesByAttempt)
+	for i, n := range report.SuccessesByAttempt {
+		covered += n
+		if float64(covered) >= successQuantile*float64(report.Successes) {
+			quantile = i + 1
+			break
+		}
+	}
+
+	lastAttempt := current.MaxAttempts
+	var lateSuccesses int64
+	if lastAttempt > 1 && lastAttempt <= len(report.SuccessesByAttempt) {
+		lateSuccesses = report.SuccessesByAttempt[lastAttempt-1]
+	}
+
+	switch {
+	case quantile < current.MaxAttempts:
+		return Recommendation{
+			Setting:   "MaxAttempts",
+			Current:   fmt.Sprint(current.MaxAttempts),
+			Suggested: fmt.Sprint(quantile),
+			Reason:    fmt.Sprintf("%.0f%% of successes happen by attempt %d", 100*successQuantile, quantile),
+		}, true
+	case lateSuccesses > 0 && float64(exhausted) >= exhaustedShare*float64(report.Loops):
+		return Recommendation{
+			Setting:   "MaxAttempts",
+			Current:   fmt.Sprint(current.MaxAttempts),
+			Suggested: fmt.Sprint(current.MaxAttempts + max(current.MaxAttempts/2, 1)),
+			Reason: fmt.Sprintf("%.0f%% of calls exhaust their attempts while %d succeeded at the last one",
+				100*float64(exhausted)/float64(report.Loops), lateSuccesses),
+		}, true
+	}
+	return Recommendation{}, false
+}
@@ -0,0 +1,45 @@
+package retrier
+
+// Redactor rewrites error text before it leaves the retry loop, for example
+// to mask tokens or personal data carried by the errors of failed requests.
+type Redactor func(text string) string
+
+// WithRedactor sets the Redactor applied to error text passed to the
+// DebugLogger and the WithOnRetry callback, to the message of returned
+// RetryErrors, and to error fingerprints used in statistics and summaries.
+// The errors themselves are kept, so errors.Is and errors.As still see the
+// original chain. Set it on a Retrier to cover all of its calls.
+// Default is no redaction.
+func WithRedactor(r Redactor) RetryOption {
+	return func(c *retryConfig) {
+		c.redactor = r
+	}
+}
+
+// redactedError presents err with its message rewritten by redact.
+type redactedError struct {
+	err    error
+	redact Redactor
+}
+
+func (e *redactedError) Error() string {
+	return e.redact(e.err.Error())
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactError returns err with its message redacted, or err itself when no
+// Redactor is set.
+func (c *retryConfig) redactError(err error) error {
+	if c.redactor == nil || err == nil {
+		return err
+	}
+	return &redactedError{err: err, redact: c.redactor}
+}
+
+// fingerprint returns the fingerprint of err with its message redacted.
+func (c *retryConfig) fingerprint(err error) string {
+	return c.fingerprinter(c.redactError(err))
+}
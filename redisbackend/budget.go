@@ -0,0 +1,73 @@
+package redisbackend
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// addScript increments a counter and sets its expiry when the counter is new,
+// atomically so that concurrent replicas never leave a counter without a TTL.
+const addScript = `local v = redis.call("INCRBY", KEYS[1], ARGV[1])
+if v == tonumber(ARGV[1]) then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return v`
+
+// BudgetStore implements retrier.BudgetStore with Redis counters, so a
+// retrier.RetryBudget is enforced across every process sharing the store.
+type BudgetStore struct {
+	client Client
+	prefix string
+}
+
+// NewBudgetStore creates a BudgetStore storing counters under keys prefixed with prefix.
+func NewBudgetStore(client Client, prefix string) *BudgetStore {
+	return &BudgetStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Add increments the counter at key by delta and returns the new value.
+func (s *BudgetStore) Add(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	ttlMillis := ttl.Milliseconds()
+	if ttlMillis < 1 {
+		ttlMillis = 1
+	}
+	reply, err := s.client.Do(ctx, "EVAL", addScript, 1, s.prefix+key, delta, ttlMillis)
+	if err != nil {
+		return 0, fmt.Errorf("redisbackend: add %q: %w", key, err)
+	}
+	return toInt64(reply)
+}
+
+// Get returns the current value of the counter at key, or 0 if it does not exist.
+func (s *BudgetStore) Get(ctx context.Context, key string) (int64, error) {
+	reply, err := s.client.Do(ctx, "GET", s.prefix+key)
+	if err != nil {
+		return 0, fmt.Errorf("redisbackend: get %q: %w", key, err)
+	}
+	if reply == nil {
+		return 0, nil
+	}
+	return toInt64(reply)
+}
+
+// toInt64 converts the integer and bulk string replies produced by common
+// Redis drivers into an int64.
+func toInt64(reply any) (int64, error) {
+	switch v := reply.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	case []byte:
+		return strconv.ParseInt(string(v), 10, 64)
+	default:
+		return 0, fmt.Errorf("redisbackend: unexpected reply type %T", reply)
+	}
+}
@@ -0,0 +1,87 @@
+// Package redisbackend provides Redis-based implementations of the retrier
+// interfaces that coordinate retries across processes.
+//
+// The package does not depend on a particular Redis driver. Instead it talks to
+// Redis through the small Client interface, which any driver can satisfy with a
+// one-line adapter. For example, with github.com/redis/go-redis:
+//
+//	type goRedisClient struct{ c *redis.Client }
+//
+//	func (g goRedisClient) Do(ctx context.Context, args ...any) (any, error) {
+//	    v, err := g.c.Do(ctx, args...).Result()
+//	    if errors.Is(err, redis.Nil) {
+//	        return nil, nil
+//	    }
+//	    return v, err
+//	}
+package redisbackend
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Client executes a single Redis command.
+// args holds the command name followed by its arguments, e.g. "SET", key, value.
+// A nil reply must be reported as (nil, nil) rather than as an error.
+type Client interface {
+	Do(ctx context.Context, args ...any) (any, error)
+}
+
+// releaseScript deletes the lease only if it is still owned by the caller,
+// so an instance whose lease expired cannot release a lease taken by another one.
+const releaseScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`
+
+// Coordinator implements retrier.Coordinator with Redis leases.
+// Each lease is a key set with NX and a millisecond TTL whose value identifies
+// the owning Coordinator, so only the owner can release it.
+type Coordinator struct {
+	client Client
+	prefix string
+	token  string
+}
+
+// NewCoordinator creates a Coordinator storing leases under keys prefixed with prefix.
+// Each Coordinator has its own owner token; use one per process.
+func NewCoordinator(client Client, prefix string) *Coordinator {
+	return &Coordinator{
+		client: client,
+		prefix: prefix,
+		token:  newToken(),
+	}
+}
+
+// Acquire takes the lease for key if nobody holds it.
+// ttl is rounded down to whole milliseconds, with a minimum of 1ms.
+func (c *Coordinator) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	ttlMillis := ttl.Milliseconds()
+	if ttlMillis < 1 {
+		ttlMillis = 1
+	}
+	reply, err := c.client.Do(ctx, "SET", c.prefix+key, c.token, "NX", "PX", ttlMillis)
+	if err != nil {
+		return false, fmt.Errorf("redisbackend: acquire %q: %w", key, err)
+	}
+	return reply != nil, nil
+}
+
+// Release gives up the lease for key if this Coordinator still owns it.
+func (c *Coordinator) Release(ctx context.Context, key string) error {
+	if _, err := c.client.Do(ctx, "EVAL", releaseScript, 1, c.prefix+key, c.token); err != nil {
+		return fmt.Errorf("redisbackend: release %q: %w", key, err)
+	}
+	return nil
+}
+
+// newToken returns a random identifier for lease ownership.
+func newToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
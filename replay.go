@@ -0,0 +1,312 @@
+package retrier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Recording is the timeline of one retry loop: when each attempt ran, how
+// it ended, and how long the loop waited after it (see WithRecording). It
+// encodes to compact JSON with EncodeRecording, for incident reviews to
+// print it with Replay or ask with Simulate whether another policy would
+// have done better.
+type Recording struct {
+	// Start is when the loop started, according to the configured Clock.
+	Start time.Time `json:"start"`
+
+	// Attempts are the attempts of the loop, in order.
+	Attempts []RecordedAttempt `json:"attempts"`
+
+	// Err is the error the loop returned, redacted like logged errors, or ""
+	// if it succeeded.
+	Err string `json:"err,omitempty"`
+
+	// Elapsed is how long the loop ran.
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// RecordedAttempt is one attempt of a Recording.
+type RecordedAttempt struct {
+	// Offset is when the attempt started, after Recording.Start.
+	Offset time.Duration `json:"offset"`
+
+	// Duration is how long the attempt ran.
+	Duration time.Duration `json:"duration"`
+
+	// Err is the error of the attempt, redacted like logged errors, or "" if
+	// it succeeded.
+	Err string `json:"err,omitempty"`
+
+	// Permanent reports whether the error was classified as not retryable.
+	Permanent bool `json:"permanent,omitempty"`
+
+	// Delay is the backoff delay the loop chose after the attempt, or 0 if it
+	// did not retry.
+	Delay time.Duration `json:"delay,omitempty"`
+
+	// Decision is the rule and reason of the decision that followed the
+	// attempt, such as "max_attempts: attempt 3 of 3 failed", with
+	// WithExplain.
+	Decision string `json:"decision,omitempty"`
+}
+
+// Succeeded reports whether the recorded loop succeeded.
+func (r Recording) Succeeded() bool {
+	return len(r.Attempts) > 0 && r.Err == ""
+}
+
+// RecordingSink receives the Recording of each retry loop, synchronously,
+// before Retry returns. It must be safe for concurrent use when loops run
+// concurrently.
+type RecordingSink func(ctx context.Context, rec Recording)
+
+// WithRecording passes the Recording of the retry loop to sink when it
+// returns, whatever the outcome. Times are measured with the configured
+// Clock. Combine it with WithExplain to record the reason of each decision.
+// Default is none.
+//
+// Example:
+//
+//	retrier.WithRecording(func(ctx context.Context, rec retrier.Recording) {
+//	    if !rec.Succeeded() {
+//	        _ = retrier.EncodeRecording(incidentLog, rec)
+//	    }
+//	})
+func WithRecording(sink RecordingSink) RetryOption {
+	return func(c *retryConfig) {
+		c.recordingSink = sink
+	}
+}
+
+// EncodeRecording writes rec to w as one line of JSON.
+func EncodeRecording(w io.Writer, rec Recording) error {
+	return json.NewEncoder(w).Encode(rec)
+}
+
+// DecodeRecording reads a Recording written by EncodeRecording from r.
+func DecodeRecording(r io.Reader) (Recording, error) {
+	var rec Recording
+	if err := json.NewDecoder(r).Decode(&rec); err != nil {
+		return Recording{}, fmt.Errorf("retrier: decode recording: %w", err)
+	}
+	return rec, nil
+}
+
+// Replay writes the timeline of rec to w, one line per attempt and backoff
+// delay, followed by the outcome.
+//
+// Example output:
+//
+//	call started 2026-03-01T14:02:11Z
+//	  +0s    attempt 1  1.2s  failed: 503 Service Unavailable
+//	  +1.2s  wait       1s    default_policy: standard error with default policy auto
+//	  +2.2s  attempt 2  40ms  succeeded
+//	succeeded after 2 attempts in 2.24s
+func Replay(w io.Writer, rec Recording) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "call started %s\n", rec.Start.Format(time.RFC3339Nano))
+	for i, a := range rec.Attempts {
+		outcome := "succeeded"
+		switch {
+		case a.Err != "" && a.Permanent:
+			outcome = "failed permanently: " + a.Err
+		case a.Err != "":
+			outcome = "failed: " + a.Err
+		}
+		fmt.Fprintf(tw, "  +%v\tattempt %d\t%v\t%s\n", a.Offset, i+1, a.Duration, outcome)
+		ended := addDurations(a.Offset, a.Duration)
+		switch {
+		case i < len(rec.Attempts)-1:
+			fmt.Fprintf(tw, "  +%v\twait\t%v\t%s\n", ended, a.Delay, a.Decision)
+		case a.Decision != "":
+			fmt.Fprintf(tw, "  +%v\tstop\t\t%s\n", ended, a.Decision)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	var err error
+	if rec.Succeeded() {
+		_, err = fmt.Fprintf(w, "succeeded after %d attempts in %v\n", len(rec.Attempts), rec.Elapsed)
+	} else {
+		_, err = fmt.Fprintf(w, "failed after %d attempts in %v: %s\n", len(rec.Attempts), rec.Elapsed, rec.Err)
+	}
+	return err
+}
+
+// Simulation is the outcome Simulate predicts for a recorded loop under
+// another policy.
+type Simulation struct {
+	// Known is false when the policy would still have been retrying after
+	// the recording ends, so its outcome cannot be told.
+	Known bool
+
+	// Succeeded reports whether the policy would have succeeded; it is
+	// meaningful only if Known.
+	Succeeded bool
+
+	// Attempts is the number of attempts the policy would have made.
+	Attempts int
+
+	// Elapsed is how long the loop would have run.
+	Elapsed time.Duration
+
+	// Delays are the backoff delays the policy would have waited, with the
+	// mean jitter.
+	Delays []time.Duration
+}
+
+// Simulate replays rec under the policy opts configure, to tell whether it
+// would have succeeded. It assumes the outcome of a call depends on when it
+// is made, as during an outage: a simulated attempt starting at some offset
+// ends like the recorded attempt running at that offset, or the last one
+// started before it. After a recorded success, every later attempt
+// succeeds; after the last recorded failure, the outcome is unknown.
+//
+// Delays follow the backoff of opts with the mean jitter, and the attempt
+// limit, WithMaxTotalDuration, and WithMaxElapsedTime stop the loop as they
+// would. Errors keep the classification they were recorded with, and
+// server-suggested delays are not known.
+//
+// Example:
+//
+//	sim := rec.Simulate(retrier.WithMaxAttempts(6), retrier.WithMaxDuration(10*time.Second))
+//	if sim.Known && sim.Succeeded {
+//	    fmt.Printf("would have succeeded after %d attempts in %v\n", sim.Attempts, sim.Elapsed)
+//	}
+func (r Recording) Simulate(opts ...RetryOption) Simulation {
+	config := newConfig(opts)
+	var sim Simulation
+	if len(r.Attempts) == 0 || (config.maxAttempts < 1 && !config.unlimited()) {
+		sim.Known = true
+		return sim
+	}
+
+	var now time.Duration
+	for attempt := 1; ; attempt++ {
+		recorded, ok := r.attemptAt(now)
+		if !ok {
+			return sim
+		}
+		sim.Attempts = attempt
+		now = addDurations(now, recorded.Duration)
+		sim.Elapsed = now
+		if recorded.Err == "" {
+			sim.Known, sim.Succeeded = true, true
+			return sim
+		}
+		if recorded.Permanent || config.lastAttempt(attempt) {
+			sim.Known = true
+			return sim
+		}
+		delay, _, ok := config.delayWith(attempt, nil, func(max time.Duration) time.Duration {
+			return max / 2
+		})
+		if !ok ||
+			config.maxTotalDuration > 0 && now+delay >= config.maxTotalDuration ||
+			config.maxElapsedTime > 0 && now+delay > config.maxElapsedTime {
+			sim.Known = true
+			return sim
+		}
+		sim.Delays = append(sim.Delays, delay)
+		now = addDurations(now, delay)
+	}
+}
+
+// attemptAt returns the recorded attempt whose outcome an attempt starting
+// at offset gets, and false if the recording does not tell.
+func (r Recording) attemptAt(offset time.Duration) (RecordedAttempt, bool) {
+	i := 0
+	for i+1 < len(r.Attempts) && r.Attempts[i+1].Offset <= offset {
+		i++
+	}
+	a := r.Attempts[i]
+	if i == len(r.Attempts)-1 && a.Err != "" && !a.Permanent && offset > addDurations(a.Offset, a.Duration) {
+		return RecordedAttempt{}, false
+	}
+	return a, true
+}
+
+// recorder builds the Recording of a loop. Its methods do nothing on a nil
+// recorder, used without WithRecording.
+type recorder struct {
+	config *retryConfig
+	rec    Recording
+	start  time.Time
+}
+
+// newRecorder returns the recorder of a loop, or nil without WithRecording.
+func (c *retryConfig) newRecorder() *recorder {
+	if c.recordingSink == nil {
+		return nil
+	}
+	now := c.clock.Now()
+	return &recorder{config: c, rec: Recording{Start: now}, start: now}
+}
+
+// begin records the start of an attempt.
+func (r *recorder) begin() {
+	if r == nil {
+		return
+	}
+	r.start = r.config.clock.Now()
+	r.rec.Attempts = append(r.rec.Attempts, RecordedAttempt{Offset: r.start.Sub(r.rec.Start)})
+}
+
+// end records the end of the current attempt, which returned err.
+func (r *recorder) end(err error) {
+	if r == nil {
+		return
+	}
+	a := &r.rec.Attempts[len(r.rec.Attempts)-1]
+	a.Duration = r.config.clock.Now().Sub(r.start)
+	if err != nil {
+		a.Err = r.config.redactError(err).Error()
+	}
+}
+
+// notRetried records that the error of the current attempt is not retryable.
+func (r *recorder) notRetried() {
+	if r == nil {
+		return
+	}
+	r.rec.Attempts[len(r.rec.Attempts)-1].Permanent = true
+}
+
+// retry records the delay before the next attempt and the decision behind it.
+func (r *recorder) retry(delay time.Duration, decision *Decision) {
+	if r == nil {
+		return
+	}
+	a := &r.rec.Attempts[len(r.rec.Attempts)-1]
+	a.Delay = delay
+	a.Decision = decisionText(decision)
+}
+
+// finish passes the Recording of the loop, which returned err after the
+// final decision, to the sink.
+func (r *recorder) finish(ctx context.Context, err error, decision *Decision) {
+	if r == nil {
+		return
+	}
+	if err != nil {
+		r.rec.Err = r.config.redactError(err).Error()
+		if n := len(r.rec.Attempts); n > 0 && decision != nil && !decision.Retry {
+			r.rec.Attempts[n-1].Decision = decisionText(decision)
+		}
+	}
+	r.rec.Elapsed = r.config.clock.Now().Sub(r.rec.Start)
+	r.config.recordingSink(ctx, r.rec)
+}
+
+// decisionText returns the rule and reason of d, or "" if d is nil.
+func decisionText(d *Decision) string {
+	if d == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s: %s", d.Rule, d.Reason)
+}
@@ -0,0 +1,63 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrAttemptAborted is passed to the release function of RetryWithResource
+// when an attempt ended without returning, because it panicked or called
+// runtime.Goexit.
+var ErrAttemptAborted = errors.New("attempt aborted")
+
+// RetryWithResource runs fn with a resource scoped to each attempt, such as a
+// connection, a lease, or a temporary file. Each attempt acquires a fresh
+// resource with acquire, runs fn with it, and releases it with release, which
+// also runs if fn panics. release receives the outcome of the attempt: nil on
+// success, the error of fn on failure, or ErrAttemptAborted if fn did not
+// return. Failures of acquire are retried like failures of fn. A release
+// error fails an otherwise successful attempt; after a failed attempt, the
+// error of fn takes precedence and the release error is discarded.
+//
+// acquire and fn receive the context of the attempt (see RetryCtx).
+//
+// Example:
+//
+//	result := retrier.RetryWithResource(ctx, logger,
+//	    func(ctx context.Context) (*sql.Conn, error) { return db.Conn(ctx) },
+//	    func(conn *sql.Conn, err error) error { return conn.Close() },
+//	    func(ctx context.Context, conn *sql.Conn) (int, error) {
+//	        return migrate(ctx, conn)
+//	    },
+//	)
+func RetryWithResource[R, T any](ctx context.Context, logger DebugLogger, acquire func(ctx context.Context) (R, error), release func(resource R, err error) error, fn func(ctx context.Context, resource R) (T, error), opts ...RetryOption) Result[T] {
+	return RetryCtx(ctx, logger, func(ctx context.Context) (T, error) {
+		return withResource(ctx, acquire, release, fn)
+	}, opts...)
+}
+
+// withResource runs one attempt of RetryWithResource.
+func withResource[R, T any](ctx context.Context, acquire func(ctx context.Context) (R, error), release func(resource R, err error) error, fn func(ctx context.Context, resource R) (T, error)) (T, error) {
+	var zero T
+	resource, err := acquire(ctx)
+	if err != nil {
+		return zero, err
+	}
+	returned := false
+	defer func() {
+		// fn panicked or exited the goroutine; the panic keeps unwinding
+		if !returned {
+			_ = release(resource, ErrAttemptAborted)
+		}
+	}()
+
+	value, err := fn(ctx, resource)
+	returned = true
+	if releaseErr := release(resource, err); releaseErr != nil && err == nil {
+		return zero, releaseErr
+	}
+	if err != nil {
+		return zero, err
+	}
+	return value, nil
+}
@@ -0,0 +1,176 @@
+package retrier
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Retrier is a reusable retry configuration shared by many calls.
+// Its options can be replaced at runtime with UpdateOptions; calls in progress
+// keep the options they started with. A Retrier is safe for concurrent use.
+type Retrier struct {
+	logger DebugLogger
+	opts   atomic.Pointer[[]RetryOption]
+
+	mu          sync.Mutex
+	listeners   []func(previous, current Options)
+	errorCounts map[string]int64
+	exemplars   RetrierExemplars
+
+	calls     atomic.Int64
+	successes atomic.Int64
+	failures  atomic.Int64
+	retries   atomic.Int64
+
+	// attempts is the number of attempts made by all calls, and
+	// attemptCounts counts calls by attempts made, in the buckets of
+	// attemptBuckets followed by one for larger counts
+	attempts      atomic.Int64
+	attemptCounts [len(attemptBuckets) + 1]atomic.Int64
+}
+
+// attemptBuckets are the upper bounds of the buckets of the attempts
+// histogram of a Retrier.
+var attemptBuckets = [...]int{1, 2, 3, 5, 10}
+
+// ErrorCounts returns the number of failed calls by error fingerprint (see
+// WithFingerprinter). The fingerprint is taken from the error of the last attempt.
+func (r *Retrier) ErrorCounts() map[string]int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(map[string]int64, len(r.errorCounts))
+	for fingerprint, n := range r.errorCounts {
+		counts[fingerprint] = n
+	}
+	return counts
+}
+
+// RetrierStats counts the calls made through a Retrier since it was created.
+type RetrierStats struct {
+	// Calls is the number of Do calls that returned.
+	Calls int64 `json:"calls"`
+
+	// Successes is the number of calls that returned a value.
+	Successes int64 `json:"successes"`
+
+	// Failures is the number of calls that returned an error.
+	Failures int64 `json:"failures"`
+
+	// Retries is the number of attempts made beyond the first of each call.
+	Retries int64 `json:"retries"`
+}
+
+// NewRetrier creates a Retrier logging to logger and retrying with opts.
+func NewRetrier(logger DebugLogger, opts ...RetryOption) *Retrier {
+	r := &Retrier{logger: logger}
+	r.opts.Store(&opts)
+	return r
+}
+
+// Do runs fn with the current options of r, followed by opts for this call.
+//
+// Example:
+//
+//	r := retrier.NewRetrier(logger, retrier.WithMaxAttempts(5))
+//	result := retrier.Do(ctx, r, fetchUser)
+func Do[T any](ctx context.Context, r *Retrier, fn func() (T, error), opts ...RetryOption) Result[T] {
+	config := newConfig(r.callOptions(opts))
+	return doConfig(ctx, r, fn, &config)
+}
+
+// doConfig runs fn through r with config, resolved from the options of r and
+// of the call.
+func doConfig[T any](ctx context.Context, r *Retrier, fn func() (T, error), config *retryConfig) Result[T] {
+	result := retry(ctx, r.logger, ignoreContext(fn), config)
+	r.record(ctx, config, result.attempts, result.err)
+	return result
+}
+
+// callOptions returns the current options of r followed by opts, in a new
+// slice, so that concurrent calls never append to the shared options.
+func (r *Retrier) callOptions(opts []RetryOption) []RetryOption {
+	current := r.RetryOptions()
+	callOpts := make([]RetryOption, 0, len(current)+len(opts))
+	callOpts = append(callOpts, current...)
+	return append(callOpts, opts...)
+}
+
+// record counts a returned call, run with ctx, in the stats of r.
+func (r *Retrier) record(ctx context.Context, config *retryConfig, attempts int, err error) {
+	r.calls.Add(1)
+	var fingerprint string
+	if err == nil {
+		r.successes.Add(1)
+	} else {
+		r.failures.Add(1)
+		fingerprint = config.fingerprint(attemptError(err))
+		r.mu.Lock()
+		if r.errorCounts == nil {
+			r.errorCounts = make(map[string]int64)
+		}
+		r.errorCounts[fingerprint]++
+		r.mu.Unlock()
+	}
+	if config.traceID != nil && (err != nil || attempts > 1) {
+		r.mu.Lock()
+		r.recordExemplars(ctx, config, attempts, err, fingerprint)
+		r.mu.Unlock()
+	}
+	if attempts > 1 {
+		r.retries.Add(int64(attempts - 1))
+	}
+	r.attempts.Add(int64(attempts))
+	bucket := len(attemptBuckets)
+	for i, bound := range attemptBuckets {
+		if attempts <= bound {
+			bucket = i
+			break
+		}
+	}
+	r.attemptCounts[bucket].Add(1)
+}
+
+// Stats returns the call counters of r.
+func (r *Retrier) Stats() RetrierStats {
+	return RetrierStats{
+		Calls:     r.calls.Load(),
+		Successes: r.successes.Load(),
+		Failures:  r.failures.Load(),
+		Retries:   r.retries.Load(),
+	}
+}
+
+// RetryOptions returns the current options of r.
+// It makes a Retrier usable as a PolicyProvider.
+func (r *Retrier) RetryOptions() []RetryOption {
+	return *r.opts.Load()
+}
+
+// Options returns the resolved settings of the current options.
+func (r *Retrier) Options() Options {
+	return ResolveOptions(r.RetryOptions()...)
+}
+
+// UpdateOptions atomically replaces the options of r for subsequent calls
+// and notifies the OnUpdate listeners.
+func (r *Retrier) UpdateOptions(opts ...RetryOption) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous := r.Options()
+	r.opts.Store(&opts)
+	current := r.Options()
+	for _, listener := range r.listeners {
+		listener(previous, current)
+	}
+}
+
+// OnUpdate registers fn to be called after every UpdateOptions with the
+// settings before and after the update. Listeners run synchronously, in
+// registration order, and must not call UpdateOptions.
+func (r *Retrier) OnUpdate(fn func(previous, current Options)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
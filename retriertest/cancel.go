@@ -0,0 +1,218 @@
+package retriertest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// RunFunc runs the retrying code under test with ctx, passing opts on to its
+// retrier.Retry, RetryCtx, or Do call. The cancellation scenarios use opts to
+// install a FakeClock and attempt hooks, so the code must not override them.
+// Its first attempt must fail with a retryable error.
+type RunFunc[T any] func(ctx context.Context, opts ...retrier.RetryOption) retrier.Result[T]
+
+// scenarioTimeout bounds how long a scenario waits for the code under test.
+const scenarioTimeout = 10 * time.Second
+
+// CancelDuringBackoff cancels ctx while run sleeps in its first backoff delay
+// and checks that it returns ErrContextCancelled after one attempt, wrapping
+// context.Canceled. It returns the Result for further checks.
+func CancelDuringBackoff[T any](t testing.TB, run RunFunc[T]) retrier.Result[T] {
+	t.Helper()
+	clock := NewFakeClock(time.Unix(0, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := start(ctx, run, retrier.WithClock(clock))
+	if _, ok := waitForTimer(t, clock, done); !ok {
+		return retrier.Result[T]{}
+	}
+	cancel()
+
+	result := await(t, done)
+	checkCancelled(t, result, 1, context.Canceled)
+	return result
+}
+
+// CancelMidAttempt cancels ctx as attempt (1-based) starts, so the attempt
+// runs with a cancelled context, and checks that run makes no further
+// attempts and, if it fails with a RetryError, that its cause is
+// ErrContextCancelled. Backoff delays before attempt pass instantly. It
+// returns the Result for further checks.
+func CancelMidAttempt[T any](t testing.TB, attempt int, run RunFunc[T]) retrier.Result[T] {
+	t.Helper()
+	clock := NewFakeClock(time.Unix(0, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := start(ctx, run,
+		retrier.WithClock(clock),
+		retrier.WithAttemptContext(func(attemptCtx context.Context, n int) (context.Context, func()) {
+			if n == attempt {
+				cancel()
+			}
+			return attemptCtx, nil
+		}),
+	)
+
+	result := drive(t, clock, done)
+	if result.Attempts() != attempt {
+		t.Errorf("cancelled during attempt %d, but run made %d attempts", attempt, result.Attempts())
+	}
+	var retryErr *retrier.RetryError
+	if errors.As(result.Err(), &retryErr) && retryErr.Cause != retrier.ErrContextCancelled {
+		t.Errorf("cancelled during attempt %d, got cause %q: %v", attempt, retryErr.Cause, result.Err())
+	}
+	return result
+}
+
+// DeadlineAtBackoffBoundary gives run a context whose deadline falls exactly
+// when its first backoff delay ends, and exercises both outcomes of that race
+// in turn:
+//
+//   - deadlineFirst: the deadline is observed before the delay ends; run must
+//     return ErrContextCancelled after one attempt, wrapping
+//     context.DeadlineExceeded.
+//   - wakeFirst: the delay ends first and the second attempt starts with an
+//     expired context; run must make no third attempt.
+//
+// The deadline of the context is only known once run starts its first
+// backoff delay.
+func DeadlineAtBackoffBoundary[T any](t testing.TB, run RunFunc[T]) (deadlineFirst, wakeFirst retrier.Result[T]) {
+	t.Helper()
+
+	// The deadline fires alone, the backoff timer stays pending
+	clock := NewFakeClock(time.Unix(0, 0))
+	ctx := clock.pendingDeadline(context.Background())
+	done := start[T](ctx, run, retrier.WithClock(clock))
+	timer, ok := waitForTimer(t, clock, done)
+	if !ok {
+		return deadlineFirst, wakeFirst
+	}
+	clock.fire(clock.setDeadline(ctx, timer.at))
+	deadlineFirst = await(t, done)
+	checkCancelled(t, deadlineFirst, 1, context.DeadlineExceeded)
+
+	// The backoff timer fires alone, the deadline fires as attempt 2 starts
+	clock = NewFakeClock(time.Unix(0, 0))
+	ctx = clock.pendingDeadline(context.Background())
+	var mu sync.Mutex
+	var deadline *fakeWaiter
+	done = start[T](ctx, run,
+		retrier.WithClock(clock),
+		retrier.WithAttemptContext(func(attemptCtx context.Context, n int) (context.Context, func()) {
+			if n == 2 {
+				mu.Lock()
+				clock.fire(deadline)
+				mu.Unlock()
+			}
+			return attemptCtx, nil
+		}),
+	)
+	timer, ok = waitForTimer(t, clock, done)
+	if !ok {
+		return deadlineFirst, wakeFirst
+	}
+	mu.Lock()
+	deadline = clock.setDeadline(ctx, timer.at)
+	mu.Unlock()
+	clock.fire(timer)
+	wakeFirst = drive(t, clock, done)
+	if wakeFirst.Attempts() != 2 {
+		t.Errorf("deadline expired as attempt 2 started, but run made %d attempts", wakeFirst.Attempts())
+	}
+	var retryErr *retrier.RetryError
+	if errors.As(wakeFirst.Err(), &retryErr) {
+		switch retryErr.Cause {
+		case retrier.ErrContextCancelled:
+			if !errors.Is(wakeFirst.Err(), context.DeadlineExceeded) {
+				t.Errorf("expected context.DeadlineExceeded in the error chain, got %v", wakeFirst.Err())
+			}
+		case retrier.ErrExhaustedAttempts:
+		default:
+			t.Errorf("deadline expired as attempt 2 started, got cause %q: %v", retryErr.Cause, wakeFirst.Err())
+		}
+	}
+	return deadlineFirst, wakeFirst
+}
+
+// start runs run in a new goroutine and returns a channel receiving its Result.
+func start[T any](ctx context.Context, run RunFunc[T], opts ...retrier.RetryOption) <-chan retrier.Result[T] {
+	done := make(chan retrier.Result[T], 1)
+	go func() {
+		done <- run(ctx, opts...)
+	}()
+	return done
+}
+
+// await waits for the Result of run.
+func await[T any](t testing.TB, done <-chan retrier.Result[T]) retrier.Result[T] {
+	t.Helper()
+	select {
+	case result := <-done:
+		return result
+	case <-time.After(scenarioTimeout):
+		t.Fatalf("run did not return within %v", scenarioTimeout)
+		return retrier.Result[T]{}
+	}
+}
+
+// waitForTimer waits until run sleeps in a backoff delay and returns the
+// timer of the delay. It reports a test error and returns false if run
+// returns first.
+func waitForTimer[T any](t testing.TB, clock *FakeClock, done <-chan retrier.Result[T]) (*fakeWaiter, bool) {
+	t.Helper()
+	deadline := time.Now().Add(scenarioTimeout)
+	for time.Now().Before(deadline) {
+		if timer := clock.nextTimer(); timer != nil {
+			return timer, true
+		}
+		select {
+		case result := <-done:
+			t.Errorf("run returned after %d attempts without backing off (err: %v); its first attempt must fail with a retryable error",
+				result.Attempts(), result.Err())
+			return nil, false
+		case <-time.After(time.Millisecond):
+		}
+	}
+	t.Fatalf("run did not back off within %v", scenarioTimeout)
+	return nil, false
+}
+
+// drive fires backoff timers as run sleeps until it returns its Result.
+func drive[T any](t testing.TB, clock *FakeClock, done <-chan retrier.Result[T]) retrier.Result[T] {
+	t.Helper()
+	deadline := time.Now().Add(scenarioTimeout)
+	for time.Now().Before(deadline) {
+		if timer := clock.nextTimer(); timer != nil {
+			clock.fire(timer)
+		}
+		select {
+		case result := <-done:
+			return result
+		case <-time.After(time.Millisecond):
+		}
+	}
+	t.Fatalf("run did not return within %v", scenarioTimeout)
+	return retrier.Result[T]{}
+}
+
+// checkCancelled reports a test error unless result is an ErrContextCancelled
+// failure after attempts, wrapping ctxErr.
+func checkCancelled[T any](t testing.TB, result retrier.Result[T], attempts int, ctxErr error) {
+	t.Helper()
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrContextCancelled {
+		t.Errorf("expected ErrContextCancelled, got %v", result.Err())
+	} else if !errors.Is(result.Err(), ctxErr) {
+		t.Errorf("expected %v in the error chain, got %v", ctxErr, result.Err())
+	}
+	if result.Attempts() != attempts {
+		t.Errorf("expected %d attempts, got %d", attempts, result.Attempts())
+	}
+}
@@ -0,0 +1,232 @@
+package retriertest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// FakeClock is a retrier.Clock whose time only moves when Advance is called.
+// Pass it with retrier.WithClock to run backoff delays without sleeping. It
+// also creates contexts whose deadlines follow its time (see WithDeadline).
+// A FakeClock is safe for concurrent use.
+type FakeClock struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter is a pending timer or context deadline.
+type fakeWaiter struct {
+	at    time.Time
+	timer bool
+	fire  func(now time.Time)
+}
+
+// NewFakeClock creates a FakeClock set to start.
+func NewFakeClock(start time.Time) *FakeClock {
+	c := &FakeClock{now: start}
+	c.changed = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer creates a timer firing once the fake time reaches Now()+d.
+func (c *FakeClock) NewTimer(d time.Duration) retrier.Timer {
+	t := &fakeTimer{clock: c, ch: make(chan time.Time, 1)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t.waiter = &fakeWaiter{at: c.now.Add(d), timer: true, fire: func(now time.Time) { t.ch <- now }}
+	c.add(t.waiter)
+	return t
+}
+
+// Advance moves the fake time forward by d, firing timers and expiring
+// deadlines that fall due, in order of their time.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for len(c.waiters) > 0 && !c.waiters[0].at.After(c.now) {
+		w := c.waiters[0]
+		c.waiters = c.waiters[1:]
+		w.fire(c.now)
+	}
+	c.changed.Broadcast()
+}
+
+// Pending returns the number of timers and deadlines that have not fired.
+func (c *FakeClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil waits until at least n timers and deadlines are pending. Use it
+// to wait for a retry loop to start sleeping before calling Advance.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.changed.Wait()
+	}
+}
+
+// NextDeadline returns the time of the earliest pending timer or deadline,
+// and false if none is pending.
+func (c *FakeClock) NextDeadline() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.waiters) == 0 {
+		return time.Time{}, false
+	}
+	return c.waiters[0].at, true
+}
+
+// WithDeadline returns a copy of parent that is done once the fake time
+// reaches deadline, with context.DeadlineExceeded, or when cancel is called.
+func (c *FakeClock) WithDeadline(parent context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	ctx := &fakeDeadlineContext{Context: parent, deadline: deadline, done: make(chan struct{})}
+	c.mu.Lock()
+	w := &fakeWaiter{at: deadline, fire: func(time.Time) { ctx.finish(context.DeadlineExceeded) }}
+	if deadline.After(c.now) {
+		c.add(w)
+	} else {
+		ctx.finish(context.DeadlineExceeded)
+	}
+	c.mu.Unlock()
+
+	stop := context.AfterFunc(parent, func() {
+		c.remove(w)
+		ctx.finish(parent.Err())
+	})
+	return ctx, func() {
+		stop()
+		c.remove(w)
+		ctx.finish(context.Canceled)
+	}
+}
+
+// add inserts w in time order, after waiters due at the same time.
+// c.mu must be held.
+func (c *FakeClock) add(w *fakeWaiter) {
+	i := sort.Search(len(c.waiters), func(i int) bool { return c.waiters[i].at.After(w.at) })
+	c.waiters = append(c.waiters, nil)
+	copy(c.waiters[i+1:], c.waiters[i:])
+	c.waiters[i] = w
+	c.changed.Broadcast()
+}
+
+// nextTimer returns the earliest pending timer, or nil.
+func (c *FakeClock) nextTimer() *fakeWaiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, w := range c.waiters {
+		if w.timer {
+			return w
+		}
+	}
+	return nil
+}
+
+// fire fires w alone, moving the time forward to w.at if it is later, and
+// reports whether w was pending.
+func (c *FakeClock) fire(w *fakeWaiter) bool {
+	if !c.remove(w) {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if w.at.After(c.now) {
+		c.now = w.at
+	}
+	w.fire(c.now)
+	return true
+}
+
+// pendingDeadline returns a copy of parent whose deadline is set later with
+// setDeadline.
+func (c *FakeClock) pendingDeadline(parent context.Context) *fakeDeadlineContext {
+	return &fakeDeadlineContext{Context: parent, done: make(chan struct{})}
+}
+
+// setDeadline registers at as the deadline of ctx and returns its waiter.
+func (c *FakeClock) setDeadline(ctx *fakeDeadlineContext, at time.Time) *fakeWaiter {
+	ctx.mu.Lock()
+	ctx.deadline = at
+	ctx.mu.Unlock()
+	w := &fakeWaiter{at: at, fire: func(time.Time) { ctx.finish(context.DeadlineExceeded) }}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.add(w)
+	return w
+}
+
+// remove drops w if it is pending, reporting whether it was.
+func (c *FakeClock) remove(w *fakeWaiter) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, pending := range c.waiters {
+		if pending == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.changed.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// fakeTimer is a timer of a FakeClock.
+type fakeTimer struct {
+	clock  *FakeClock
+	waiter *fakeWaiter
+	ch     chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTimer) Stop() bool { return t.clock.remove(t.waiter) }
+
+// fakeDeadlineContext is a context whose deadline follows a FakeClock.
+type fakeDeadlineContext struct {
+	context.Context
+	deadline time.Time
+
+	mu   sync.Mutex
+	done chan struct{}
+	err  error
+}
+
+func (c *fakeDeadlineContext) Deadline() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.deadline, !c.deadline.IsZero()
+}
+
+func (c *fakeDeadlineContext) Done() <-chan struct{} { return c.done }
+
+func (c *fakeDeadlineContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// finish ends the context with err, unless it already ended.
+func (c *fakeDeadlineContext) finish(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+		close(c.done)
+	}
+}
@@ -0,0 +1,120 @@
+package retriertest
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// Recorder keeps the retrier.Recording of every retry loop run with its
+// Option, for assertions on attempts and backoff delays. Use it with a
+// FakeClock to record exact delays. It is safe for concurrent use.
+//
+// Example:
+//
+//	rec := retriertest.NewRecorder()
+//	retrier.Retry(ctx, logger, script.Call, rec.Option(), retrier.WithJitter(0))
+//	retriertest.AssertAttempts(t, rec.Last(), 3)
+//	retriertest.AssertDelays(t, rec.Last(), 100*time.Millisecond, 200*time.Millisecond)
+type Recorder struct {
+	mu         sync.Mutex
+	recordings []retrier.Recording
+}
+
+// NewRecorder returns a Recorder without recordings.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Option returns the option recording a loop into r.
+func (r *Recorder) Option() retrier.RetryOption {
+	return retrier.WithRecording(func(_ context.Context, rec retrier.Recording) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.recordings = append(r.recordings, rec)
+	})
+}
+
+// Recordings returns the recordings of the loops run so far, in the order
+// they returned.
+func (r *Recorder) Recordings() []retrier.Recording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.recordings)
+}
+
+// Last returns the recording of the loop that returned last, or the zero
+// Recording if none did.
+func (r *Recorder) Last() retrier.Recording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.recordings) == 0 {
+		return retrier.Recording{}
+	}
+	return r.recordings[len(r.recordings)-1]
+}
+
+// Delays returns the backoff delays rec waited, one per retry.
+func Delays(rec retrier.Recording) []time.Duration {
+	var delays []time.Duration
+	for i, a := range rec.Attempts {
+		if i < len(rec.Attempts)-1 {
+			delays = append(delays, a.Delay)
+		}
+	}
+	return delays
+}
+
+// AssertAttempts reports a test error unless rec made n attempts.
+func AssertAttempts(t testing.TB, rec retrier.Recording, n int) {
+	t.Helper()
+	if len(rec.Attempts) != n {
+		t.Errorf("expected %d attempts, got %d", n, len(rec.Attempts))
+	}
+}
+
+// AssertDelays reports a test error unless rec waited exactly the backoff
+// delays want, in order.
+func AssertDelays(t testing.TB, rec retrier.Recording, want ...time.Duration) {
+	t.Helper()
+	if got := Delays(rec); !slices.Equal(got, want) {
+		t.Errorf("expected delays %v, got %v", want, got)
+	}
+}
+
+// LogCall is a call of Logger.LogRetry.
+type LogCall struct {
+	Attempt     int
+	MaxAttempts int
+	Backoff     time.Duration
+	Err         error
+	Attrs       []any
+}
+
+// Logger is an enabled retrier.DebugLogger recording its calls, for
+// assertions on what a retry loop logs. It is safe for concurrent use.
+type Logger struct {
+	mu    sync.Mutex
+	calls []LogCall
+}
+
+// Enabled returns true.
+func (l *Logger) Enabled() bool { return true }
+
+// LogRetry records the call.
+func (l *Logger) LogRetry(_ context.Context, attempt, maxAttempts int, backoff time.Duration, err error, attrs ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, LogCall{Attempt: attempt, MaxAttempts: maxAttempts, Backoff: backoff, Err: err, Attrs: attrs})
+}
+
+// Calls returns the calls of LogRetry so far, in order.
+func (l *Logger) Calls() []LogCall {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.calls)
+}
@@ -0,0 +1,74 @@
+// Package retriertest provides helpers for testing code that uses retrier.
+package retriertest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// AssertResultEqual reports a test error describing every difference between
+// got and want: value, attempts, and the error chain, compared link by link by
+// type and message.
+func AssertResultEqual[T any](t testing.TB, got, want retrier.Result[T]) {
+	t.Helper()
+	if diff := ResultDiff(got, want); diff != "" {
+		t.Errorf("Result mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// ResultEqual reports whether x and y hold equal values, attempts, and error
+// chains. It has the shape go-cmp expects of a comparer:
+//
+//	cmp.Diff(want, got, cmp.Comparer(retriertest.ResultEqual[User]))
+func ResultEqual[T any](x, y retrier.Result[T]) bool {
+	return ResultDiff(x, y) == ""
+}
+
+// ResultDiff returns a human-readable description of the differences between
+// got and want, or "" if they are equal.
+func ResultDiff[T any](got, want retrier.Result[T]) string {
+	var b strings.Builder
+	if !reflect.DeepEqual(got.Value(), want.Value()) {
+		fmt.Fprintf(&b, "  value:\n  -  %#v\n  +  %#v\n", want.Value(), got.Value())
+	}
+	if got.Attempts() != want.Attempts() {
+		fmt.Fprintf(&b, "  attempts:\n  -  %d\n  +  %d\n", want.Attempts(), got.Attempts())
+	}
+	gotChain, wantChain := errorChain(got.Err()), errorChain(want.Err())
+	if !slices.Equal(gotChain, wantChain) {
+		b.WriteString("  error chain:\n")
+		for _, link := range wantChain {
+			fmt.Fprintf(&b, "  -  %s\n", link)
+		}
+		for _, link := range gotChain {
+			fmt.Fprintf(&b, "  +  %s\n", link)
+		}
+	}
+	return b.String()
+}
+
+// errorChain describes each error in the tree of err, depth first, as its type and message.
+func errorChain(err error) []string {
+	if err == nil {
+		return nil
+	}
+	chain := []string{fmt.Sprintf("%T: %v", err, err)}
+	if re, ok := err.(*retrier.RetryError); ok {
+		chain[0] = fmt.Sprintf("%T(%s): %v", err, re.Cause, err)
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, inner := range u.Unwrap() {
+			chain = append(chain, errorChain(inner)...)
+		}
+	default:
+		chain = append(chain, errorChain(errors.Unwrap(err))...)
+	}
+	return chain
+}
@@ -0,0 +1,92 @@
+package retriertest
+
+import (
+	"context"
+	"sync"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// Step is one scripted outcome of a Script.
+type Step[T any] struct {
+	Value T
+	Err   error
+}
+
+// Script is a retried function whose calls return scripted outcomes in
+// order, repeating the last one once the script is over, and which counts
+// its calls. Pass its Call or CallCtx method to retrier.Retry or
+// retrier.RetryCtx. It is safe for concurrent use.
+//
+// Example:
+//
+//	script := retriertest.FailThenSucceed(2, retriertest.Transient("unavailable"), "ok")
+//	result := retrier.Retry(ctx, logger, script.Call)
+//	// result.Value() == "ok", script.Calls() == 3
+type Script[T any] struct {
+	mu    sync.Mutex
+	steps []Step[T]
+	calls int
+}
+
+// NewScript returns a Script returning steps in order. A Script without
+// steps returns the zero value and no error.
+func NewScript[T any](steps ...Step[T]) *Script[T] {
+	return &Script[T]{steps: steps}
+}
+
+// FailThenSucceed returns a Script failing n times with err, then returning
+// value.
+func FailThenSucceed[T any](n int, err error, value T) *Script[T] {
+	steps := make([]Step[T], n, n+1)
+	for i := range steps {
+		steps[i].Err = err
+	}
+	return NewScript(append(steps, Step[T]{Value: value})...)
+}
+
+// AlwaysFail returns a Script failing every call with err.
+func AlwaysFail[T any](err error) *Script[T] {
+	return NewScript(Step[T]{Err: err})
+}
+
+// Call returns the outcome of the next step.
+func (s *Script[T]) Call() (T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if len(s.steps) == 0 {
+		var zero T
+		return zero, nil
+	}
+	step := s.steps[min(s.calls, len(s.steps))-1]
+	return step.Value, step.Err
+}
+
+// CallCtx returns the outcome of the next step, ignoring ctx.
+func (s *Script[T]) CallCtx(context.Context) (T, error) {
+	return s.Call()
+}
+
+// Calls returns the number of calls made so far.
+func (s *Script[T]) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// Transient returns an error with message msg that retrier retries whatever
+// its default policy: it implements retrier.RetryableError with
+// retrier.RetryPolicyAuto. Wrap it with retrier.Permanent for the opposite.
+func Transient(msg string) error {
+	return &transientError{msg: msg}
+}
+
+// transientError is a retryable error (see Transient).
+type transientError struct {
+	msg string
+}
+
+func (e *transientError) Error() string { return e.msg }
+
+func (e *transientError) RetryPolicy() retrier.RetryPolicy { return retrier.RetryPolicyAuto }
@@ -0,0 +1,139 @@
+// Package retrygo mirrors the API of github.com/avast/retry-go on top of the
+// retrier engine, so existing call sites can migrate by changing their import.
+//
+// Only the commonly used options are provided. Delays follow retrier's
+// exponential backoff with the retry-go defaults: 10 attempts, a 100ms initial
+// delay, and up to 100ms of jitter.
+package retrygo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// Option configures Do, like retry.Option.
+type Option func(*config)
+
+// config holds the translated settings of a Do call.
+type config struct {
+	ctx           context.Context
+	lastErrorOnly bool
+	opts          []retrier.RetryOption
+}
+
+// Attempts sets the maximum number of attempts. As in retry-go, 0 retries
+// until the function succeeds or the context is done.
+func Attempts(n uint) Option {
+	return func(c *config) {
+		attempts := math.MaxInt
+		if n > 0 && uint64(n) < math.MaxInt {
+			attempts = int(n)
+		}
+		c.opts = append(c.opts, retrier.WithMaxAttempts(attempts))
+	}
+}
+
+// Delay sets the initial backoff delay.
+func Delay(d time.Duration) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, retrier.WithInitialDuration(d))
+	}
+}
+
+// MaxDelay sets the maximum backoff delay.
+func MaxDelay(d time.Duration) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, retrier.WithMaxDuration(d))
+	}
+}
+
+// MaxJitter sets the maximum random duration added to each delay.
+func MaxJitter(d time.Duration) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, retrier.WithJitter(d))
+	}
+}
+
+// OnRetry sets a callback invoked before each retry. As in retry-go, n is the
+// 0-based index of the failed attempt.
+func OnRetry(onRetry func(n uint, err error)) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, retrier.WithOnRetry(func(attempt int, err error) {
+			onRetry(uint(attempt-1), err)
+		}))
+	}
+}
+
+// RetryIf sets the predicate deciding which errors are retried.
+// Without it, errors are retried according to their retrier.RetryPolicy.
+func RetryIf(retryIf func(err error) bool) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, retrier.WithRetryIf(retryIf))
+	}
+}
+
+// LastErrorOnly makes Do return the error of the last attempt instead of the
+// *retrier.RetryError describing why retrying stopped.
+func LastErrorOnly(lastErrorOnly bool) Option {
+	return func(c *config) {
+		c.lastErrorOnly = lastErrorOnly
+	}
+}
+
+// Context sets the context stopping retries when done. Default is context.Background().
+func Context(ctx context.Context) Option {
+	return func(c *config) {
+		c.ctx = ctx
+	}
+}
+
+// RetryOptions translates opts into retrier options, for call sites moving
+// from Do to retrier.Retry. Context and LastErrorOnly have no retrier
+// equivalent and are ignored.
+func RetryOptions(opts ...Option) []retrier.RetryOption {
+	return resolve(opts).opts
+}
+
+// Do calls fn until it succeeds, like retry.Do.
+func Do(fn func() error, opts ...Option) error {
+	_, err := DoWithData(func() (struct{}, error) {
+		return struct{}{}, fn()
+	}, opts...)
+	return err
+}
+
+// DoWithData calls fn until it succeeds and returns its value, like retry.DoWithData.
+func DoWithData[T any](fn func() (T, error), opts ...Option) (T, error) {
+	c := resolve(opts)
+	result := retrier.Retry(c.ctx, retrier.NewNoOpLogger(), fn, c.opts...)
+	value, _, err := result.Decompose()
+	if err != nil && c.lastErrorOnly {
+		var retryErr *retrier.RetryError
+		if errors.As(err, &retryErr) {
+			if wrapped := retryErr.Unwrap(); wrapped != nil {
+				err = wrapped
+			}
+		}
+	}
+	return value, err
+}
+
+// resolve applies opts on top of the retry-go defaults.
+func resolve(opts []Option) config {
+	c := config{
+		ctx: context.Background(),
+		opts: []retrier.RetryOption{
+			retrier.WithMaxAttempts(10),
+			retrier.WithInitialDuration(100 * time.Millisecond),
+			retrier.WithJitter(100 * time.Millisecond),
+		},
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
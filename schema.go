@@ -0,0 +1,143 @@
+package retrier
+
+import (
+	"embed"
+	"time"
+)
+
+// Names of the versioned record schemas, carried in the "schema" field of
+// every record. Within a version, fields are never removed, renamed, or
+// given another meaning; new optional fields may be added, so decoders must
+// ignore unknown fields. A breaking change gets a new version, and the
+// records of the previous one stay available.
+const (
+	// EventSchemaV1 is the schema of EventV1.
+	EventSchemaV1 = "retrier.event.v1"
+
+	// SummarySchemaV1 is the schema of SummaryV1.
+	SummarySchemaV1 = "retrier.summary.v1"
+)
+
+//go:embed schema/*.json
+var schemas embed.FS
+
+// JSONSchema returns the JSON Schema document describing the records of
+// schema, such as EventSchemaV1, and false if schema is unknown. Data
+// pipelines can validate ingested records against it.
+func JSONSchema(schema string) ([]byte, bool) {
+	doc, err := schemas.ReadFile("schema/" + schema + ".json")
+	return doc, err == nil
+}
+
+// EventV1 is the stable, machine-readable form of a RetryEvent (see
+// EventSchemaV1), for logs and data pipelines that must not break when
+// RetryEvent changes. Encode it with encoding/json. Durations are integers
+// of nanoseconds and times are RFC 3339 strings.
+//
+// Example:
+//
+//	for e := range events {
+//	    _ = json.NewEncoder(out).Encode(e.V1())
+//	}
+type EventV1 struct {
+	// Schema is EventSchemaV1.
+	Schema string `json:"schema"`
+
+	// Kind is the name of the EventKind, such as "retry".
+	Kind string `json:"kind"`
+
+	// Attempt is RetryEvent.Attempt.
+	Attempt int `json:"attempt"`
+
+	// MaxAttempts is RetryEvent.MaxAttempts.
+	MaxAttempts int `json:"maxAttempts"`
+
+	// Backoff is RetryEvent.Backoff.
+	Backoff time.Duration `json:"backoff,omitempty"`
+
+	// RawBackoff is RetryEvent.RawBackoff.
+	RawBackoff time.Duration `json:"rawBackoff,omitempty"`
+
+	// Err is the message of RetryEvent.Err.
+	Err string `json:"err,omitempty"`
+
+	// Cause is the message of RetryEvent.Cause.
+	Cause string `json:"cause,omitempty"`
+
+	// Time is RetryEvent.Time.
+	Time time.Time `json:"time"`
+
+	// Decision is RetryEvent.Decision.
+	Decision *DecisionV1 `json:"decision,omitempty"`
+}
+
+// DecisionV1 is the stable form of a Decision, within an EventV1.
+type DecisionV1 struct {
+	Attempt     int           `json:"attempt"`
+	Retry       bool          `json:"retry"`
+	Rule        string        `json:"rule"`
+	Reason      string        `json:"reason"`
+	Delay       time.Duration `json:"delay,omitempty"`
+	DelaySource string        `json:"delaySource,omitempty"`
+}
+
+// V1 returns e as an EventV1.
+func (e RetryEvent) V1() EventV1 {
+	v := EventV1{
+		Schema:      EventSchemaV1,
+		Kind:        e.Kind.String(),
+		Attempt:     e.Attempt,
+		MaxAttempts: e.MaxAttempts,
+		Backoff:     e.Backoff,
+		RawBackoff:  e.RawBackoff,
+		Err:         errorText(e.Err),
+		Cause:       errorText(e.Cause),
+		Time:        e.Time,
+	}
+	if d := e.Decision; d != nil {
+		v.Decision = &DecisionV1{
+			Attempt:     d.Attempt,
+			Retry:       d.Retry,
+			Rule:        string(d.Rule),
+			Reason:      d.Reason,
+			Delay:       d.Delay,
+			DelaySource: d.DelaySource,
+		}
+	}
+	return v
+}
+
+// SummaryV1 is the stable, machine-readable form of a Summary (see
+// SummarySchemaV1). Durations are integers of nanoseconds.
+type SummaryV1 struct {
+	// Schema is SummarySchemaV1.
+	Schema string `json:"schema"`
+
+	Attempts          int           `json:"attempts"`
+	SuccessAfterRetry bool          `json:"successAfterRetry"`
+	FailureKind       string        `json:"failureKind,omitempty"`
+	TotalLatency      time.Duration `json:"totalLatency"`
+	BackoffLatency    time.Duration `json:"backoffLatency"`
+	CappedRetries     int           `json:"cappedRetries"`
+}
+
+// V1 returns s as a SummaryV1.
+func (s Summary) V1() SummaryV1 {
+	return SummaryV1{
+		Schema:            SummarySchemaV1,
+		Attempts:          s.Attempts,
+		SuccessAfterRetry: s.SuccessAfterRetry,
+		FailureKind:       s.FailureKind,
+		TotalLatency:      s.TotalLatency,
+		BackoffLatency:    s.BackoffLatency,
+		CappedRetries:     s.CappedRetries,
+	}
+}
+
+// errorText returns the message of err, or "" if err is nil.
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,55 @@
+package retrier
+
+import "context"
+
+// Semaphore limits concurrent access to a dependency. It is satisfied by
+// *semaphore.Weighted from golang.org/x/sync/semaphore; use ChanSemaphore for
+// a buffered channel.
+type Semaphore interface {
+	// Acquire blocks until n units are available or ctx is done.
+	Acquire(ctx context.Context, n int64) error
+
+	// Release returns n units.
+	Release(n int64)
+}
+
+// WithSemaphore makes every attempt hold one unit of s while fn runs, so that
+// attempts share a concurrency limit with calls to the same dependency that
+// are not retried. Backoff delays do not hold the semaphore. If ctx is done
+// while waiting for s, Retry returns ErrContextCancelled. Default is none.
+func WithSemaphore(s Semaphore) RetryOption {
+	return func(c *retryConfig) {
+		c.semaphore = s
+	}
+}
+
+// ChanSemaphore adapts a buffered channel to a Semaphore: a unit is held while
+// a value sits in ch, so cap(ch) is the concurrency limit.
+//
+// Example:
+//
+//	pool := make(chan struct{}, 10) // sized like the connection pool
+//	result := retrier.Retry(ctx, logger, query, retrier.WithSemaphore(retrier.ChanSemaphore(pool)))
+func ChanSemaphore(ch chan struct{}) Semaphore {
+	return chanSemaphore(ch)
+}
+
+type chanSemaphore chan struct{}
+
+func (s chanSemaphore) Acquire(ctx context.Context, n int64) error {
+	for i := int64(0); i < n; i++ {
+		select {
+		case s <- struct{}{}:
+		case <-ctx.Done():
+			s.Release(i)
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (s chanSemaphore) Release(n int64) {
+	for i := int64(0); i < n; i++ {
+		<-s
+	}
+}
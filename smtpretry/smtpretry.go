@@ -0,0 +1,111 @@
+// Package smtpretry classifies SMTP delivery errors and provides retry options
+// suited to mail delivery.
+//
+// SMTP encodes retryability in its reply codes (RFC 5321): 4xx replies are
+// transient and the delivery should be retried later, 5xx replies are
+// permanent. Greylisting servers reject the first delivery from an unknown
+// sender with a transient reply and accept it after a few minutes, so mail
+// retries want long, widely jittered delays.
+//
+// The package works with the errors of net/smtp, which are *textproto.Error
+// values, and with any error whose message starts with a reply code:
+//
+//	result := retrier.Retry(ctx, logger, func() (struct{}, error) {
+//	    return struct{}{}, smtp.SendMail(addr, auth, from, to, msg)
+//	}, smtpretry.Options()...)
+package smtpretry
+
+import (
+	"errors"
+	"net/textproto"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rohmanhakim/retrier"
+)
+
+// replyPattern matches a reply code at the start of an error message.
+var replyPattern = regexp.MustCompile(`^([2-5][0-9][0-9])\b`)
+
+// greylistPatterns match the wording greylisting servers use.
+var greylistPatterns = []string{
+	"greylist",
+	"graylist",
+	"try again later",
+	"please retry later",
+	"temporarily deferred",
+	"temporarily rejected",
+}
+
+// ReplyCode returns the SMTP reply code carried by err, from a
+// *textproto.Error in its chain or from the start of its message. It returns
+// false if err carries no reply code.
+func ReplyCode(err error) (int, bool) {
+	if err == nil {
+		return 0, false
+	}
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code, true
+	}
+	m := replyPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0, false
+	}
+	code, _ := strconv.Atoi(m[1])
+	return code, true
+}
+
+// IsGreylisted reports whether err looks like a greylisting rejection.
+func IsGreylisted(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, pattern := range greylistPatterns {
+		if strings.Contains(msg, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryPolicy classifies err:
+//   - greylisting rejections and 4xx replies are retried (RetryPolicyAuto);
+//   - other 5xx replies are permanent (RetryPolicyNever);
+//   - errors without a reply code, such as connection failures, are retried.
+func RetryPolicy(err error) retrier.RetryPolicy {
+	if IsGreylisted(err) {
+		return retrier.RetryPolicyAuto
+	}
+	code, ok := ReplyCode(err)
+	if ok && code >= 500 {
+		return retrier.RetryPolicyNever
+	}
+	return retrier.RetryPolicyAuto
+}
+
+// RetryIf reports whether err should be retried according to RetryPolicy.
+// It suits retrier.WithRetryIf.
+func RetryIf(err error) bool {
+	return RetryPolicy(err) == retrier.RetryPolicyAuto
+}
+
+// Options returns retry options for mail delivery: up to 8 attempts, starting
+// 5 minutes apart (past typical greylisting windows) and doubling up to
+// 4 hours, with up to 10 minutes of jitter so queued mail does not retry in
+// bursts, classified with RetryIf. Later options override them:
+//
+//	opts := append(smtpretry.Options(), retrier.WithMaxAttempts(12))
+func Options() []retrier.RetryOption {
+	return []retrier.RetryOption{
+		retrier.WithMaxAttempts(8),
+		retrier.WithInitialDuration(5 * time.Minute),
+		retrier.WithMultiplier(2),
+		retrier.WithMaxDuration(4 * time.Hour),
+		retrier.WithJitter(10 * time.Minute),
+		retrier.WithRetryIf(RetryIf),
+	}
+}
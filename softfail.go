@@ -0,0 +1,86 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+)
+
+// Degraded wraps err, the failure of a step that ran after the retried
+// function produced its value, such as enriching or caching it, to tell that
+// the value it returns with err is still usable. The attempt fails with err
+// and is retried or returned like any other error, as err is classified; with
+// WithSoftFail, the value is returned if no later attempt does better.
+// errors.Is and errors.As see through the wrapper. Degraded returns nil if
+// err is nil.
+//
+// Example:
+//
+//	func fetchProfile(ctx context.Context) (Profile, error) {
+//	    p, err := api.Profile(ctx, id)
+//	    if err != nil {
+//	        return Profile{}, err
+//	    }
+//	    if err := p.LoadAvatar(ctx); err != nil {
+//	        return p, retrier.Degraded(err) // usable without its avatar
+//	    }
+//	    return p, nil
+//	}
+func Degraded(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &degradedError{err: err}
+}
+
+// degradedError marks the value returned with an error as usable (see
+// Degraded).
+type degradedError struct {
+	err error
+}
+
+func (e *degradedError) Error() string { return e.err.Error() }
+
+func (e *degradedError) Unwrap() error { return e.err }
+
+// WithSoftFail makes a call that fails after an attempt returned a usable
+// value with a Degraded error succeed with warnings instead: the Result
+// carries the value of the last such attempt, a nil Err, and the Degraded
+// error followed by the error the call failed with in Warnings. The value
+// must pass the check of WithRetryOnResult and the validation of
+// WithSampledValidation, which always runs on it, to be usable. A later
+// attempt succeeding outright still wins. Default is off.
+//
+// Example:
+//
+//	result := retrier.RetryCtx(ctx, logger, fetchProfile, retrier.WithSoftFail())
+//	if result.IsDegraded() {
+//	    log.Printf("serving a partial profile: %v", result.Warnings())
+//	}
+func WithSoftFail() RetryOption {
+	return func(c *retryConfig) {
+		c.softFail = true
+	}
+}
+
+// usableValue reports whether value, returned with err by an attempt run
+// with ctx, is a usable value of a failed attempt under WithSoftFail.
+func (c *retryConfig) usableValue(ctx context.Context, value any, err error) bool {
+	var degraded *degradedError
+	if !c.softFail || !errors.As(err, &degraded) {
+		return false
+	}
+	if c.resultCheck != nil && c.resultCheck(value) != nil {
+		return false
+	}
+	return c.validation == nil || c.validation.check(ctx, true, value) == nil
+}
+
+// softFail turns result, a failure, into a success with warnings carrying
+// value, which an attempt returned with the Degraded error degradedErr.
+func softFail[T any](result *Result[T], value T, degradedErr error) {
+	result.warnings = []error{degradedErr}
+	if result.err != degradedErr {
+		result.warnings = append(result.warnings, result.err)
+	}
+	result.value, result.err = value, nil
+}
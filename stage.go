@@ -0,0 +1,60 @@
+package retrier
+
+import (
+	"context"
+	"sync"
+)
+
+// Stage runs a retried processing step of a channel pipeline. It reads items
+// from in, retries fn on each with opts using up to workers goroutines, and
+// sends every outcome to out. With more than one worker, outcomes are sent in
+// completion order; include the item in U to correlate them. fn receives
+// the attempt context (see RetryCtx).
+//
+// Stage blocks until in is closed and every outcome is sent, then closes out
+// and returns nil. If ctx is cancelled first, it stops reading, closes out
+// once its workers return, and returns ctx.Err(). A workers value below 1 is
+// treated as 1.
+//
+// Example:
+//
+//	g.Go(func() error {
+//	    return retrier.Stage(ctx, logger, orders, charged, 4, chargeOrder, retrier.WithMaxAttempts(5))
+//	})
+func Stage[T, U any](ctx context.Context, logger DebugLogger, in <-chan T, out chan<- Result[U], workers int, fn func(ctx context.Context, item T) (U, error), opts ...RetryOption) error {
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var item T
+				var ok bool
+				select {
+				case <-ctx.Done():
+					return
+				case item, ok = <-in:
+					if !ok {
+						return
+					}
+				}
+
+				result := RetryCtx(ctx, logger, func(actx context.Context) (U, error) {
+					return fn(actx, item)
+				}, opts...)
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- result:
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(out)
+	return ctx.Err()
+}
@@ -0,0 +1,80 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// StrictMode is what WithStrictClassification does with unclassified errors.
+type StrictMode int
+
+const (
+	// StrictOff applies the default retry policy to unclassified errors.
+	StrictOff StrictMode = iota
+
+	// StrictWarn reports unclassified errors, then applies the default
+	// retry policy to them.
+	StrictWarn
+
+	// StrictFail reports unclassified errors and stops the loop with
+	// ErrUnclassified, as for a configuration error.
+	StrictFail
+)
+
+// String returns the name of m, such as "fail".
+func (m StrictMode) String() string {
+	switch m {
+	case StrictOff:
+		return "off"
+	case StrictWarn:
+		return "warn"
+	case StrictFail:
+		return "fail"
+	default:
+		return "unknown"
+	}
+}
+
+// WithStrictClassification enforces explicit error classification: an
+// attempt error is unclassified when it has no RetryableError in its chain
+// (see Permanent) and no WithRetryIf predicate is set, so only
+// the default retry policy would decide it. Context errors, which the loop
+// handles itself, are never unclassified.
+//
+// Each unclassified error publishes an EventUnclassified (see
+// WithNotifyChannel) and logs a warning if the logger implements
+// WarningLogger. With StrictFail, the loop then returns a RetryError with
+// cause ErrUnclassified wrapping the error, instead of retrying it. Default
+// is StrictOff.
+//
+// Example:
+//
+//	// In tests and staging, catch call sites that forgot to classify
+//	result := retrier.Retry(ctx, logger, fn,
+//	    retrier.WithStrictClassification(retrier.StrictFail),
+//	)
+func WithStrictClassification(mode StrictMode) RetryOption {
+	return func(c *retryConfig) {
+		c.strict = mode
+	}
+}
+
+// unclassified reports whether err would only be decided by the default
+// retry policy.
+func (c *retryConfig) unclassified(err error) bool {
+	if c.retryIf != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var retryErr RetryableError
+	return !errors.As(err, &retryErr)
+}
+
+// reportUnclassified publishes and logs the unclassified error err of attempt.
+func (c *retryConfig) reportUnclassified(ctx context.Context, logger DebugLogger, attempt int, err error) {
+	c.publish(ctx, RetryEvent{Kind: EventUnclassified, Attempt: attempt, Err: c.redactError(err)})
+	if warner, ok := logger.(WarningLogger); ok {
+		attrs := append([]any{"attempt", attempt, "error", c.redactError(err), "type", fmt.Sprintf("%T", err)}, c.attrs...)
+		warner.LogWarning(c.logContext(ctx), "unclassified error", attrs...)
+	}
+}
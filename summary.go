@@ -0,0 +1,155 @@
+package retrier
+
+import (
+	"context"
+	"time"
+)
+
+// FailurePermanent is the FailureKind of a Summary whose loop stopped on an
+// error that is not retried, such as a permanent one.
+const FailurePermanent = "permanent"
+
+// Summary describes a whole retry loop once it returned, for SLO accounting:
+// it separates the time spent waiting between attempts from the time spent
+// in them, which only the loop knows.
+type Summary struct {
+	// Attempts is the number of attempts made.
+	Attempts int
+
+	// SuccessAfterRetry is true if the loop succeeded after at least one
+	// failed attempt.
+	SuccessAfterRetry bool
+
+	// FailureKind is empty if the loop succeeded, FailurePermanent if it
+	// stopped on an error that is not retried, and otherwise the Cause of the
+	// RetryError it returned, such as "exhausted attempt". It is meant as a
+	// low-cardinality metric label.
+	FailureKind string
+
+	// TotalLatency is the time from the start of the loop until it returned.
+	TotalLatency time.Duration
+
+	// BackoffLatency is the part of TotalLatency spent in backoff delays.
+	BackoffLatency time.Duration
+
+	// CappedRetries is the number of retries whose backoff delay the
+	// exponential curve would have made longer than WithMaxDuration allows.
+	CappedRetries int
+}
+
+// Succeeded reports whether the loop succeeded.
+func (s Summary) Succeeded() bool {
+	return s.FailureKind == ""
+}
+
+// WorkLatency is the part of TotalLatency not spent in backoff delays: in
+// attempts, and in the bookkeeping around them.
+func (s Summary) WorkLatency() time.Duration {
+	return s.TotalLatency - s.BackoffLatency
+}
+
+// SummarySink receives the Summary of each retry loop, synchronously, before
+// Retry returns. It must be safe for concurrent use when loops run
+// concurrently.
+type SummarySink func(ctx context.Context, s Summary)
+
+// WithSummarySink passes the Summary of the retry loop to sink when it
+// returns, whatever the outcome. Latencies are measured with the configured
+// Clock. Default is none.
+//
+// Example:
+//
+//	retrier.WithSummarySink(func(ctx context.Context, s retrier.Summary) {
+//	    metrics.Observe("retry_work_seconds", s.WorkLatency().Seconds())
+//	    metrics.Observe("retry_backoff_seconds", s.BackoffLatency.Seconds())
+//	    if !s.Succeeded() {
+//	        metrics.Count("retry_failures", 1, "kind", s.FailureKind)
+//	    }
+//	})
+func WithSummarySink(sink SummarySink) RetryOption {
+	return func(c *retryConfig) {
+		c.summarySink = sink
+	}
+}
+
+// loopTimer measures the time a loop spends in and out of backoff delays,
+// for its Summary, WithMaxTotalBackoff, WithMaxTotalDuration, and the
+// DeadlineUsage of a cancellable context. Its zero value, used without any
+// of them, measures nothing.
+type loopTimer struct {
+	clock      Clock
+	start      time.Time
+	sleepStart time.Time
+	backoff    time.Duration
+
+	// notRetried is set when the loop stops on an error it does not retry
+	notRetried bool
+
+	// capped counts the backoff delays WithMaxDuration capped
+	capped int
+}
+
+// newLoopTimer starts measuring a loop configured with c, run with ctx.
+func (c *retryConfig) newLoopTimer(ctx context.Context) loopTimer {
+	if c.summarySink == nil && c.maxTotalBackoff <= 0 && c.maxTotalDuration <= 0 && c.maxElapsedTime <= 0 && ctx.Done() == nil {
+		return loopTimer{}
+	}
+	return loopTimer{clock: c.clock, start: c.clock.Now()}
+}
+
+// sleep marks the start of a backoff delay, unless one is already running.
+func (t *loopTimer) sleep() {
+	if t.clock != nil && t.sleepStart.IsZero() {
+		t.sleepStart = t.clock.Now()
+	}
+}
+
+// wake marks the end of the backoff delay started last, if any.
+func (t *loopTimer) wake() {
+	if t.clock == nil || t.sleepStart.IsZero() {
+		return
+	}
+	t.backoff += t.clock.Now().Sub(t.sleepStart)
+	t.sleepStart = time.Time{}
+}
+
+// elapsed returns the time since the loop started.
+func (t *loopTimer) elapsed() time.Duration {
+	if t.clock == nil {
+		return 0
+	}
+	return t.clock.Now().Sub(t.start)
+}
+
+// waited returns the time spent in backoff delays so far, including the
+// running one.
+func (t *loopTimer) waited() time.Duration {
+	if t.clock == nil || t.sleepStart.IsZero() {
+		return t.backoff
+	}
+	return t.backoff + t.clock.Now().Sub(t.sleepStart)
+}
+
+// publishSummary passes the Summary of a loop measured by t, which returned
+// err after attempts, to the SummarySink.
+func (c *retryConfig) publishSummary(ctx context.Context, t *loopTimer, attempts int, err error) {
+	if c.summarySink == nil {
+		return
+	}
+	t.wake()
+	s := Summary{
+		Attempts:       attempts,
+		TotalLatency:   c.clock.Now().Sub(t.start),
+		BackoffLatency: t.backoff,
+		CappedRetries:  t.capped,
+	}
+	switch retryErr, ok := err.(*RetryError); {
+	case err == nil:
+		s.SuccessAfterRetry = attempts > 1
+	case t.notRetried || !ok:
+		s.FailureKind = FailurePermanent
+	default:
+		s.FailureKind = string(retryErr.Cause)
+	}
+	c.summarySink(ctx, s)
+}
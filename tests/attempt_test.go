@@ -0,0 +1,84 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+type requestIDKey struct{}
+
+// TestWithAttemptContext_Values verifies that hooks attach per-attempt values,
+// compose in order, and clean up after each attempt.
+func TestWithAttemptContext_Values(t *testing.T) {
+	var events []string
+	var seen []string
+
+	opts := append(defaultTestOpts(),
+		retrier.WithAttemptContext(func(ctx context.Context, attempt int) (context.Context, func()) {
+			events = append(events, fmt.Sprintf("derive-a-%d", attempt))
+			id := fmt.Sprintf("req-%d", attempt)
+			return context.WithValue(ctx, requestIDKey{}, id), func() {
+				events = append(events, fmt.Sprintf("cleanup-a-%d", attempt))
+			}
+		}),
+		retrier.WithAttemptContext(func(ctx context.Context, attempt int) (context.Context, func()) {
+			events = append(events, fmt.Sprintf("derive-b-%d:%v", attempt, ctx.Value(requestIDKey{})))
+			return ctx, func() {
+				events = append(events, fmt.Sprintf("cleanup-b-%d", attempt))
+			}
+		}),
+	)
+
+	result := retrier.RetryCtx(context.Background(), noopLogger, func(ctx context.Context) (int, error) {
+		seen = append(seen, ctx.Value(requestIDKey{}).(string))
+		if len(seen) < 2 {
+			return 0, errors.New("transient")
+		}
+		return 1, nil
+	}, opts...)
+
+	if result.IsFailure() {
+		t.Fatalf("unexpected error: %v", result.Err())
+	}
+	if want := []string{"req-1", "req-2"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("seen request IDs = %v, want %v", seen, want)
+	}
+	want := []string{
+		"derive-a-1", "derive-b-1:req-1", "cleanup-b-1", "cleanup-a-1",
+		"derive-a-2", "derive-b-2:req-2", "cleanup-b-2", "cleanup-a-2",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+// TestWithAttemptContext_Timeout verifies that a per-attempt deadline cancels
+// only the attempt, so the next attempt gets a fresh deadline.
+func TestWithAttemptContext_Timeout(t *testing.T) {
+	calls := 0
+	result := retrier.RetryCtx(context.Background(), noopLogger, func(ctx context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		return "ok", nil
+	}, append(defaultTestOpts(),
+		retrier.WithAttemptContext(func(ctx context.Context, _ int) (context.Context, func()) {
+			return context.WithTimeout(ctx, 10*time.Millisecond)
+		}),
+	)...)
+
+	if result.Value() != "ok" || result.Attempts() != 2 {
+		t.Errorf("got %q after %d attempts (err %v), want \"ok\" after 2", result.Value(), result.Attempts(), result.Err())
+	}
+}
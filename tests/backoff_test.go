@@ -2,6 +2,7 @@ package retrier_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -293,3 +294,398 @@ func TestBackoff_ExponentialGrowth(t *testing.T) {
 		}
 	}
 }
+
+// firstBackoff runs a failing-then-succeeding function and returns the first backoff delay.
+func firstBackoff(t *testing.T, opts ...retrier.RetryOption) time.Duration {
+	t.Helper()
+	mock := &backoffMockLogger{enabled: true}
+	callCount := 0
+	fn := func() (string, error) {
+		callCount++
+		if callCount == 1 {
+			return "", &mockRetryableError{msg: "error"}
+		}
+		return "success", nil
+	}
+	retrier.Retry(context.Background(), mock, fn, append(opts, retrier.WithMaxAttempts(2))...)
+	if len(mock.logRetryCalls) == 0 {
+		t.Fatal("expected a retry log call")
+	}
+	return mock.logRetryCalls[0].backoff
+}
+
+// TestBackoff_InstanceKey verifies that WithInstanceKey adds a deterministic,
+// per-instance offset within [0, initialDuration) to the first backoff.
+func TestBackoff_InstanceKey(t *testing.T) {
+	initial := 10 * time.Millisecond
+	opts := func(key string) []retrier.RetryOption {
+		return []retrier.RetryOption{
+			retrier.WithInitialDuration(initial),
+			retrier.WithMaxDuration(time.Second),
+			retrier.WithInstanceKey(key),
+		}
+	}
+
+	podA1 := firstBackoff(t, opts("pod-a")...)
+	podA2 := firstBackoff(t, opts("pod-a")...)
+	podB := firstBackoff(t, opts("pod-b")...)
+
+	if podA1 != podA2 {
+		t.Errorf("expected the same key to produce the same delay, got %v and %v", podA1, podA2)
+	}
+	if podA1 == podB {
+		t.Errorf("expected different keys to produce different delays, both got %v", podA1)
+	}
+	for _, d := range []time.Duration{podA1, podB} {
+		if d < initial || d >= 2*initial {
+			t.Errorf("delay %v outside [%v, %v)", d, initial, 2*initial)
+		}
+	}
+}
+
+// TestBackoff_NoInstanceKey verifies that no offset is applied by default.
+func TestBackoff_NoInstanceKey(t *testing.T) {
+	d := firstBackoff(t,
+		retrier.WithInitialDuration(10*time.Millisecond),
+		retrier.WithMaxDuration(time.Second),
+	)
+	if d != 10*time.Millisecond {
+		t.Errorf("expected 10ms, got %v", d)
+	}
+}
+
+// fixedStrategy is a retrier.BackoffStrategy returning fixed delays until they run out.
+type fixedStrategy struct {
+	delays []time.Duration
+	errs   []error
+}
+
+func (s *fixedStrategy) NextDelay(retry int, err error) (time.Duration, bool) {
+	s.errs = append(s.errs, err)
+	if retry > len(s.delays) {
+		return 0, false
+	}
+	return s.delays[retry-1], true
+}
+
+// TestBackoff_CustomStrategy verifies that WithBackoff replaces the exponential delays.
+func TestBackoff_CustomStrategy(t *testing.T) {
+	strategy := &fixedStrategy{delays: []time.Duration{3 * time.Millisecond, time.Millisecond}}
+	mock := &backoffMockLogger{enabled: true}
+	attemptErr := &mockRetryableError{msg: "error"}
+	fn := func() (string, error) { return "", attemptErr }
+
+	retrier.Retry(context.Background(), mock, fn,
+		retrier.WithMaxAttempts(3),
+		retrier.WithInitialDuration(time.Hour),
+		retrier.WithBackoff(func() retrier.BackoffStrategy { return strategy }),
+	)
+
+	if len(mock.logRetryCalls) < 2 {
+		t.Fatalf("expected at least 2 retry logs, got %d", len(mock.logRetryCalls))
+	}
+	for i, want := range strategy.delays {
+		if got := mock.logRetryCalls[i].backoff; got != want {
+			t.Errorf("retry %d: expected backoff %v, got %v", i+1, want, got)
+		}
+	}
+	for _, err := range strategy.errs {
+		if err != attemptErr {
+			t.Errorf("expected strategy to receive the attempt error, got %v", err)
+		}
+	}
+}
+
+// TestBackoff_CustomStrategyStops verifies that a stopping strategy ends Retry
+// with ErrBackoffStopped.
+func TestBackoff_CustomStrategyStops(t *testing.T) {
+	strategy := &fixedStrategy{delays: []time.Duration{time.Millisecond}}
+	attemptErr := &mockRetryableError{msg: "error"}
+	fn := func() (string, error) { return "", attemptErr }
+
+	result := retrier.Retry(context.Background(), noopLogger, fn,
+		retrier.WithMaxAttempts(5),
+		retrier.WithBackoff(func() retrier.BackoffStrategy { return strategy }),
+	)
+
+	if result.Attempts() != 2 {
+		t.Errorf("expected 2 attempts, got %d", result.Attempts())
+	}
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrBackoffStopped {
+		t.Fatalf("expected ErrBackoffStopped, got %v", result.Err())
+	}
+	if !errors.Is(result.Err(), attemptErr) {
+		t.Error("expected the attempt error to be wrapped")
+	}
+}
+
+// TestBackoff_CustomStrategyHonorsServerDelay verifies that a server-suggested
+// delay is a minimum for custom strategies.
+func TestBackoff_CustomStrategyHonorsServerDelay(t *testing.T) {
+	strategy := &fixedStrategy{delays: []time.Duration{time.Millisecond}}
+	mock := &backoffMockLogger{enabled: true}
+	callCount := 0
+	fn := func() (string, error) {
+		callCount++
+		if callCount == 1 {
+			return "", &mockErrorWithDelay{msg: "rate limited", retryable: true, suggestedDelay: 5 * time.Millisecond}
+		}
+		return "success", nil
+	}
+
+	retrier.Retry(context.Background(), mock, fn,
+		retrier.WithBackoff(func() retrier.BackoffStrategy { return strategy }),
+	)
+
+	if got := mock.logRetryCalls[0].backoff; got != 5*time.Millisecond {
+		t.Errorf("expected server delay 5ms, got %v", got)
+	}
+}
+
+// TestConstantBackoff verifies that every retry waits the constant delay,
+// ignoring the exponential settings but not jitter.
+func TestConstantBackoff(t *testing.T) {
+	logger := &backoffMockLogger{enabled: true}
+	retrier.Retry(context.Background(), logger, func() (int, error) {
+		return 0, errors.New("transient")
+	}, retrier.WithMaxAttempts(4), retrier.ConstantBackoff(3*time.Millisecond),
+		retrier.WithInitialDuration(time.Second), retrier.WithMultiplier(10),
+		retrier.WithMaxDuration(time.Millisecond), retrier.WithJitter(2*time.Millisecond))
+
+	var got []time.Duration
+	for _, call := range logger.logRetryCalls {
+		if call.backoff > 0 {
+			got = append(got, call.backoff)
+		}
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 delays, got %v", got)
+	}
+	for i, delay := range got {
+		if delay < 3*time.Millisecond || delay >= 5*time.Millisecond {
+			t.Errorf("retry %d: delay %v, want 3ms plus jitter below 2ms", i+1, delay)
+		}
+	}
+}
+
+// TestWithLinearBackoff verifies that delays grow by the increment from the
+// initial duration until WithMaxDuration caps them.
+func TestWithLinearBackoff(t *testing.T) {
+	logger := &backoffMockLogger{enabled: true}
+	retrier.Retry(context.Background(), logger, func() (int, error) {
+		return 0, errors.New("transient")
+	}, retrier.WithMaxAttempts(6), retrier.WithLinearBackoff(3*time.Millisecond),
+		retrier.WithInitialDuration(2*time.Millisecond), retrier.WithMultiplier(10),
+		retrier.WithMaxDuration(9*time.Millisecond))
+
+	want := []time.Duration{2 * time.Millisecond, 5 * time.Millisecond, 8 * time.Millisecond, 9 * time.Millisecond, 9 * time.Millisecond}
+	var got []time.Duration
+	for _, call := range logger.logRetryCalls {
+		if call.backoff > 0 {
+			got = append(got, call.backoff)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d delays, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("retry %d: delay %v, want %v", i+1, got[i], want[i])
+		}
+	}
+}
+
+// TestWithFastRetries verifies that the first retries wait the fast delay and
+// the exponential curve starts afterwards.
+func TestWithFastRetries(t *testing.T) {
+	logger := &backoffMockLogger{enabled: true}
+	retrier.Retry(context.Background(), logger, func() (int, error) {
+		return 0, errors.New("transient")
+	}, retrier.WithMaxAttempts(6), retrier.WithFastRetries(2, time.Millisecond),
+		retrier.WithInitialDuration(5*time.Millisecond), retrier.WithMultiplier(2),
+		retrier.WithMaxDuration(time.Second))
+
+	want := []time.Duration{time.Millisecond, time.Millisecond, 5 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond}
+	var got []time.Duration
+	for _, call := range logger.logRetryCalls {
+		if call.backoff > 0 {
+			got = append(got, call.backoff)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d delays, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("retry %d: delay %v, want %v", i+1, got[i], want[i])
+		}
+	}
+}
+
+// TestWithFastRetries_ServerDelay verifies that a server-suggested delay
+// still acts as a minimum during fast retries.
+func TestWithFastRetries_ServerDelay(t *testing.T) {
+	logger := &backoffMockLogger{enabled: true}
+	retrier.Retry(context.Background(), logger, func() (int, error) {
+		return 0, &mockErrorWithDelay{msg: "slow down", retryable: true, suggestedDelay: 15 * time.Millisecond}
+	}, retrier.WithMaxAttempts(2), retrier.WithFastRetries(1, time.Millisecond))
+
+	if len(logger.logRetryCalls) == 0 || logger.logRetryCalls[0].backoff != 15*time.Millisecond {
+		t.Errorf("expected the first delay to be the suggested 15ms, got %+v", logger.logRetryCalls)
+	}
+}
+
+// TestWithMaxTotalBackoff verifies that the last delay is shortened to what
+// is left of the total backoff and that the loop stops once it is spent.
+func TestWithMaxTotalBackoff(t *testing.T) {
+	const total = 50 * time.Millisecond
+	logger := &backoffMockLogger{enabled: true}
+	result := retrier.Retry(context.Background(), logger, func() (int, error) {
+		return 0, errors.New("transient")
+	}, retrier.WithMaxAttempts(10), retrier.WithInitialDuration(10*time.Millisecond),
+		retrier.WithMultiplier(2), retrier.WithMaxTotalBackoff(total))
+
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrTotalBackoffExceeded {
+		t.Fatalf("expected ErrTotalBackoffExceeded, got %v", result.Err())
+	}
+	var sum time.Duration
+	var delays []time.Duration
+	for _, call := range logger.logRetryCalls {
+		sum += call.backoff
+		delays = append(delays, call.backoff)
+	}
+	if sum > total {
+		t.Errorf("delays %v add up to %v, more than %v", delays, sum, total)
+	}
+	if len(delays) != 3 || delays[2] >= 40*time.Millisecond {
+		t.Errorf("expected 3 delays, the last shortened below 40ms, got %v", delays)
+	}
+}
+
+// TestWithSeverity verifies that severity weights scale the backoff delay,
+// within WithMaxDuration, with the option taking precedence over the error.
+func TestWithSeverity(t *testing.T) {
+	overloaded := errors.New("overloaded")
+	blip := errors.New("connection reset")
+	errs := []error{retrier.Severity(overloaded, 2), blip, retrier.Severity(overloaded, 10), overloaded, overloaded}
+
+	logger := &backoffMockLogger{enabled: true}
+	attempt := 0
+	retrier.Retry(context.Background(), logger, func() (int, error) {
+		err := errs[attempt]
+		attempt++
+		return 0, err
+	}, retrier.WithMaxAttempts(len(errs)), retrier.WithInitialDuration(4*time.Millisecond),
+		retrier.WithMultiplier(1), retrier.WithMaxDuration(20*time.Millisecond),
+		retrier.WithSeverity(func(err error) float64 {
+			if errors.Is(err, blip) {
+				return 0.5
+			}
+			return 0 // defer to the error
+		}))
+
+	want := []time.Duration{8 * time.Millisecond, 2 * time.Millisecond, 20 * time.Millisecond, 4 * time.Millisecond}
+	var got []time.Duration
+	for _, call := range logger.logRetryCalls {
+		if call.backoff > 0 {
+			got = append(got, call.backoff)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d delays, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("retry %d: delay %v, want %v", i+1, got[i], want[i])
+		}
+	}
+}
+
+// TestWithSeverity_ServerDelay verifies that a light severity does not
+// shorten a server-suggested delay.
+func TestWithSeverity_ServerDelay(t *testing.T) {
+	logger := &backoffMockLogger{enabled: true}
+	retrier.Retry(context.Background(), logger, func() (int, error) {
+		return 0, &mockErrorWithDelay{msg: "slow down", retryable: true, suggestedDelay: 10 * time.Millisecond}
+	}, retrier.WithMaxAttempts(2), retrier.WithInitialDuration(time.Millisecond),
+		retrier.WithSeverity(func(error) float64 { return 0.1 }))
+
+	if len(logger.logRetryCalls) == 0 || logger.logRetryCalls[0].backoff != 10*time.Millisecond {
+		t.Errorf("expected the suggested 10ms delay, got %+v", logger.logRetryCalls)
+	}
+}
+
+// jitteredDelays runs loops of 6 failing attempts over a 2ms to 16ms curve
+// with mode and returns the delays before the retries of each loop.
+func jitteredDelays(mode retrier.JitterMode, err error) [][]time.Duration {
+	var loops [][]time.Duration
+	for i := 0; i < 10; i++ {
+		logger := &backoffMockLogger{enabled: true}
+		retrier.Retry(context.Background(), logger, func() (int, error) {
+			return 0, err
+		}, retrier.WithMaxAttempts(6), retrier.WithInitialDuration(2*time.Millisecond),
+			retrier.WithMultiplier(2), retrier.WithMaxDuration(16*time.Millisecond),
+			retrier.WithJitter(time.Hour), // ignored by randomizing modes
+			retrier.WithJitterMode(mode))
+
+		var delays []time.Duration
+		for _, call := range logger.logRetryCalls[:len(logger.logRetryCalls)-1] {
+			delays = append(delays, call.backoff)
+		}
+		loops = append(loops, delays)
+	}
+	return loops
+}
+
+// TestWithJitterMode verifies the range each jitter mode draws delays from.
+func TestWithJitterMode(t *testing.T) {
+	curve := []time.Duration{2, 4, 8, 16, 16}
+	for i := range curve {
+		curve[i] *= time.Millisecond
+	}
+	tests := []struct {
+		mode    retrier.JitterMode
+		between func(retry int, prev time.Duration) (low, high time.Duration)
+	}{
+		{retrier.JitterFull, func(retry int, _ time.Duration) (time.Duration, time.Duration) {
+			return 0, curve[retry]
+		}},
+		{retrier.JitterEqual, func(retry int, _ time.Duration) (time.Duration, time.Duration) {
+			return curve[retry] / 2, curve[retry]
+		}},
+		{retrier.JitterDecorrelated, func(_ int, prev time.Duration) (time.Duration, time.Duration) {
+			return 2 * time.Millisecond, min(3*max(prev, 2*time.Millisecond), 16*time.Millisecond)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			for _, delays := range jitteredDelays(tt.mode, errors.New("transient")) {
+				var prev time.Duration
+				for retry, delay := range delays {
+					low, high := tt.between(retry, prev)
+					if delay < low || delay >= high {
+						t.Errorf("retry %d: expected a delay in [%v, %v), got %v (delays %v)", retry+1, low, high, delay, delays)
+					}
+					prev = delay
+				}
+			}
+		})
+	}
+}
+
+// TestWithJitterMode_ServerDelay verifies that server-suggested delays remain
+// a minimum when jitter randomizes the delay.
+func TestWithJitterMode_ServerDelay(t *testing.T) {
+	err := &mockErrorWithDelay{msg: "slow down", retryable: true, suggestedDelay: 5 * time.Millisecond}
+	for _, mode := range []retrier.JitterMode{retrier.JitterFull, retrier.JitterDecorrelated} {
+		for _, delays := range jitteredDelays(mode, err) {
+			for _, delay := range delays {
+				if delay < 5*time.Millisecond {
+					t.Errorf("%v: expected delays of at least 5ms, got %v", mode, delays)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/retriertest"
+)
+
+// failingFn returns a function that always fails with a retryable error.
+func failingFn() func() (string, error) {
+	return func() (string, error) {
+		return "", errors.New("transient")
+	}
+}
+
+// budgetTestOpts returns fast options for budget tests.
+func budgetTestOpts(budget *retrier.RetryBudget, maxAttempts int) []retrier.RetryOption {
+	return []retrier.RetryOption{
+		retrier.WithInitialDuration(time.Millisecond),
+		retrier.WithMaxDuration(time.Millisecond),
+		retrier.WithMaxAttempts(maxAttempts),
+		retrier.WithBudget(budget),
+	}
+}
+
+// TestRetryBudget_MinRetries verifies that minRetries are allowed regardless of traffic.
+func TestRetryBudget_MinRetries(t *testing.T) {
+	budget := retrier.NewRetryBudget(0, retrier.WithMinRetries(3), retrier.WithBudgetWindow(time.Hour))
+
+	result := retrier.Retry(context.Background(), noopLogger, failingFn(), budgetTestOpts(budget, 10)...)
+
+	// 1 initial attempt + 3 budgeted retries
+	if result.Attempts() != 4 {
+		t.Errorf("expected 4 attempts, got %d", result.Attempts())
+	}
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) {
+		t.Fatalf("expected RetryError, got %T", result.Err())
+	}
+	if retryErr.Cause != retrier.ErrBudgetExhausted {
+		t.Errorf("expected cause %q, got %q", retrier.ErrBudgetExhausted, retryErr.Cause)
+	}
+	if retryErr.RetryPolicy() != retrier.RetryPolicyManual {
+		t.Errorf("expected RetryPolicyManual, got %v", retryErr.RetryPolicy())
+	}
+}
+
+// TestRetryBudget_Ratio verifies that each request earns ratio retries.
+func TestRetryBudget_Ratio(t *testing.T) {
+	budget := retrier.NewRetryBudget(0.5, retrier.WithMinRetries(0), retrier.WithBudgetWindow(time.Hour))
+	success := func() (string, error) { return "ok", nil }
+
+	// Four successful requests earn two retries
+	for i := 0; i < 4; i++ {
+		retrier.Retry(context.Background(), noopLogger, success, budgetTestOpts(budget, 3)...)
+	}
+
+	// The failing request itself adds 0.5, for a total of 2.5 allowed retries
+	result := retrier.Retry(context.Background(), noopLogger, failingFn(), budgetTestOpts(budget, 10)...)
+	if result.Attempts() != 3 {
+		t.Errorf("expected 3 attempts, got %d", result.Attempts())
+	}
+}
+
+// TestRetryBudget_SharedStore verifies that budgets sharing a store and key share counters.
+func TestRetryBudget_SharedStore(t *testing.T) {
+	store := retrier.NewMemoryBudgetStore()
+	first := retrier.NewRetryBudget(0,
+		retrier.WithMinRetries(2),
+		retrier.WithBudgetWindow(time.Hour),
+		retrier.WithBudgetStore(store, "svc"),
+	)
+	second := retrier.NewRetryBudget(0,
+		retrier.WithMinRetries(2),
+		retrier.WithBudgetWindow(time.Hour),
+		retrier.WithBudgetStore(store, "svc"),
+	)
+
+	r1 := retrier.Retry(context.Background(), noopLogger, failingFn(), budgetTestOpts(first, 2)...)
+	r2 := retrier.Retry(context.Background(), noopLogger, failingFn(), budgetTestOpts(second, 2)...)
+	r3 := retrier.Retry(context.Background(), noopLogger, failingFn(), budgetTestOpts(first, 2)...)
+
+	if r1.Attempts() != 2 || r2.Attempts() != 2 {
+		t.Errorf("expected the first two calls to retry once, got %d and %d attempts", r1.Attempts(), r2.Attempts())
+	}
+	if r3.Attempts() != 1 {
+		t.Errorf("expected the shared budget to be exhausted, got %d attempts", r3.Attempts())
+	}
+}
+
+// TestRetryBudget_StoreErrorFailsOpen verifies that store failures do not block retries.
+func TestRetryBudget_StoreErrorFailsOpen(t *testing.T) {
+	budget := retrier.NewRetryBudget(0,
+		retrier.WithMinRetries(0),
+		retrier.WithBudgetStore(failingBudgetStore{}, "svc"),
+	)
+
+	result := retrier.Retry(context.Background(), noopLogger, failingFn(), budgetTestOpts(budget, 3)...)
+
+	if result.Attempts() != 3 {
+		t.Errorf("expected 3 attempts, got %d", result.Attempts())
+	}
+}
+
+// TestMemoryBudgetStore_Expiry verifies that counters expire after their TTL.
+func TestMemoryBudgetStore_Expiry(t *testing.T) {
+	store := retrier.NewMemoryBudgetStore()
+	ctx := context.Background()
+
+	if v, _ := store.Add(ctx, "c", 2, 10*time.Millisecond); v != 2 {
+		t.Fatalf("Add() = %d, want 2", v)
+	}
+	if v, _ := store.Get(ctx, "c"); v != 2 {
+		t.Fatalf("Get() = %d, want 2", v)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if v, _ := store.Get(ctx, "c"); v != 0 {
+		t.Errorf("Get() after expiry = %d, want 0", v)
+	}
+	if v, _ := store.Add(ctx, "c", 1, time.Second); v != 1 {
+		t.Errorf("Add() after expiry = %d, want 1", v)
+	}
+}
+
+// failingBudgetStore is a BudgetStore whose operations always fail.
+type failingBudgetStore struct{}
+
+func (failingBudgetStore) Add(context.Context, string, int64, time.Duration) (int64, error) {
+	return 0, errors.New("store unavailable")
+}
+
+func (failingBudgetStore) Get(context.Context, string) (int64, error) {
+	return 0, errors.New("store unavailable")
+}
+
+// TestRetryBudget_ForTenantIsolation verifies that a noisy tenant exhausts only its own budget.
+func TestRetryBudget_ForTenantIsolation(t *testing.T) {
+	budget := retrier.NewRetryBudget(0, retrier.WithMinRetries(1), retrier.WithBudgetWindow(time.Hour))
+	noisy := budget.ForTenant("noisy")
+	quiet := budget.ForTenant("quiet")
+
+	if budget.ForTenant("noisy") != noisy {
+		t.Error("expected ForTenant to return the same budget for the same id")
+	}
+
+	for i := 0; i < 3; i++ {
+		retrier.Retry(context.Background(), noopLogger, failingFn(), budgetTestOpts(noisy, 2)...)
+	}
+	result := retrier.Retry(context.Background(), noopLogger, failingFn(), budgetTestOpts(quiet, 2)...)
+
+	if result.Attempts() != 2 {
+		t.Errorf("expected the quiet tenant to retry, got %d attempts", result.Attempts())
+	}
+}
+
+// TestRetryBudget_TenantStats verifies per-tenant metrics.
+func TestRetryBudget_TenantStats(t *testing.T) {
+	ctx := context.Background()
+	budget := retrier.NewRetryBudget(0, retrier.WithMinRetries(1), retrier.WithBudgetWindow(time.Hour))
+	tenant := budget.ForTenant("acme")
+
+	for i := 0; i < 3; i++ {
+		retrier.Retry(ctx, noopLogger, failingFn(), budgetTestOpts(tenant, 2)...)
+	}
+
+	stats, err := tenant.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	want := retrier.BudgetStats{Requests: 3, Retries: 1, Denied: 2}
+	if stats != want {
+		t.Errorf("Stats() = %+v, want %+v", stats, want)
+	}
+
+	all, err := budget.TenantStats(ctx)
+	if err != nil {
+		t.Fatalf("TenantStats() error = %v", err)
+	}
+	if len(all) != 1 || all["acme"] != want {
+		t.Errorf("TenantStats() = %+v, want map[acme:%+v]", all, want)
+	}
+
+	parent, _ := budget.Stats(ctx)
+	if parent != (retrier.BudgetStats{}) {
+		t.Errorf("expected tenant activity to stay out of the parent budget, got %+v", parent)
+	}
+}
+
+// TestRetryBudget_EvictsIdleTenants verifies that tenants unused for two windows are dropped
+// while tenants still in use are kept.
+func TestRetryBudget_EvictsIdleTenants(t *testing.T) {
+	ctx := context.Background()
+	budget := retrier.NewRetryBudget(0, retrier.WithBudgetWindow(20*time.Millisecond))
+	idle := budget.ForTenant("idle")
+	active := budget.ForTenant("active")
+
+	for i := 0; i < 6; i++ {
+		time.Sleep(10 * time.Millisecond)
+		retrier.Retry(ctx, noopLogger, func() (string, error) { return "ok", nil }, budgetTestOpts(active, 1)...)
+	}
+
+	all, err := budget.TenantStats(ctx)
+	if err != nil {
+		t.Fatalf("TenantStats() error = %v", err)
+	}
+	if _, ok := all["idle"]; ok || len(all) != 1 {
+		t.Errorf("expected only the active tenant kept, got %+v", all)
+	}
+	if budget.ForTenant("active") != active {
+		t.Error("expected ForTenant to keep the budget of the active tenant")
+	}
+	if budget.ForTenant("idle") == idle {
+		t.Error("expected ForTenant to recreate the budget of the evicted tenant")
+	}
+}
+
+// TestRetryBudget_Clock verifies that the budget clock decides the accounting window.
+func TestRetryBudget_Clock(t *testing.T) {
+	ctx := context.Background()
+	clock := retriertest.NewFakeClock(time.Unix(0, 0))
+	budget := retrier.NewRetryBudget(0, retrier.WithMinRetries(1),
+		retrier.WithBudgetWindow(time.Minute), retrier.WithBudgetClock(clock))
+
+	retrier.Retry(ctx, noopLogger, failingFn(), budgetTestOpts(budget, 3)...)
+	if stats, _ := budget.Stats(ctx); stats != (retrier.BudgetStats{Requests: 1, Retries: 1, Denied: 1}) {
+		t.Fatalf("Stats() = %+v, want the exhausted window", stats)
+	}
+
+	clock.Advance(time.Minute)
+	if stats, _ := budget.Stats(ctx); stats != (retrier.BudgetStats{}) {
+		t.Errorf("Stats() = %+v, want a fresh window once the clock moved", stats)
+	}
+	result := retrier.Retry(ctx, noopLogger, failingFn(), budgetTestOpts(budget, 2)...)
+	if result.Attempts() != 2 {
+		t.Errorf("expected a retry in the new window, got %d attempts", result.Attempts())
+	}
+}
+
+// TestRetryBudget_ZeroWindow verifies that a window of 0 keeps the default instead of
+// dividing by zero.
+func TestRetryBudget_ZeroWindow(t *testing.T) {
+	budget := retrier.NewRetryBudget(0.1, retrier.WithBudgetWindow(0))
+	result := retrier.Retry(context.Background(), noopLogger, failingFn(), budgetTestOpts(budget, 2)...)
+	if result.Attempts() != 2 {
+		t.Errorf("expected 2 attempts, got %d", result.Attempts())
+	}
+}
+
+// TestMemoryBudgetStore_MixedExpiry verifies that a counter expiring before those added
+// earlier is evicted on time, and that counters still live are kept.
+func TestMemoryBudgetStore_MixedExpiry(t *testing.T) {
+	ctx := context.Background()
+	store := retrier.NewMemoryBudgetStore()
+	_, _ = store.Add(ctx, "long", 1, time.Hour)
+	_, _ = store.Add(ctx, "short", 1, 10*time.Millisecond)
+
+	time.Sleep(20 * time.Millisecond)
+	if n, _ := store.Add(ctx, "short", 1, 10*time.Millisecond); n != 1 {
+		t.Errorf("expected the expired counter to restart at 1, got %d", n)
+	}
+	if n, _ := store.Get(ctx, "long"); n != 1 {
+		t.Errorf("expected the live counter kept, got %d", n)
+	}
+}
@@ -0,0 +1,159 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestRetryCtx_PassesContext verifies that each attempt receives a context
+// derived from the caller's.
+func TestRetryCtx_PassesContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "v")
+	calls := 0
+
+	result := retrier.RetryCtx(ctx, noopLogger, func(ctx context.Context) (string, error) {
+		calls++
+		if calls < 2 {
+			return "", errors.New("transient")
+		}
+		return ctx.Value(key{}).(string), nil
+	}, defaultTestOpts()...)
+
+	if result.Value() != "v" || result.Attempts() != 2 {
+		t.Errorf("got %q after %d attempts, want \"v\" after 2", result.Value(), result.Attempts())
+	}
+}
+
+// TestCheckCancel_StopMidAttempt verifies that a StopSignal cancels the context
+// of the running attempt and that CheckCancel reports why.
+func TestCheckCancel_StopMidAttempt(t *testing.T) {
+	stop := retrier.NewStopSignal()
+	started := make(chan struct{})
+	var checkErr error
+
+	go func() {
+		<-started
+		stop.Stop()
+	}()
+
+	result := retrier.RetryCtx(context.Background(), noopLogger, func(ctx context.Context) (int, error) {
+		if err := retrier.CheckCancel(ctx); err != nil {
+			t.Errorf("expected no cancellation before Stop, got %v", err)
+		}
+		close(started)
+		<-ctx.Done()
+		checkErr = retrier.CheckCancel(ctx)
+		return 0, checkErr
+	}, append(defaultTestOpts(), retrier.WithStopSignal(stop))...)
+
+	var retryErr *retrier.RetryError
+	if !errors.As(checkErr, &retryErr) || retryErr.Cause != retrier.ErrRetryStopped {
+		t.Fatalf("CheckCancel() = %v, want ErrRetryStopped", checkErr)
+	}
+	if result.Attempts() != 1 {
+		t.Errorf("expected 1 attempt, got %d", result.Attempts())
+	}
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrRetryStopped {
+		t.Errorf("expected ErrRetryStopped, got %v", result.Err())
+	}
+}
+
+// TestWithStopSignal_DuringBackoff verifies that Stop ends a backoff delay and
+// returns ErrRetryStopped wrapping the last error.
+func TestWithStopSignal_DuringBackoff(t *testing.T) {
+	stop := retrier.NewStopSignal()
+	transient := errors.New("transient")
+	time.AfterFunc(20*time.Millisecond, stop.Stop)
+
+	start := time.Now()
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		return 0, transient
+	}, retrier.WithInitialDuration(time.Minute), retrier.WithMaxDuration(time.Minute), retrier.WithStopSignal(stop))
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Stop did not end the backoff delay, took %v", elapsed)
+	}
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrRetryStopped {
+		t.Fatalf("expected ErrRetryStopped, got %v", result.Err())
+	}
+	if !errors.Is(result.Err(), transient) {
+		t.Errorf("expected the last error in the chain, got %v", result.Err())
+	}
+	if !stop.Stopped() {
+		t.Error("expected Stopped() to be true")
+	}
+}
+
+// TestCheckCancel_ParentCancelled verifies that CheckCancel reports the cause
+// of a cancelled parent context.
+func TestCheckCancel_ParentCancelled(t *testing.T) {
+	cause := errors.New("shutting down")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	if err := retrier.CheckCancel(ctx); err != nil {
+		t.Errorf("CheckCancel() = %v, want nil", err)
+	}
+	cancel(cause)
+	if err := retrier.CheckCancel(ctx); !errors.Is(err, cause) {
+		t.Errorf("CheckCancel() = %v, want %v", err, cause)
+	}
+}
+
+// TestContextCause_Backoff verifies that a loop cancelled with a cause during
+// a backoff delay returns an error carrying the cause.
+func TestContextCause_Backoff(t *testing.T) {
+	breakerOpen := errors.New("breaker opened")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	result := retrier.Retry(ctx, noopLogger, func() (int, error) {
+		cancel(breakerOpen)
+		return 0, errors.New("transient")
+	}, retrier.WithInitialDuration(time.Hour))
+
+	err := result.Err()
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, breakerOpen) {
+		t.Errorf("expected context.Canceled caused by the breaker, got %v", err)
+	}
+}
+
+// TestContextCause_Attempt verifies that the cause of the cancellation of an
+// attempt context is recorded in the attempt history and events.
+func TestContextCause_Attempt(t *testing.T) {
+	events := make(chan retrier.RetryEvent, 8)
+	result := retrier.RetryCtx(context.Background(), noopLogger, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}, retrier.WithMaxAttempts(2), retrier.WithInitialDuration(time.Millisecond),
+		retrier.WithMaxAttemptDuration(10*time.Millisecond), retrier.WithNotifyChannel(events))
+
+	history := result.History()
+	if len(history) != 2 {
+		t.Fatalf("expected 2 failed attempts, got %v", history)
+	}
+	cause := history[0].Cause
+	if !errors.Is(cause, context.DeadlineExceeded) || !strings.Contains(cause.Error(), "attempt 1 exceeded its time limit") {
+		t.Errorf("expected the time limit as the cause, got %v", cause)
+	}
+	if e := <-events; e.Kind != retrier.EventRetry || e.Cause == nil || e.Cause.Error() != cause.Error() {
+		t.Errorf("expected the cause in the retry event, got %+v", e)
+	}
+}
+
+// TestContextCause_None verifies that attempts whose context was not
+// cancelled, or cancelled without a cause, record none.
+func TestContextCause_None(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	result := retrier.RetryCtx(ctx, noopLogger, func(ctx context.Context) (int, error) {
+		cancel()
+		return 0, ctx.Err()
+	})
+
+	if history := result.History(); len(history) != 1 || history[0].Cause != nil {
+		t.Errorf("expected no cause, got %v", history)
+	}
+}
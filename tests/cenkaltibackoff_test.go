@@ -0,0 +1,92 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/cenkaltibackoff"
+)
+
+// countingBackOff mimics a cenkalti BackOff returning a constant delay a fixed number of times.
+type countingBackOff struct {
+	delay  time.Duration
+	limit  int
+	calls  int
+	resets int
+}
+
+func (b *countingBackOff) NextBackOff() time.Duration {
+	if b.calls >= b.limit {
+		return cenkaltibackoff.Stop
+	}
+	b.calls++
+	return b.delay
+}
+
+func (b *countingBackOff) Reset() {
+	b.calls = 0
+	b.resets++
+}
+
+// TestCenkaltiBackOff_NewBackOff verifies that the adapter yields the retrier
+// delay sequence and stops after MaxAttempts-1 delays.
+func TestCenkaltiBackOff_NewBackOff(t *testing.T) {
+	b := cenkaltibackoff.NewBackOff(
+		retrier.WithMaxAttempts(4),
+		retrier.WithInitialDuration(100*time.Millisecond),
+		retrier.WithMultiplier(2),
+		retrier.WithMaxDuration(300*time.Millisecond),
+	)
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, cenkaltibackoff.Stop}
+	for i, w := range want {
+		if got := b.NextBackOff(); got != w {
+			t.Errorf("NextBackOff() #%d = %v, want %v", i+1, got, w)
+		}
+	}
+
+	b.Reset()
+	if got := b.NextBackOff(); got != 100*time.Millisecond {
+		t.Errorf("NextBackOff() after Reset = %v, want 100ms", got)
+	}
+}
+
+// TestCenkaltiBackOff_SingleAttempt verifies that one attempt means no retries.
+func TestCenkaltiBackOff_SingleAttempt(t *testing.T) {
+	b := cenkaltibackoff.NewBackOff(retrier.WithMaxAttempts(1))
+	if got := b.NextBackOff(); got != cenkaltibackoff.Stop {
+		t.Errorf("NextBackOff() = %v, want Stop", got)
+	}
+}
+
+// TestCenkaltiBackOff_WithBackOff verifies that Retry waits for the BackOff's
+// delays and stops when it returns Stop.
+func TestCenkaltiBackOff_WithBackOff(t *testing.T) {
+	backOff := &countingBackOff{delay: 2 * time.Millisecond, limit: 2}
+	mock := &backoffMockLogger{enabled: true}
+	fn := func() (string, error) { return "", errors.New("transient") }
+
+	result := retrier.Retry(context.Background(), mock, fn,
+		retrier.WithMaxAttempts(10),
+		cenkaltibackoff.WithBackOff(func() *countingBackOff { return backOff }),
+	)
+
+	if result.Attempts() != 3 {
+		t.Errorf("expected 3 attempts, got %d", result.Attempts())
+	}
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrBackoffStopped {
+		t.Fatalf("expected ErrBackoffStopped, got %v", result.Err())
+	}
+	if backOff.resets != 1 {
+		t.Errorf("expected the BackOff to be reset once, got %d", backOff.resets)
+	}
+	for _, call := range mock.logRetryCalls[:2] {
+		if call.backoff != 2*time.Millisecond {
+			t.Errorf("expected backoff 2ms, got %v", call.backoff)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !retrierchaos
+
+package retrier_test
+
+import (
+	"context"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestWithChaos_Disabled verifies that chaos needs the build tag or the env variable.
+func TestWithChaos_Disabled(t *testing.T) {
+	t.Setenv(retrier.ChaosEnv, "")
+
+	opts := append(defaultTestOpts(), retrier.WithChaos(1, nil))
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) { return 1, nil }, opts...)
+	if result.IsFailure() {
+		t.Errorf("expected chaos to be disabled, got %v", result.Err())
+	}
+}
@@ -0,0 +1,41 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestWithChaos verifies that injected failures are retried like real ones and
+// skip the function.
+func TestWithChaos(t *testing.T) {
+	t.Setenv(retrier.ChaosEnv, "true")
+
+	calls := 0
+	injected := errors.New("injected timeout")
+	opts := append(defaultTestOpts(), retrier.WithChaos(1, func() error { return injected }))
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		calls++
+		return 1, nil
+	}, opts...)
+
+	if calls != 0 {
+		t.Errorf("expected the function not to run, got %d calls", calls)
+	}
+	if !errors.Is(result.Err(), injected) || result.Attempts() != 3 {
+		t.Errorf("expected exhausted attempts wrapping the injected error, got %v after %d", result.Err(), result.Attempts())
+	}
+
+	// Without a factory, ErrChaosInjected is used; with prob 0, nothing is injected
+	opts = append(defaultTestOpts(), retrier.WithMaxAttempts(1), retrier.WithChaos(1, nil))
+	result = retrier.Retry(context.Background(), noopLogger, func() (int, error) { return 1, nil }, opts...)
+	if !errors.Is(result.Err(), retrier.ErrChaosInjected) {
+		t.Errorf("expected ErrChaosInjected, got %v", result.Err())
+	}
+	opts = append(defaultTestOpts(), retrier.WithChaos(0, nil))
+	if result := retrier.Retry(context.Background(), noopLogger, func() (int, error) { return 1, nil }, opts...); result.IsFailure() {
+		t.Errorf("expected no injection with probability 0, got %v", result.Err())
+	}
+}
@@ -0,0 +1,160 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// mockCoordinator is an in-memory retrier.Coordinator for testing.
+type mockCoordinator struct {
+	mu         sync.Mutex
+	held       map[string]bool
+	acquireErr error
+	acquires   int
+	releases   int
+}
+
+func newMockCoordinator() *mockCoordinator {
+	return &mockCoordinator{held: make(map[string]bool)}
+}
+
+func (m *mockCoordinator) Acquire(_ context.Context, key string, _ time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.acquires++
+	if m.acquireErr != nil {
+		return false, m.acquireErr
+	}
+	if m.held[key] {
+		return false, nil
+	}
+	m.held[key] = true
+	return true, nil
+}
+
+func (m *mockCoordinator) Release(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.releases++
+	delete(m.held, key)
+	return nil
+}
+
+// TestCoordinator_AcquiresAndReleasesLease verifies that the lease is taken
+// before the first retry and released when Retry returns.
+func TestCoordinator_AcquiresAndReleasesLease(t *testing.T) {
+	coord := newMockCoordinator()
+	callCount := 0
+	fn := func() (string, error) {
+		callCount++
+		if callCount < 3 {
+			return "", errors.New("transient")
+		}
+		return "success", nil
+	}
+
+	opts := append(defaultTestOpts(),
+		retrier.WithMaxAttempts(5),
+		retrier.WithCoordinator(coord, "op", time.Minute),
+	)
+	result := retrier.Retry(context.Background(), noopLogger, fn, opts...)
+
+	if result.IsFailure() {
+		t.Fatalf("expected success, got: %v", result.Err())
+	}
+	if coord.acquires != 1 {
+		t.Errorf("expected 1 acquire, got %d", coord.acquires)
+	}
+	if coord.releases != 1 {
+		t.Errorf("expected 1 release, got %d", coord.releases)
+	}
+	if coord.held["op"] {
+		t.Error("lease should be released after Retry returns")
+	}
+}
+
+// TestCoordinator_NoLeaseOnFirstSuccess verifies that no lease is taken
+// when the first attempt succeeds.
+func TestCoordinator_NoLeaseOnFirstSuccess(t *testing.T) {
+	coord := newMockCoordinator()
+	fn := func() (string, error) { return "success", nil }
+
+	result := retrier.Retry(context.Background(), noopLogger, fn,
+		retrier.WithCoordinator(coord, "op", time.Minute),
+	)
+
+	if result.IsFailure() {
+		t.Fatalf("expected success, got: %v", result.Err())
+	}
+	if coord.acquires != 0 {
+		t.Errorf("expected no acquire, got %d", coord.acquires)
+	}
+}
+
+// TestCoordinator_DeniedWhenLeaseHeld verifies that Retry gives up after the
+// first attempt when another instance holds the lease.
+func TestCoordinator_DeniedWhenLeaseHeld(t *testing.T) {
+	coord := newMockCoordinator()
+	coord.held["op"] = true
+	callCount := 0
+	innerErr := errors.New("transient")
+	fn := func() (string, error) {
+		callCount++
+		return "", innerErr
+	}
+
+	opts := append(defaultTestOpts(),
+		retrier.WithMaxAttempts(5),
+		retrier.WithCoordinator(coord, "op", time.Minute),
+	)
+	result := retrier.Retry(context.Background(), noopLogger, fn, opts...)
+
+	if callCount != 1 {
+		t.Errorf("expected 1 call, got %d", callCount)
+	}
+	if result.Attempts() != 1 {
+		t.Errorf("expected 1 attempt, got %d", result.Attempts())
+	}
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) {
+		t.Fatalf("expected RetryError, got %T", result.Err())
+	}
+	if retryErr.Cause != retrier.ErrCoordinationDenied {
+		t.Errorf("expected cause %q, got %q", retrier.ErrCoordinationDenied, retryErr.Cause)
+	}
+	if !errors.Is(result.Err(), innerErr) {
+		t.Error("expected the attempt error to be wrapped")
+	}
+	if coord.releases != 0 {
+		t.Errorf("expected no release for a lease never acquired, got %d", coord.releases)
+	}
+}
+
+// TestCoordinator_AcquireError verifies that a failing coordinator stops retries.
+func TestCoordinator_AcquireError(t *testing.T) {
+	coord := newMockCoordinator()
+	coord.acquireErr = errors.New("redis down")
+	fn := func() (string, error) { return "", errors.New("transient") }
+
+	opts := append(defaultTestOpts(),
+		retrier.WithMaxAttempts(5),
+		retrier.WithCoordinator(coord, "op", time.Minute),
+	)
+	result := retrier.Retry(context.Background(), noopLogger, fn, opts...)
+
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) {
+		t.Fatalf("expected RetryError, got %T", result.Err())
+	}
+	if retryErr.Cause != retrier.ErrCoordinationDenied {
+		t.Errorf("expected cause %q, got %q", retrier.ErrCoordinationDenied, retryErr.Cause)
+	}
+	if !containsString(retryErr.Message, "redis down") {
+		t.Errorf("expected message to mention acquire error, got %q", retryErr.Message)
+	}
+}
@@ -1,6 +1,7 @@
 package retrier_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -122,27 +123,54 @@ func TestResult_Unwrap(t *testing.T) {
 	})
 }
 
-// TestResult_Unwrap_PanicMessage tests that Unwrap panic contains error message.
-func TestResult_Unwrap_PanicMessage(t *testing.T) {
+// TestResult_Unwrap_PanicValue tests that Unwrap panics with an error wrapping
+// the Result's error.
+func TestResult_Unwrap_PanicValue(t *testing.T) {
+	testErr := errors.New("test error")
 	defer func() {
 		r := recover()
 		if r == nil {
 			t.Fatal("expected panic")
 		}
-		// Panic message should contain the error
-		panicStr, ok := r.(string)
+		panicErr, ok := r.(error)
 		if !ok {
-			t.Fatalf("panic value is not a string: %v", r)
+			t.Fatalf("panic value is not an error: %v", r)
+		}
+		if !errors.Is(panicErr, testErr) {
+			t.Errorf("panic error should wrap the result error, got %v", panicErr)
 		}
-		if panicStr == "" {
-			t.Error("panic message should not be empty")
+		if !containsString(panicErr.Error(), "test error") {
+			t.Errorf("panic message should contain the error, got %q", panicErr.Error())
 		}
 	}()
 
-	result := retrier.NewFailureResult[string](errors.New("test error"), 2)
+	result := retrier.NewFailureResult[string](testErr, 2)
 	result.Unwrap()
 }
 
+// TestResult_Expect tests Expect's value and custom panic message.
+func TestResult_Expect(t *testing.T) {
+	if got := retrier.NewSuccessResult(7, 1).Expect("unused"); got != 7 {
+		t.Errorf("Expect() = %d, want 7", got)
+	}
+
+	retryErr := retrier.NewRetryError(retrier.ErrExhaustedAttempts, "exhausted", retrier.RetryPolicyManual, nil)
+	defer func() {
+		panicErr, ok := recover().(error)
+		if !ok {
+			t.Fatal("expected panic with an error value")
+		}
+		if !containsString(panicErr.Error(), "config must load: ") {
+			t.Errorf("expected custom message prefix, got %q", panicErr.Error())
+		}
+		var got *retrier.RetryError
+		if !errors.As(panicErr, &got) || got != retryErr {
+			t.Errorf("expected the RetryError in the chain, got %v", panicErr)
+		}
+	}()
+	retrier.NewFailureResult[int](retryErr, 3).Expect("config must load")
+}
+
 // TestResult_Types tests that Result works with different generic types.
 func TestResult_Types(t *testing.T) {
 	t.Run("int type", func(t *testing.T) {
@@ -182,3 +210,85 @@ func TestResult_Types(t *testing.T) {
 		}
 	})
 }
+
+// TestResult_Inspect tests that Inspect and InspectErr run only for their outcome
+// and return the Result unchanged.
+func TestResult_Inspect(t *testing.T) {
+	var seenValue string
+	var seenErr error
+	success := retrier.NewSuccessResult("value", 2)
+
+	got := success.
+		Inspect(func(v string) { seenValue = v }).
+		InspectErr(func(err error) { seenErr = err })
+	if seenValue != "value" || seenErr != nil {
+		t.Errorf("expected only Inspect to run, got value %q and error %v", seenValue, seenErr)
+	}
+	if got.Value() != success.Value() || got.Err() != nil || got.Attempts() != success.Attempts() {
+		t.Errorf("expected the Result to be returned unchanged, got %+v", got)
+	}
+
+	seenValue = ""
+	failErr := errors.New("failed")
+	failure := retrier.NewFailureResult[string](failErr, 3)
+
+	got = failure.
+		Inspect(func(v string) { seenValue = v }).
+		InspectErr(func(err error) { seenErr = err })
+	if seenValue != "" || seenErr != failErr {
+		t.Errorf("expected only InspectErr to run, got value %q and error %v", seenValue, seenErr)
+	}
+	if got.Value() != "" || got.Err() != failErr || got.Attempts() != failure.Attempts() {
+		t.Errorf("expected the Result to be returned unchanged, got %+v", got)
+	}
+}
+
+// TestResult_Errors tests that Errors lists the error of every failed attempt in order.
+func TestResult_Errors(t *testing.T) {
+	ctx := context.Background()
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	t.Run("first-try success has no errors", func(t *testing.T) {
+		result := retrier.Retry(ctx, noopLogger, func() (int, error) { return 1, nil }, defaultTestOpts()...)
+		if errs := result.Errors(); len(errs) != 0 {
+			t.Errorf("Errors() = %v, want empty", errs)
+		}
+	})
+
+	t.Run("success after failures keeps earlier errors", func(t *testing.T) {
+		calls := 0
+		result := retrier.Retry(ctx, noopLogger, func() (int, error) {
+			calls++
+			switch calls {
+			case 1:
+				return 0, errA
+			case 2:
+				return 0, errB
+			}
+			return 1, nil
+		}, defaultTestOpts()...)
+		errs := result.Errors()
+		if len(errs) != 2 || errs[0] != errA || errs[1] != errB {
+			t.Errorf("Errors() = %v, want [a b]", errs)
+		}
+	})
+
+	t.Run("exhausted attempts", func(t *testing.T) {
+		result := retrier.Retry(ctx, noopLogger, func() (int, error) { return 0, errA },
+			append(defaultTestOpts(), retrier.WithMaxAttempts(3))...)
+		errs := result.Errors()
+		if len(errs) != 3 {
+			t.Fatalf("Errors() has %d errors, want 3", len(errs))
+		}
+		if joined := errors.Join(errs...); !errors.Is(joined, errA) {
+			t.Errorf("expected joined errors to contain the attempt error, got %v", joined)
+		}
+	})
+
+	t.Run("results not produced by Retry", func(t *testing.T) {
+		if errs := retrier.NewFailureResult[int](errA, 1).Errors(); len(errs) != 0 {
+			t.Errorf("Errors() = %v, want empty", errs)
+		}
+	})
+}
@@ -0,0 +1,293 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/retriertest"
+)
+
+// attemptTimeouts runs 4 failing attempts under a 10s deadline split by split
+// and returns the time each attempt had before its context deadline.
+func attemptTimeouts(t *testing.T, split retrier.DeadlineSplit) []time.Duration {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var timeouts []time.Duration
+	retrier.RetryCtx(ctx, noopLogger, func(ctx context.Context) (int, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("expected the attempt context to have a deadline")
+		}
+		timeouts = append(timeouts, time.Until(deadline))
+		return 0, errors.New("transient")
+	}, append(defaultTestOpts(), retrier.WithMaxAttempts(4), retrier.WithDeadlineSplit(split))...)
+
+	if len(timeouts) != 4 {
+		t.Fatalf("expected 4 attempts, got %d", len(timeouts))
+	}
+	return timeouts
+}
+
+// assertTimeouts checks each timeout against want within a tolerance for the
+// time the attempts and backoffs took.
+func assertTimeouts(t *testing.T, got, want []time.Duration) {
+	t.Helper()
+	for i := range want {
+		if diff := got[i] - want[i]; diff < -200*time.Millisecond || diff > 10*time.Millisecond {
+			t.Errorf("attempt %d: timeout %v, want about %v", i+1, got[i], want[i])
+		}
+	}
+}
+
+// TestWithDeadlineSplit_None verifies that by default every attempt gets the
+// whole remaining time.
+func TestWithDeadlineSplit_None(t *testing.T) {
+	got := attemptTimeouts(t, retrier.SplitNone)
+	assertTimeouts(t, got, []time.Duration{10 * time.Second, 10 * time.Second, 10 * time.Second, 10 * time.Second})
+}
+
+// TestWithDeadlineSplit_Equal verifies that the remaining time is divided
+// equally among the remaining attempts.
+func TestWithDeadlineSplit_Equal(t *testing.T) {
+	got := attemptTimeouts(t, retrier.SplitEqual)
+	// 10s/4, then 10s/3, 10s/2 and all of it, since the attempts fail fast
+	assertTimeouts(t, got, []time.Duration{2500 * time.Millisecond, 3333 * time.Millisecond, 5 * time.Second, 10 * time.Second})
+}
+
+// TestWithDeadlineSplit_Decaying verifies that earlier attempts get larger
+// shares of the remaining time.
+func TestWithDeadlineSplit_Decaying(t *testing.T) {
+	got := attemptTimeouts(t, retrier.SplitDecaying)
+	// 8/15, 4/7, 2/3 of the remaining time, then all of it
+	assertTimeouts(t, got, []time.Duration{5333 * time.Millisecond, 5714 * time.Millisecond, 6666 * time.Millisecond, 10 * time.Second})
+}
+
+// TestWithDeadlineSplit_AttemptTimeout verifies that an attempt exceeding its
+// share is cut short and retried within the overall deadline.
+func TestWithDeadlineSplit_AttemptTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	calls := 0
+
+	result := retrier.RetryCtx(ctx, noopLogger, func(ctx context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			<-ctx.Done() // hangs until its share runs out
+			return "", ctx.Err()
+		}
+		return "ok", nil
+	}, append(defaultTestOpts(), retrier.WithMaxAttempts(2), retrier.WithDeadlineSplit(retrier.SplitEqual))...)
+
+	if !result.IsSuccess() || result.Attempts() != 2 {
+		t.Fatalf("expected success after 2 attempts, got %v after %d", result.Err(), result.Attempts())
+	}
+	if err := ctx.Err(); err != nil {
+		t.Errorf("expected the overall deadline to remain, got %v", err)
+	}
+}
+
+// TestWithMaxAttemptDuration verifies that each attempt context is bounded by
+// the attempt limit, independently of the backoff cap.
+func TestWithMaxAttemptDuration(t *testing.T) {
+	var timeouts []time.Duration
+	result := retrier.RetryCtx(context.Background(), noopLogger, func(ctx context.Context) (int, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("expected the attempt context to have a deadline")
+		}
+		timeouts = append(timeouts, time.Until(deadline))
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}, retrier.WithMaxAttempts(2), retrier.WithInitialDuration(time.Millisecond),
+		retrier.WithMaxDuration(time.Millisecond), retrier.WithMaxAttemptDuration(20*time.Millisecond))
+
+	if !errors.Is(result.Err(), context.DeadlineExceeded) || result.Attempts() != 2 {
+		t.Fatalf("expected 2 attempts timing out, got %d: %v", result.Attempts(), result.Err())
+	}
+	for _, timeout := range timeouts {
+		if timeout > 20*time.Millisecond || timeout < 15*time.Millisecond {
+			t.Errorf("expected attempts bounded at 20ms, got %v", timeouts)
+		}
+	}
+}
+
+// TestWithMaxTotalDuration verifies that the loop stops rather than start a
+// delay leaving no time for another attempt.
+func TestWithMaxTotalDuration(t *testing.T) {
+	transient := errors.New("transient")
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		return 0, transient
+	}, retrier.WithMaxAttempts(10), retrier.WithInitialDuration(10*time.Millisecond),
+		retrier.WithMultiplier(2), retrier.WithMaxTotalDuration(50*time.Millisecond))
+
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrTotalDurationExceeded {
+		t.Fatalf("expected ErrTotalDurationExceeded, got %v", result.Err())
+	}
+	// Delays of 10ms and 20ms fit in 50ms, a third of 40ms does not
+	if !errors.Is(result.Err(), transient) || result.Attempts() != 3 {
+		t.Errorf("expected 3 attempts wrapping the last error, got %d: %v", result.Attempts(), result.Err())
+	}
+}
+
+// TestWithMaxTotalDuration_AttemptContext verifies that the attempt limit is
+// shortened to what is left of the total duration.
+func TestWithMaxTotalDuration_AttemptContext(t *testing.T) {
+	var timeouts []time.Duration
+	retrier.RetryCtx(context.Background(), noopLogger, func(ctx context.Context) (int, error) {
+		deadline, _ := ctx.Deadline()
+		timeouts = append(timeouts, time.Until(deadline))
+		return 0, errors.New("transient")
+	}, retrier.WithMaxAttempts(2), retrier.WithInitialDuration(40*time.Millisecond),
+		retrier.WithMaxAttemptDuration(80*time.Millisecond), retrier.WithMaxTotalDuration(100*time.Millisecond))
+
+	// The second attempt starts after a 40ms delay, with 60ms left
+	if len(timeouts) != 2 || timeouts[1] > 60*time.Millisecond || timeouts[1] < 30*time.Millisecond {
+		t.Errorf("expected the second attempt bounded by what is left of the total, got %v", timeouts)
+	}
+}
+
+// TestWithMaxElapsedTime verifies that the loop gives up once the next
+// attempt would start past the elapsed time allowed, without bounding the
+// attempt context.
+func TestWithMaxElapsedTime(t *testing.T) {
+	transient := errors.New("transient")
+	bounded := false
+	result := retrier.RetryCtx(context.Background(), noopLogger, func(ctx context.Context) (int, error) {
+		if _, ok := ctx.Deadline(); ok {
+			bounded = true
+		}
+		return 0, transient
+	}, retrier.WithMaxAttempts(10), retrier.WithInitialDuration(10*time.Millisecond),
+		retrier.WithMultiplier(2), retrier.WithMaxElapsedTime(50*time.Millisecond))
+
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrElapsedTimeExceeded {
+		t.Fatalf("expected ErrElapsedTimeExceeded, got %v", result.Err())
+	}
+	// Delays of 10ms and 20ms fit in 50ms, a third of 40ms does not
+	if !errors.Is(result.Err(), transient) || result.Attempts() != 3 {
+		t.Errorf("expected 3 attempts wrapping the last error, got %d: %v", result.Attempts(), result.Err())
+	}
+	if retryErr.Usage == nil {
+		t.Error("expected the time usage in the error")
+	}
+	if bounded {
+		t.Error("expected attempts not to be bounded")
+	}
+}
+
+// TestMaxDurations_Invalid verifies that contradicting duration limits fail
+// the call before the first attempt.
+func TestMaxDurations_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []retrier.RetryOption
+	}{
+		{"attempt above total", []retrier.RetryOption{retrier.WithMaxAttemptDuration(2 * time.Second), retrier.WithMaxTotalDuration(time.Second)}},
+		{"negative attempt", []retrier.RetryOption{retrier.WithMaxAttemptDuration(-time.Second)}},
+		{"negative total", []retrier.RetryOption{retrier.WithMaxTotalDuration(-time.Second)}},
+		{"negative initial", []retrier.RetryOption{retrier.WithInitialDuration(-time.Second)}},
+		{"zero max", []retrier.RetryOption{retrier.WithMaxDuration(0)}},
+		{"negative max", []retrier.RetryOption{retrier.WithMaxDuration(-time.Second)}},
+		{"zero multiplier", []retrier.RetryOption{retrier.WithMultiplier(0)}},
+		{"negative multiplier", []retrier.RetryOption{retrier.WithMultiplier(-2)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+				called = true
+				return 0, nil
+			}, tt.opts...)
+
+			var retryErr *retrier.RetryError
+			if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrInvalidDurations {
+				t.Fatalf("expected ErrInvalidDurations, got %v", result.Err())
+			}
+			if called || result.Attempts() != 0 {
+				t.Errorf("expected no attempt, got %d", result.Attempts())
+			}
+		})
+	}
+}
+
+// TestDeadlineUsage verifies that a loop giving up on its context deadline
+// reports how much of it went to attempts and to backoff delays.
+func TestDeadlineUsage(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := retriertest.NewFakeClock(start)
+	ctx, cancel := clock.WithDeadline(context.Background(), start.Add(10*time.Second))
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- retrier.RetryCtx(ctx, noopLogger, func(ctx context.Context) (int, error) {
+			clock.Advance(3 * time.Second) // a slow attempt
+			return 0, errors.New("down")
+		},
+			retrier.WithInitialDuration(5*time.Second),
+			retrier.WithMaxDuration(5*time.Second),
+			retrier.WithHealthCheck(func(ctx context.Context) bool { return false }),
+			retrier.WithClock(clock),
+		).Err()
+	}()
+	clock.BlockUntil(2) // the deadline and the backoff delay
+	clock.Advance(5 * time.Second)
+	clock.BlockUntil(2) // the delay again, as the dependency is still down
+	clock.Advance(2 * time.Second)
+
+	var retryErr *retrier.RetryError
+	if err := <-done; !errors.As(err, &retryErr) || retryErr.Cause != retrier.ErrContextCancelled || retryErr.Usage == nil {
+		t.Fatalf("expected ErrContextCancelled with its usage, got %v", err)
+	}
+	want := retrier.DeadlineUsage{Deadline: start.Add(10 * time.Second), Elapsed: 10 * time.Second, Working: 3 * time.Second, Waiting: 7 * time.Second}
+	if *retryErr.Usage != want {
+		t.Errorf("expected usage %+v, got %+v", want, *retryErr.Usage)
+	}
+	if !strings.Contains(retryErr.Error(), "10s elapsed: 3s working, 7s waiting") {
+		t.Errorf("expected the usage in the message, got %q", retryErr.Error())
+	}
+}
+
+// TestDeadlineWouldExceed verifies that a loop gives up at once, rather than
+// sleep until its context deadline, when the backoff delay would end past it.
+func TestDeadlineWouldExceed(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := retriertest.NewFakeClock(start)
+	ctx, cancel := clock.WithDeadline(context.Background(), start.Add(10*time.Second))
+	defer cancel()
+
+	result := retrier.RetryCtx(ctx, noopLogger, func(ctx context.Context) (int, error) {
+		clock.Advance(3 * time.Second)
+		return 0, errors.New("down")
+	},
+		retrier.WithInitialDuration(time.Minute),
+		retrier.WithMaxDuration(time.Minute),
+		retrier.WithClock(clock),
+		retrier.WithExplain(),
+	)
+
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrDeadlineWouldExceed {
+		t.Fatalf("expected ErrDeadlineWouldExceed, got %v", result.Err())
+	}
+	if result.Attempts() != 1 || clock.Now() != start.Add(3*time.Second) {
+		t.Errorf("expected to give up after 1 attempt without sleeping, got %d attempts at %v", result.Attempts(), clock.Now().Sub(start))
+	}
+	if retryErr.Usage == nil || retryErr.Usage.Waiting != 0 {
+		t.Errorf("expected usage without waiting, got %v", retryErr.Usage)
+	}
+	if ctx.Err() != nil {
+		t.Errorf("expected the context to still be live, got %v", ctx.Err())
+	}
+	if d := result.Decisions(); len(d) != 1 || d[0].Rule != retrier.RuleDeadline || d[0].Retry {
+		t.Errorf("expected a deadline decision, got %+v", d)
+	}
+}
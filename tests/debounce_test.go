@@ -0,0 +1,175 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestDebouncer_CoalescesBurst tests that a burst of triggers runs the
+// operation once, with the fn of the last trigger.
+func TestDebouncer_CoalescesBurst(t *testing.T) {
+	done := make(chan string, 10)
+	d := retrier.NewDebouncer(context.Background(), noopLogger, 20*time.Millisecond,
+		func(key string, err error) { done <- key }, defaultTestOpts()...)
+	defer d.Close()
+
+	var runs atomic.Int32
+	var last atomic.Int32
+	for i := 1; i <= 5; i++ {
+		d.Trigger("reload", func(ctx context.Context) error {
+			runs.Add(1)
+			last.Store(int32(i))
+			return nil
+		})
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("operation did not run")
+	}
+	time.Sleep(40 * time.Millisecond)
+	if runs.Load() != 1 {
+		t.Errorf("expected 1 run, got %d", runs.Load())
+	}
+	if last.Load() != 5 {
+		t.Errorf("expected the last trigger's fn to run, got trigger %d", last.Load())
+	}
+}
+
+// TestDebouncer_RetriesAndReports tests that executions are retried and their
+// outcome is reported per key.
+func TestDebouncer_RetriesAndReports(t *testing.T) {
+	var mu sync.Mutex
+	outcomes := make(map[string]error)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	d := retrier.NewDebouncer(context.Background(), noopLogger, 5*time.Millisecond,
+		func(key string, err error) {
+			mu.Lock()
+			outcomes[key] = err
+			mu.Unlock()
+			wg.Done()
+		}, append(defaultTestOpts(), retrier.WithMaxAttempts(3))...)
+	defer d.Close()
+
+	calls := 0
+	d.Trigger("flaky", func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+	down := errors.New("down")
+	d.Trigger("broken", func(ctx context.Context) error { return down })
+	wg.Wait()
+
+	if outcomes["flaky"] != nil {
+		t.Errorf("flaky outcome = %v, want nil", outcomes["flaky"])
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+	if !errors.Is(outcomes["broken"], down) {
+		t.Errorf("broken outcome = %v, want %v", outcomes["broken"], down)
+	}
+}
+
+// TestDebouncer_TriggerDuringRun tests that a trigger arriving while the
+// operation runs schedules exactly one more execution, without overlap.
+func TestDebouncer_TriggerDuringRun(t *testing.T) {
+	done := make(chan struct{}, 10)
+	d := retrier.NewDebouncer(context.Background(), noopLogger, 5*time.Millisecond,
+		func(string, error) { done <- struct{}{} }, defaultTestOpts()...)
+	defer d.Close()
+
+	var running, overlaps, runs atomic.Int32
+	started := make(chan struct{}, 10)
+	fn := func(ctx context.Context) error {
+		if running.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		runs.Add(1)
+		started <- struct{}{}
+		time.Sleep(30 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}
+
+	d.Trigger("k", fn)
+	<-started
+	d.Trigger("k", fn)
+	d.Trigger("k", fn)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("expected 2 executions, got %d", i)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	if runs.Load() != 2 {
+		t.Errorf("expected 2 runs, got %d", runs.Load())
+	}
+	if overlaps.Load() != 0 {
+		t.Error("expected executions not to overlap")
+	}
+}
+
+// TestDebouncer_Close tests that Close cancels pending executions.
+func TestDebouncer_Close(t *testing.T) {
+	var runs atomic.Int32
+	d := retrier.NewDebouncer(context.Background(), noopLogger, 20*time.Millisecond, nil, defaultTestOpts()...)
+	d.Trigger("k", func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	d.Close()
+	d.Trigger("k", func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+
+	time.Sleep(40 * time.Millisecond)
+	if runs.Load() != 0 {
+		t.Errorf("expected no runs after Close, got %d", runs.Load())
+	}
+}
+
+// TestDebouncer_AttemptContext tests that the operation receives the attempt context, so
+// per-attempt deadlines cut a hanging attempt short.
+func TestDebouncer_AttemptContext(t *testing.T) {
+	done := make(chan error, 1)
+	d := retrier.NewDebouncer(context.Background(), noopLogger, time.Millisecond,
+		func(key string, err error) { done <- err },
+		append(defaultTestOpts(), retrier.WithMaxAttempts(2), retrier.WithMaxAttemptDuration(10*time.Millisecond))...)
+	defer d.Close()
+
+	var attempts atomic.Int32
+	var cutShort atomic.Bool
+	d.Trigger("reload", func(ctx context.Context) error {
+		if attempts.Add(1) > 1 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			cutShort.Store(true)
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return errors.New("hung")
+		}
+	})
+
+	if err := <-done; err != nil || !cutShort.Load() {
+		t.Errorf("expected the first attempt cut short and retried, got %v after %d attempts", err, attempts.Load())
+	}
+}
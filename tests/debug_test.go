@@ -0,0 +1,76 @@
+package retrier_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestRegistry_ServeHTTP verifies the JSON rendered for registered Retriers.
+func TestRegistry_ServeHTTP(t *testing.T) {
+	budget := retrier.NewRetryBudget(0.5)
+	guard := retrier.NewRetryGuard(8)
+	payments := retrier.NewRetrier(noopLogger,
+		retrier.WithMaxAttempts(4),
+		retrier.WithInitialDuration(250*time.Millisecond),
+		retrier.WithBudget(budget),
+		retrier.WithRetryGuard(guard),
+	)
+	retrier.Do(context.Background(), payments, func() (string, error) { return "ok", nil })
+
+	registry := retrier.NewRegistry()
+	registry.Register("payments", payments)
+	registry.Register("search", retrier.NewRetrier(noopLogger))
+	registry.Register("gone", retrier.NewRetrier(noopLogger))
+	registry.Unregister("gone")
+
+	rec := httptest.NewRecorder()
+	registry.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/retrier", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+	var body struct {
+		Retriers map[string]struct {
+			Options struct {
+				MaxAttempts     int    `json:"maxAttempts"`
+				InitialDuration string `json:"initialDuration"`
+				RetryPolicy     string `json:"retryPolicy"`
+			} `json:"options"`
+			Stats  retrier.RetrierStats `json:"stats"`
+			Budget *retrier.BudgetStats `json:"budget"`
+			Guard  *struct {
+				Active int `json:"active"`
+				Max    int `json:"max"`
+			} `json:"guard"`
+		} `json:"retriers"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if len(body.Retriers) != 2 {
+		t.Fatalf("expected 2 retriers, got %d", len(body.Retriers))
+	}
+	p := body.Retriers["payments"]
+	if p.Options.MaxAttempts != 4 || p.Options.InitialDuration != "250ms" || p.Options.RetryPolicy != "auto" {
+		t.Errorf("unexpected options %+v", p.Options)
+	}
+	if p.Stats.Calls != 1 || p.Stats.Successes != 1 {
+		t.Errorf("unexpected stats %+v", p.Stats)
+	}
+	if p.Budget == nil || p.Budget.Requests != 1 {
+		t.Errorf("expected budget stats with 1 request, got %+v", p.Budget)
+	}
+	if p.Guard == nil || p.Guard.Max != 8 {
+		t.Errorf("expected guard state with max 8, got %+v", p.Guard)
+	}
+	if s := body.Retriers["search"]; s.Budget != nil || s.Guard != nil {
+		t.Errorf("expected no budget or guard for search, got %+v", s)
+	}
+}
@@ -0,0 +1,72 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestRetryEach verifies that entries are retried independently and their
+// outcomes keyed like the items.
+func TestRetryEach(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	items := map[string]int{"ok": 1, "flaky": 2, "broken": 3}
+	broken := errors.New("broken")
+
+	errs := retrier.RetryEach(context.Background(), noopLogger, 0, items, func(ctx context.Context, key string, value int) error {
+		mu.Lock()
+		calls[key]++
+		n := calls[key]
+		mu.Unlock()
+		switch {
+		case key == "broken":
+			return retrier.Permanent(broken)
+		case key == "flaky" && n < 2:
+			return errors.New("transient")
+		}
+		return nil
+	}, defaultTestOpts()...)
+
+	if len(errs) != len(items) || errs["ok"] != nil || errs["flaky"] != nil || !errors.Is(errs["broken"], broken) {
+		t.Errorf("unexpected outcomes %v", errs)
+	}
+	if calls["ok"] != 1 || calls["flaky"] != 2 || calls["broken"] != 1 {
+		t.Errorf("unexpected calls %v", calls)
+	}
+}
+
+// TestRetryEachSlice_Limit verifies that at most limit elements run at the
+// same time, and outcomes follow the order of the elements.
+func TestRetryEachSlice_Limit(t *testing.T) {
+	var running, peak atomic.Int32
+	items := []int{0, 1, 2, 3, 4, 5, 6, 7}
+
+	errs := retrier.RetryEachSlice(context.Background(), noopLogger, 2, items, func(ctx context.Context, i, value int) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		if value%2 == 1 {
+			return retrier.Permanent(errors.New("odd"))
+		}
+		return nil
+	})
+
+	if peak.Load() > 2 {
+		t.Errorf("expected at most 2 elements at once, got %d", peak.Load())
+	}
+	for i, err := range errs {
+		if (err != nil) != (i%2 == 1) {
+			t.Errorf("unexpected outcome %v for element %d", err, i)
+		}
+	}
+}
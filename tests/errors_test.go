@@ -1,8 +1,11 @@
 package retrier_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	retrier "github.com/rohmanhakim/retrier"
 )
@@ -266,3 +269,193 @@ func containsString(s, substr string) bool {
 	}
 	return false
 }
+
+// TestRetryError_Groups tests that the attempt errors are grouped by
+// fingerprint in the final RetryError.
+func TestRetryError_Groups(t *testing.T) {
+	callCount := 0
+	fn := func() (string, error) {
+		callCount++
+		if callCount == 3 {
+			return "", errors.New("timeout")
+		}
+		return "", fmt.Errorf("dial tcp 10.0.0.%d:5432: connection refused", callCount)
+	}
+
+	opts := append(defaultTestOpts(), retrier.WithMaxAttempts(5), retrier.WithInitialDuration(time.Millisecond))
+	result := retrier.Retry(context.Background(), noopLogger, fn, opts...)
+
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) {
+		t.Fatalf("expected RetryError, got %T", result.Err())
+	}
+	groups := retryErr.Groups()
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %+v", groups)
+	}
+	if groups[0].Fingerprint != "dial tcp <addr>: connection refused" || groups[0].Count != 4 {
+		t.Errorf("unexpected first group %+v", groups[0])
+	}
+	if groups[0].Err.Error() != "dial tcp 10.0.0.5:5432: connection refused" {
+		t.Errorf("expected the most recent error in the group, got %v", groups[0].Err)
+	}
+	if groups[1].Fingerprint != "timeout" || groups[1].Count != 1 {
+		t.Errorf("unexpected second group %+v", groups[1])
+	}
+	if want := "[dial tcp <addr>: connection refused ×4, timeout ×1]"; !containsString(retryErr.Error(), want) {
+		t.Errorf("Error() = %q, want summary %q", retryErr.Error(), want)
+	}
+}
+
+// TestRetryError_NoSummaryForSingleFailure tests that a single failed attempt
+// adds no summary to the message.
+func TestRetryError_NoSummaryForSingleFailure(t *testing.T) {
+	result := retrier.Retry(context.Background(), noopLogger,
+		func() (string, error) { return "", errors.New("boom") },
+		retrier.WithMaxAttempts(1),
+	)
+
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) {
+		t.Fatalf("expected RetryError, got %T", result.Err())
+	}
+	if len(retryErr.Groups()) != 1 || containsString(retryErr.Error(), "×") {
+		t.Errorf("expected one group and no summary, got %+v in %q", retryErr.Groups(), retryErr.Error())
+	}
+}
+
+// TestRetryError_History tests the attempt history and the attempt annotation.
+func TestRetryError_History(t *testing.T) {
+	callCount := 0
+	fn := func() (string, error) {
+		callCount++
+		return "", fmt.Errorf("failure %d", callCount)
+	}
+
+	before := time.Now()
+	opts := append(defaultTestOpts(), retrier.WithMaxAttempts(3), retrier.WithInitialDuration(time.Millisecond))
+	result := retrier.Retry(context.Background(), noopLogger, fn, opts...)
+
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) {
+		t.Fatalf("expected RetryError, got %T", result.Err())
+	}
+	history := retryErr.History()
+	if len(history) != 3 {
+		t.Fatalf("expected 3 history entries, got %d", len(history))
+	}
+	for i, entry := range history {
+		if entry.Attempt != i+1 || entry.Err.Error() != fmt.Sprintf("failure %d", i+1) {
+			t.Errorf("unexpected history entry %d: %+v", i, entry)
+		}
+		if entry.Time.Before(before) || (i > 0 && entry.Time.Before(history[i-1].Time)) {
+			t.Errorf("expected increasing timestamps, got %v", entry.Time)
+		}
+	}
+	if history[1].Error() != "attempt 2: failure 2" {
+		t.Errorf("AttemptError.Error() = %q", history[1].Error())
+	}
+	if retryErr.Attempt() != 3 || !containsString(retryErr.Error(), "failure 3 (attempt 3)") {
+		t.Errorf("expected attempt 3 annotation, got %d in %q", retryErr.Attempt(), retryErr.Error())
+	}
+}
+
+// TestRetryError_AttemptForContextError tests that a context error is not
+// attributed to an attempt.
+func TestRetryError_AttemptForContextError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fn := func() (string, error) {
+		cancel()
+		return "", errors.New("transient")
+	}
+
+	result := retrier.Retry(ctx, noopLogger, fn, retrier.WithMaxAttempts(3))
+
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) {
+		t.Fatalf("expected RetryError, got %T", result.Err())
+	}
+	if retryErr.Attempt() != 0 || len(retryErr.History()) != 1 {
+		t.Errorf("expected no attempt and 1 history entry, got %d and %d", retryErr.Attempt(), len(retryErr.History()))
+	}
+}
+
+// TestRetryError_WithErrorFormatter tests that a formatter replaces the message
+// of the RetryError without affecting its error chain.
+func TestRetryError_WithErrorFormatter(t *testing.T) {
+	secret := errors.New("token=abc123 rejected")
+	opts := append(defaultTestOpts(),
+		retrier.WithMaxAttempts(2),
+		retrier.WithErrorFormatter(func(e *retrier.RetryError) string {
+			return fmt.Sprintf("retry failed: %s after attempt %d", e.Cause, e.Attempt())
+		}),
+	)
+
+	err := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		return 0, secret
+	}, opts...).Err()
+
+	if got, want := err.Error(), "retry failed: exhausted attempt after attempt 2"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, secret) {
+		t.Error("expected the wrapped error to remain in the chain")
+	}
+	var retryErr *retrier.RetryError
+	if !errors.As(err, &retryErr) || len(retryErr.History()) != 2 {
+		t.Errorf("expected a RetryError with 2 attempts of history, got %v", err)
+	}
+}
+
+// TestPermanent tests that Permanent errors are returned without retrying.
+func TestPermanent(t *testing.T) {
+	if retrier.Permanent(nil) != nil {
+		t.Error("Permanent(nil) should be nil")
+	}
+
+	notFound := errors.New("not found")
+	calls := 0
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		calls++
+		return 0, retrier.Permanent(notFound)
+	}, defaultTestOpts()...)
+
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+	if !errors.Is(result.Err(), notFound) {
+		t.Errorf("expected the wrapped error in the chain, got %v", result.Err())
+	}
+	if result.Err().Error() != "not found" {
+		t.Errorf("Error() = %q, want %q", result.Err().Error(), "not found")
+	}
+}
+
+// TestIsTransient_IsPermanent tests the classification of errors.
+func TestIsTransient_IsPermanent(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantTransient bool
+		wantPermanent bool
+	}{
+		{"nil", nil, false, false},
+		{"standard error", errors.New("timeout"), true, false},
+		{"permanent wrapper", retrier.Permanent(errors.New("bad request")), false, true},
+		{"wrapped permanent", fmt.Errorf("fetch: %w", retrier.Permanent(errors.New("gone"))), false, true},
+		{"retryable auto", &mockErrorWithDelay{msg: "busy", retryable: true}, true, false},
+		{"exhausted retry error", retrier.NewRetryError(retrier.ErrExhaustedAttempts, "exhausted", retrier.RetryPolicyManual, nil), false, false},
+		{"cancelled retry error", retrier.NewRetryError(retrier.ErrContextCancelled, "cancelled", retrier.RetryPolicyNever, nil), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retrier.IsTransient(tt.err); got != tt.wantTransient {
+				t.Errorf("IsTransient() = %v, want %v", got, tt.wantTransient)
+			}
+			if got := retrier.IsPermanent(tt.err); got != tt.wantPermanent {
+				t.Errorf("IsPermanent() = %v, want %v", got, tt.wantPermanent)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/execretry"
+)
+
+// TestExecRetry_Run verifies that a failing command is rerun until it succeeds.
+func TestExecRetry_Run(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	marker := filepath.Join(t.TempDir(), "count")
+	// Fails until it has run three times
+	script := `echo x >> "$1"; [ "$(wc -l < "$1")" -ge 3 ]`
+
+	result := execretry.Run(context.Background(), noopLogger,
+		execretry.Command("sh", "-c", script, "sh", marker),
+		append(defaultTestOpts(), retrier.WithMaxAttempts(5))...,
+	)
+	if result.IsFailure() || result.Attempts() != 3 {
+		t.Errorf("expected success on attempt 3, got %v after %d", result.Err(), result.Attempts())
+	}
+}
+
+// TestExecRetry_ExitCode verifies exit status extraction and permanent statuses.
+func TestExecRetry_ExitCode(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	opts := append(defaultTestOpts(), retrier.WithRetryIf(execretry.RetryIfExitCode(64)))
+
+	result := execretry.Run(context.Background(), noopLogger, execretry.Command("sh", "-c", "exit 64"), opts...)
+	if code, ok := execretry.ExitCode(result.Err()); !ok || code != 64 || result.Attempts() != 1 {
+		t.Errorf("expected status 64 after 1 attempt, got %d, %v after %d", code, ok, result.Attempts())
+	}
+
+	result = execretry.Run(context.Background(), noopLogger, execretry.Command("sh", "-c", "exit 3"), opts...)
+	if code, ok := execretry.ExitCode(result.Err()); !ok || code != 3 || result.Attempts() != 3 {
+		t.Errorf("expected status 3 after 3 attempts, got %d, %v after %d", code, ok, result.Attempts())
+	}
+
+	if _, ok := execretry.ExitCode(errors.New("boom")); ok {
+		t.Error("expected no exit status for a plain error")
+	}
+}
+
+// TestExecRetry_MissingCommand verifies that a missing command is not retried.
+func TestExecRetry_MissingCommand(t *testing.T) {
+	result := execretry.Run(context.Background(), noopLogger,
+		execretry.Command(filepath.Join(t.TempDir(), "missing")), defaultTestOpts()...)
+	if result.Attempts() != 1 || !retrier.IsPermanent(result.Err()) {
+		t.Errorf("expected a permanent failure after 1 attempt, got %v after %d", result.Err(), result.Attempts())
+	}
+}
@@ -0,0 +1,146 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestExperiment_RecordsArms verifies that outcomes are recorded per arm.
+func TestExperiment_RecordsArms(t *testing.T) {
+	control := retrier.NewRetrier(noopLogger, retrier.WithMaxAttempts(1))
+	treatment := retrier.NewRetrier(noopLogger, retrier.WithMaxAttempts(3), retrier.WithInitialDuration(5*time.Millisecond))
+
+	// Fails on every first attempt, succeeds on the second
+	newFn := func() func() (string, error) {
+		callCount := 0
+		return func() (string, error) {
+			callCount++
+			if callCount == 1 {
+				return "", errors.New("transient")
+			}
+			return "ok", nil
+		}
+	}
+
+	all := retrier.NewExperiment(control, treatment, 100)
+	none := retrier.NewExperiment(control, treatment, 0)
+	for i := 0; i < 3; i++ {
+		retrier.RunExperiment(context.Background(), all, "", newFn())
+		retrier.RunExperiment(context.Background(), none, "", newFn())
+	}
+
+	stats := all.Stats()
+	if stats.Control.Calls != 0 || stats.Treatment.Calls != 3 {
+		t.Fatalf("expected all calls in treatment, got %+v", stats)
+	}
+	if stats.Treatment.SuccessRate() != 1 || stats.Treatment.MeanAttempts() != 2 {
+		t.Errorf("unexpected treatment outcomes %+v", stats.Treatment)
+	}
+	if stats.Treatment.MeanAddedLatency() < 5*time.Millisecond {
+		t.Errorf("expected at least 5ms added latency, got %v", stats.Treatment.MeanAddedLatency())
+	}
+
+	stats = none.Stats()
+	if stats.Treatment.Calls != 0 || stats.Control.Calls != 3 {
+		t.Fatalf("expected all calls in control, got %+v", stats)
+	}
+	if stats.Control.SuccessRate() != 0 || stats.Control.MeanAttempts() != 1 {
+		t.Errorf("unexpected control outcomes %+v", stats.Control)
+	}
+}
+
+// TestExperiment_KeyedSplit verifies that keys are assigned consistently and
+// roughly in proportion.
+func TestExperiment_KeyedSplit(t *testing.T) {
+	control := retrier.NewRetrier(noopLogger)
+	treatment := retrier.NewRetrier(noopLogger)
+	exp := retrier.NewExperiment(control, treatment, 30)
+	fn := func() (string, error) { return "ok", nil }
+
+	for i := 0; i < 1000; i++ {
+		retrier.RunExperiment(context.Background(), exp, fmt.Sprintf("user-%d", i), fn)
+	}
+	treated := exp.Stats().Treatment.Calls
+	if treated < 230 || treated > 370 {
+		t.Errorf("expected about 300 treated keys, got %d", treated)
+	}
+
+	before := treatment.Stats().Calls
+	for i := 0; i < 10; i++ {
+		retrier.RunExperiment(context.Background(), exp, "user-1", fn)
+	}
+	if delta := treatment.Stats().Calls - before; delta != 0 && delta != 10 {
+		t.Errorf("expected a key to always land in the same arm, got %d of 10 in treatment", delta)
+	}
+}
+
+// TestRunExperiment_ConcurrentOptions verifies that concurrent calls with
+// their own options do not share the options slice of the arm, even when it
+// has spare capacity.
+func TestRunExperiment_ConcurrentOptions(t *testing.T) {
+	opts := make([]retrier.RetryOption, 1, 8)
+	opts[0] = retrier.WithInitialDuration(time.Microsecond)
+	control := retrier.NewRetrier(noopLogger, opts...)
+	exp := retrier.NewExperiment(control, retrier.NewRetrier(noopLogger), 0)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i := range 40 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			want := i%3 + 1
+			result := retrier.RunExperiment(context.Background(), exp, "", func() (int, error) {
+				return 0, errors.New("down")
+			}, retrier.WithMaxAttempts(want))
+			if result.Attempts() != want {
+				errs <- fmt.Errorf("expected %d attempts, got %d", want, result.Attempts())
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// TestRunExperiment_ResolvesOptionsOnce verifies that a call resolves the options of its
+// arm once, so stateful backoff strategies and providers see one call per call.
+func TestRunExperiment_ResolvesOptionsOnce(t *testing.T) {
+	var strategies atomic.Int32
+	control := retrier.NewRetrier(noopLogger, retrier.WithBackoff(func() retrier.BackoffStrategy {
+		strategies.Add(1)
+		return &fixedStrategy{delays: []time.Duration{time.Microsecond}}
+	}))
+	exp := retrier.NewExperiment(control, retrier.NewRetrier(noopLogger), 0)
+
+	retrier.RunExperiment(context.Background(), exp, "", func() (int, error) { return 1, nil })
+	if n := strategies.Load(); n != 1 {
+		t.Errorf("expected 1 backoff strategy per call, got %d", n)
+	}
+
+	registry := retrier.NewRegistry()
+	registry.Register("control", control)
+	registry.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/debug/retrier", nil))
+	if n := strategies.Load(); n != 1 {
+		t.Errorf("expected the debug handler not to create backoff strategies, got %d", n-1)
+	}
+}
+
+// TestArmStats_Empty verifies that derived stats are zero without calls.
+func TestArmStats_Empty(t *testing.T) {
+	var s retrier.ArmStats
+	if s.SuccessRate() != 0 || s.MeanAttempts() != 0 || s.MeanAddedLatency() != 0 {
+		t.Errorf("expected zero derived stats, got %v %v %v", s.SuccessRate(), s.MeanAttempts(), s.MeanAddedLatency())
+	}
+}
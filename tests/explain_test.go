@@ -0,0 +1,101 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestWithExplain_Exhausted verifies the decisions of a loop retrying a
+// standard error until its attempts run out.
+func TestWithExplain_Exhausted(t *testing.T) {
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		return 0, errors.New("transient")
+	}, append(defaultTestOpts(), retrier.WithExplain())...)
+
+	decisions := result.Decisions()
+	if len(decisions) != 3 {
+		t.Fatalf("expected 3 decisions, got %v", decisions)
+	}
+	for i, d := range decisions[:2] {
+		if !d.Retry || d.Attempt != i+1 || d.Rule != retrier.RuleDefaultPolicy || d.DelaySource != "backoff" || d.Delay <= 0 {
+			t.Errorf("decision %d: unexpected %+v", i, d)
+		}
+	}
+	if last := decisions[2]; last.Retry || last.Attempt != 3 || last.Rule != retrier.RuleMaxAttempts {
+		t.Errorf("unexpected final decision %+v", last)
+	}
+}
+
+// TestWithExplain_StopRules verifies the rule reported for loops stopped in
+// different ways.
+func TestWithExplain_StopRules(t *testing.T) {
+	budget := retrier.NewRetryBudget(0, retrier.WithMinRetries(0))
+	tests := []struct {
+		name   string
+		err    error
+		opts   []retrier.RetryOption
+		rule   retrier.DecisionRule
+		reason string
+	}{
+		{"permanent", retrier.Permanent(errors.New("bad")), nil, retrier.RuleErrorPolicy, "policy never"},
+		{"default policy", errors.New("bad"), []retrier.RetryOption{retrier.WithRetryPolicy(retrier.RetryPolicyManual)}, retrier.RuleDefaultPolicy, "default policy manual"},
+		{"retry if", errors.New("bad"), []retrier.RetryOption{retrier.WithRetryIf(func(error) bool { return false })}, retrier.RuleRetryIf, "returned false"},
+		{"disabled", errors.New("transient"), []retrier.RetryOption{retrier.WithEnabledFunc(func(context.Context) bool { return false })}, retrier.RuleDisabled, "switch"},
+		{"budget", errors.New("transient"), []retrier.RetryOption{retrier.WithBudget(budget)}, retrier.RuleBudget, "retry budget exhausted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+				return 0, tt.err
+			}, append(append(defaultTestOpts(), tt.opts...), retrier.WithExplain())...)
+
+			decisions := result.Decisions()
+			if len(decisions) != 1 {
+				t.Fatalf("expected 1 decision, got %v", decisions)
+			}
+			d := decisions[0]
+			if d.Retry || d.Attempt != 1 || d.Rule != tt.rule || !strings.Contains(d.Reason, tt.reason) {
+				t.Errorf("got %v, want a stop by %s mentioning %q", d, tt.rule, tt.reason)
+			}
+		})
+	}
+}
+
+// TestWithExplain_Events verifies that retry and failure events carry their
+// decision.
+func TestWithExplain_Events(t *testing.T) {
+	events := make(chan retrier.RetryEvent, 10)
+	retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		return 0, &mockErrorWithDelay{msg: "slow down", retryable: true, suggestedDelay: 5 * time.Millisecond}
+	}, append(defaultTestOpts(), retrier.WithMaxAttempts(2), retrier.WithExplain(), retrier.WithNotifyChannel(events))...)
+	close(events)
+
+	var got []retrier.RetryEvent
+	for e := range events {
+		got = append(got, e)
+	}
+	if len(got) != 2 || got[0].Decision == nil || got[1].Decision == nil {
+		t.Fatalf("expected 2 events with decisions, got %+v", got)
+	}
+	if d := got[0].Decision; !d.Retry || d.Rule != retrier.RuleErrorPolicy || d.DelaySource != "server" {
+		t.Errorf("unexpected retry decision %v", d)
+	}
+	if d := got[1].Decision; d.Retry || d.Rule != retrier.RuleMaxAttempts {
+		t.Errorf("unexpected failure decision %v", d)
+	}
+}
+
+// TestWithExplain_Off verifies that no decisions are recorded by default.
+func TestWithExplain_Off(t *testing.T) {
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		return 0, errors.New("transient")
+	}, defaultTestOpts()...)
+	if result.Decisions() != nil {
+		t.Errorf("expected no decisions, got %v", result.Decisions())
+	}
+}
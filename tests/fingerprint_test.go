@@ -0,0 +1,57 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestDefaultFingerprint tests the collapsing of dynamic message parts.
+func TestDefaultFingerprint(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{errors.New("dial tcp 10.0.0.7:5432: connect: connection refused"), "dial tcp <addr>: connect: connection refused"},
+		{errors.New("dial tcp [2001:db8::1]:443: i/o timeout"), "dial tcp <addr>: i/o timeout"},
+		{errors.New("order 5f1c2a9e-8b7d-4c3e-9a6f-0d2b4e8c1a7f not found"), "order <uuid> not found"},
+		{errors.New("user 12345 exceeded quota after 3 requests"), "user <n> exceeded quota after <n> requests"},
+		{errors.New("trace 0x7ffee4b2 span 9f86d081884c7d65 failed"), "trace <hex> span <hex> failed"},
+		{errors.New(`open "/tmp/job-42.lock": permission denied`), `open "<q>": permission denied`},
+		{errors.New("bad gateway"), "bad gateway"},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := retrier.DefaultFingerprint(tt.err); got != tt.want {
+			t.Errorf("DefaultFingerprint(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+// TestRetrier_ErrorCounts verifies that failures are counted by fingerprint.
+func TestRetrier_ErrorCounts(t *testing.T) {
+	r := retrier.NewRetrier(noopLogger, append(defaultTestOpts(), retrier.WithMaxAttempts(1))...)
+	for i := 0; i < 3; i++ {
+		retrier.Do(context.Background(), r, func() (string, error) {
+			return "", fmt.Errorf("user %d: timeout", i)
+		})
+	}
+
+	custom := retrier.NewRetrier(noopLogger,
+		retrier.WithMaxAttempts(2),
+		retrier.WithInitialDuration(0),
+		retrier.WithFingerprinter(func(err error) string { return "custom" }),
+	)
+	retrier.Do(context.Background(), custom, func() (string, error) { return "", errors.New("x") })
+
+	if got := r.ErrorCounts(); len(got) != 1 || got["user <n>: timeout"] != 3 {
+		t.Errorf("ErrorCounts() = %v, want 3 under one fingerprint", got)
+	}
+	if got := custom.ErrorCounts(); got["custom"] != 1 {
+		t.Errorf("ErrorCounts() = %v, want 1 under the custom fingerprint", got)
+	}
+}
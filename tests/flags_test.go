@@ -0,0 +1,56 @@
+package retrier_test
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestBindFlags verifies that parsed flags fill in the returned Options.
+func TestBindFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	opts := retrier.BindFlags(fs, "retry")
+
+	err := fs.Parse([]string{"-retry-max-attempts=7", "-retry-initial=250ms", "-retry-multiplier=1.5", "-retry-max=10s", "-retry-jitter=20ms"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := retrier.Options{
+		MaxAttempts:     7,
+		InitialDuration: 250 * time.Millisecond,
+		Multiplier:      1.5,
+		MaxDuration:     10 * time.Second,
+		Jitter:          20 * time.Millisecond,
+		RetryPolicy:     retrier.RetryPolicyAuto,
+	}
+	if *opts != want {
+		t.Errorf("BindFlags() = %+v, want %+v", *opts, want)
+	}
+	if got := retrier.ResolveOptions(opts.RetryOptions()...); got != want {
+		t.Errorf("RetryOptions() resolved to %+v, want %+v", got, want)
+	}
+}
+
+// TestBindFlags_Defaults verifies the flag defaults and the empty prefix.
+func TestBindFlags_Defaults(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	opts := retrier.BindFlags(fs, "", retrier.WithMaxAttempts(5))
+
+	if err := fs.Parse(nil); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if want := retrier.ResolveOptions(retrier.WithMaxAttempts(5)); *opts != want {
+		t.Errorf("BindFlags() = %+v, want %+v", *opts, want)
+	}
+	if fs.Lookup("max-attempts") == nil || fs.Lookup("jitter") == nil {
+		t.Error("expected flags registered without a prefix")
+	}
+	if err := fs.Parse([]string{"-initial=soon"}); err == nil {
+		t.Error("expected an error for a malformed duration")
+	}
+}
@@ -0,0 +1,88 @@
+package retrier_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestDescribePolicy verifies the canonical description of a policy and its
+// schedule.
+func TestDescribePolicy(t *testing.T) {
+	got := retrier.DescribePolicy(1,
+		retrier.WithMaxAttempts(4),
+		retrier.WithInitialDuration(100*time.Millisecond),
+		retrier.WithMaxDuration(300*time.Millisecond),
+		retrier.WithFastRetries(1, 10*time.Millisecond),
+		retrier.WithRetryGuard(retrier.NewRetryGuard(8)),
+		retrier.WithRetryIf(func(error) bool { return true }),
+	)
+
+	for _, want := range []string{
+		"  max_attempts: 4\n",
+		"  fast_retries: 1 at 10ms\n",
+		"  retry_if: set\n",
+		"  retry_guard: max 8\n",
+		"  budget: none\n",
+		"schedule\n" +
+			"  retry  delay  max    sampled  total\n" +
+			"  1      10ms   10ms   10ms     10ms\n" +
+			"  2      100ms  100ms  100ms    110ms\n" +
+			"  3      200ms  200ms  200ms    310ms\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the description to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+// TestDescribePolicy_Jitter verifies that jittered delays are sampled
+// deterministically from the seed, within their range.
+func TestDescribePolicy_Jitter(t *testing.T) {
+	opts := []retrier.RetryOption{retrier.WithMaxAttempts(30), retrier.WithJitter(50 * time.Millisecond)}
+	first := retrier.DescribePolicy(7, opts...)
+	if second := retrier.DescribePolicy(7, opts...); second != first {
+		t.Errorf("expected the same description for the same seed:\n%s\n%s", first, second)
+	}
+	if other := retrier.DescribePolicy(8, opts...); other == first {
+		t.Error("expected another seed to sample other delays")
+	}
+	if !strings.Contains(first, "  1      1s     1.05s  ") {
+		t.Errorf("expected the first delay to range over [1s, 1.05s), got:\n%s", first)
+	}
+	if !strings.HasSuffix(first, "  ... 9 more retries\n") {
+		t.Errorf("expected the schedule to be truncated after 20 retries, got:\n%s", first)
+	}
+}
+
+// TestDiffGoldenPolicy verifies that a Retrier matches the golden file of its
+// options until they change.
+func TestDiffGoldenPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "payments.policy")
+	opts := []retrier.RetryOption{retrier.WithMaxAttempts(3), retrier.WithInitialDuration(time.Second)}
+	if err := retrier.WriteGoldenPolicy(path, 1, opts...); err != nil {
+		t.Fatalf("WriteGoldenPolicy() error = %v", err)
+	}
+
+	r := retrier.NewRetrier(noopLogger, opts...)
+	if diff, err := retrier.DiffGoldenPolicy(path, 1, r); err != nil || diff != "" {
+		t.Errorf("expected no diff, got %v:\n%s", err, diff)
+	}
+
+	r.UpdateOptions(retrier.WithMaxAttempts(4), retrier.WithInitialDuration(time.Second))
+	diff, err := retrier.DiffGoldenPolicy(path, 1, r)
+	if err != nil {
+		t.Fatalf("DiffGoldenPolicy() error = %v", err)
+	}
+	want := "-  max_attempts: 3\n+  max_attempts: 4\n+  3      4s     4s   4s       7s\n"
+	if diff != want {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", diff, want)
+	}
+
+	if _, err := retrier.DiffGoldenPolicy(filepath.Join(t.TempDir(), "missing"), 1, r); err == nil {
+		t.Error("expected an error for a missing golden file")
+	}
+}
@@ -0,0 +1,96 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/graphqlretry"
+)
+
+// TestGraphQLRetry_Check tests classification of response bodies.
+func TestGraphQLRetry_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+		want    retrier.RetryPolicy
+	}{
+		{"data only", `{"data":{"user":{"id":"1"}}}`, false, 0},
+		{"rate limited", `{"errors":[{"message":"slow down","extensions":{"code":"RATE_LIMITED"}}]}`, true, retrier.RetryPolicyAuto},
+		{"internal", `{"data":null,"errors":[{"message":"boom","extensions":{"code":"INTERNAL_SERVER_ERROR"}}]}`, true, retrier.RetryPolicyAuto},
+		{"validation", `{"errors":[{"message":"unknown field","extensions":{"code":"GRAPHQL_VALIDATION_FAILED"}}]}`, true, retrier.RetryPolicyNever},
+		{"permanent wins", `{"errors":[{"message":"a","extensions":{"code":"INTERNAL"}},{"message":"b","extensions":{"code":"FORBIDDEN"}}]}`, true, retrier.RetryPolicyNever},
+		{"no code", `{"errors":[{"message":"something failed"}]}`, true, retrier.RetryPolicyManual},
+		{"not json", `<html>bad gateway</html>`, true, retrier.RetryPolicyNever},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := graphqlretry.Check([]byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			var retryable retrier.RetryableError
+			if !errors.As(err, &retryable) || retryable.RetryPolicy() != tt.want {
+				t.Errorf("expected policy %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// TestGraphQLRetry_ResponseError tests the message and suggested delay.
+func TestGraphQLRetry_ResponseError(t *testing.T) {
+	err := graphqlretry.Check([]byte(`{"errors":[
+		{"message":"slow down","extensions":{"code":"rate_limited","retryAfter":2}},
+		{"message":"partial failure"}
+	]}`))
+	var respErr *graphqlretry.ResponseError
+	if !errors.As(err, &respErr) {
+		t.Fatalf("expected *ResponseError, got %T", err)
+	}
+	if got, want := respErr.Error(), "graphql: RATE_LIMITED: slow down; partial failure"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got := respErr.SuggestedDelay(); got != 2*time.Second {
+		t.Errorf("SuggestedDelay() = %v, want 2s", got)
+	}
+	if graphqlretry.Classify(graphqlretry.Response{}) != nil {
+		t.Error("expected nil for a response without errors")
+	}
+}
+
+// TestGraphQLRetry_WithRetryOnResult tests retrying 200-with-errors responses.
+func TestGraphQLRetry_WithRetryOnResult(t *testing.T) {
+	bodies := []string{
+		`{"errors":[{"message":"try later","extensions":{"code":"SERVICE_UNAVAILABLE"}}]}`,
+		`{"data":{"ok":true}}`,
+	}
+	calls := 0
+	opts := append(defaultTestOpts(), retrier.WithRetryOnResult(graphqlretry.Check))
+	result := retrier.Retry(context.Background(), noopLogger, func() ([]byte, error) {
+		body := bodies[calls]
+		calls++
+		return []byte(body), nil
+	}, opts...)
+
+	if result.IsFailure() || calls != 2 || string(result.Value()) != bodies[1] {
+		t.Errorf("expected success on the second call, got %v after %d calls", result.Err(), calls)
+	}
+
+	calls = 0
+	bodies[0] = `{"errors":[{"message":"bad id","extensions":{"code":"BAD_USER_INPUT"}}]}`
+	result = retrier.Retry(context.Background(), noopLogger, func() ([]byte, error) {
+		body := bodies[calls]
+		calls++
+		return []byte(body), nil
+	}, opts...)
+	var respErr *graphqlretry.ResponseError
+	if calls != 1 || !errors.As(result.Err(), &respErr) {
+		t.Errorf("expected BAD_USER_INPUT to stop after 1 call, got %v after %d calls", result.Err(), calls)
+	}
+}
@@ -0,0 +1,159 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestGroup_ResultsInOrder tests that Wait returns the Results in Go order.
+func TestGroup_ResultsInOrder(t *testing.T) {
+	g := retrier.NewGroup[int](context.Background(), noopLogger, 0, defaultTestOpts()...)
+	for i := 0; i < 5; i++ {
+		calls := 0
+		g.Go(func(ctx context.Context) (int, error) {
+			calls++
+			if calls == 1 && i%2 == 0 {
+				return 0, errors.New("transient")
+			}
+			return i * 10, nil
+		})
+	}
+
+	results, err := g.Wait()
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+	for i, result := range results {
+		if result.Value() != i*10 {
+			t.Errorf("results[%d].Value() = %d, want %d", i, result.Value(), i*10)
+		}
+		wantAttempts := 1
+		if i%2 == 0 {
+			wantAttempts = 2
+		}
+		if result.Attempts() != wantAttempts {
+			t.Errorf("results[%d].Attempts() = %d, want %d", i, result.Attempts(), wantAttempts)
+		}
+	}
+}
+
+// TestGroup_Limit tests that at most limit operations run at once.
+func TestGroup_Limit(t *testing.T) {
+	var running, peak atomic.Int32
+	g := retrier.NewGroup[struct{}](context.Background(), noopLogger, 2, defaultTestOpts()...)
+	for i := 0; i < 6; i++ {
+		g.Go(func(ctx context.Context) (struct{}, error) {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return struct{}{}, nil
+		})
+	}
+
+	if _, err := g.Wait(); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if peak.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent operations, got %d", peak.Load())
+	}
+}
+
+// TestGroup_CancelOnFailure tests that the first failure cancels the other
+// operations and is returned by Wait.
+func TestGroup_CancelOnFailure(t *testing.T) {
+	permanent := retrier.Permanent(errors.New("bad input"))
+	g := retrier.NewGroup[int](context.Background(), noopLogger, 0,
+		append(defaultTestOpts(), retrier.WithMaxAttempts(100), retrier.WithInitialDuration(time.Second))...)
+
+	g.Go(func(ctx context.Context) (int, error) {
+		return 0, errors.New("still failing")
+	})
+	g.Go(func(ctx context.Context) (int, error) {
+		return 0, permanent
+	})
+
+	done := make(chan struct{})
+	var results []retrier.Result[int]
+	var err error
+	go func() {
+		results, err = g.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Wait() did not return after a failure cancelled the group")
+	}
+	if !errors.Is(err, permanent) {
+		t.Errorf("Wait() error = %v, want the permanent error", err)
+	}
+	var retryErr *retrier.RetryError
+	if !errors.As(results[0].Err(), &retryErr) || retryErr.Cause != retrier.ErrContextCancelled {
+		t.Errorf("expected the other operation to be cancelled, got %v", results[0].Err())
+	}
+}
+
+// TestGroup_SharedBudget tests that operations draw on one retry budget.
+func TestGroup_SharedBudget(t *testing.T) {
+	budget := retrier.NewRetryBudget(0, retrier.WithMinRetries(2), retrier.WithBudgetWindow(time.Hour))
+	var calls atomic.Int32
+	g := retrier.NewGroup[int](context.Background(), noopLogger, 1,
+		append(defaultTestOpts(), retrier.WithMaxAttempts(5), retrier.WithBudget(budget))...)
+	for i := 0; i < 3; i++ {
+		g.Go(func(ctx context.Context) (int, error) {
+			calls.Add(1)
+			return 0, errors.New("down")
+		})
+	}
+
+	if _, err := g.Wait(); err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := calls.Load(); got > 5 {
+		t.Errorf("expected the shared budget to cap retries, got %d calls", got)
+	}
+}
+
+// TestGroup_AttemptContext tests that fn receives the attempt context, so per-attempt
+// deadlines cut a hanging attempt short.
+func TestGroup_AttemptContext(t *testing.T) {
+	g := retrier.NewGroup[int](context.Background(), noopLogger, 0,
+		append(defaultTestOpts(), retrier.WithMaxAttempts(2), retrier.WithMaxAttemptDuration(10*time.Millisecond))...)
+	var attempts atomic.Int32
+	var cutShort atomic.Bool
+	g.Go(func(ctx context.Context) (int, error) {
+		if attempts.Add(1) > 1 {
+			return 1, nil
+		}
+		select {
+		case <-ctx.Done():
+			cutShort.Store(true)
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+			return 0, errors.New("hung")
+		}
+	})
+
+	results, err := g.Wait()
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if !cutShort.Load() || results[0].Value() != 1 {
+		t.Errorf("expected the first attempt cut short and retried, got value %d after %d attempts", results[0].Value(), results[0].Attempts())
+	}
+}
@@ -0,0 +1,154 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/grpcretry"
+)
+
+// grpcCode stands in for codes.Code.
+type grpcCode uint32
+
+// grpcError stands in for a gRPC status error.
+type grpcError struct {
+	code grpcCode
+}
+
+func (e *grpcError) Error() string { return "rpc error" }
+
+// grpcStatusCode stands in for status.Code.
+func grpcStatusCode(err error) grpcCode {
+	var ge *grpcError
+	if errors.As(err, &ge) {
+		return ge.code
+	}
+	return 2 // UNKNOWN
+}
+
+// TestParsePolicy tests parsing and validation of retryPolicy objects.
+func TestParsePolicy(t *testing.T) {
+	p, err := grpcretry.ParsePolicy([]byte(`{
+		"maxAttempts": 9,
+		"initialBackoff": "0.1s",
+		"maxBackoff": "2s",
+		"backoffMultiplier": 1.5,
+		"retryableStatusCodes": ["UNAVAILABLE", "resource_exhausted", 4]
+	}`))
+	if err != nil {
+		t.Fatalf("ParsePolicy() error = %v", err)
+	}
+	if p.MaxAttempts != 5 {
+		t.Errorf("expected maxAttempts capped to 5, got %d", p.MaxAttempts)
+	}
+	if p.InitialBackoff != 100*time.Millisecond || p.MaxBackoff != 2*time.Second || p.BackoffMultiplier != 1.5 {
+		t.Errorf("unexpected backoff %+v", p)
+	}
+	if len(p.RetryableStatusCodes) != 3 || p.RetryableStatusCodes[0] != 14 || p.RetryableStatusCodes[1] != 8 || p.RetryableStatusCodes[2] != 4 {
+		t.Errorf("unexpected codes %v", p.RetryableStatusCodes)
+	}
+
+	invalid := []string{
+		`{"maxAttempts": 1, "initialBackoff": "1s", "maxBackoff": "1s", "backoffMultiplier": 2, "retryableStatusCodes": ["UNAVAILABLE"]}`,
+		`{"maxAttempts": 3, "initialBackoff": "100ms", "maxBackoff": "1s", "backoffMultiplier": 2, "retryableStatusCodes": ["UNAVAILABLE"]}`,
+		`{"maxAttempts": 3, "initialBackoff": "1s", "maxBackoff": "1s", "backoffMultiplier": 0, "retryableStatusCodes": ["UNAVAILABLE"]}`,
+		`{"maxAttempts": 3, "initialBackoff": "1s", "maxBackoff": "1s", "backoffMultiplier": 2, "retryableStatusCodes": []}`,
+		`{"maxAttempts": 3, "initialBackoff": "1s", "maxBackoff": "1s", "backoffMultiplier": 2, "retryableStatusCodes": ["NOPE"]}`,
+	}
+	for _, doc := range invalid {
+		if _, err := grpcretry.ParsePolicy([]byte(doc)); err == nil {
+			t.Errorf("expected error for %s", doc)
+		}
+	}
+}
+
+// TestServiceConfig_Policy tests method name matching precedence.
+func TestServiceConfig_Policy(t *testing.T) {
+	policy := func(attempts int) string {
+		return `{"maxAttempts": ` + string(rune('0'+attempts)) + `, "initialBackoff": "1s", "maxBackoff": "1s", "backoffMultiplier": 2, "retryableStatusCodes": ["UNAVAILABLE"]}`
+	}
+	config, err := grpcretry.ParseServiceConfig([]byte(`{"methodConfig": [
+		{"name": [{}], "retryPolicy": ` + policy(2) + `},
+		{"name": [{"service": "echo.Echo"}], "retryPolicy": ` + policy(3) + `},
+		{"name": [{"service": "echo.Echo", "method": "Stream"}], "retryPolicy": ` + policy(4) + `},
+		{"name": [{"service": "other.Svc"}]}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseServiceConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		method string
+		want   int
+	}{
+		{"/echo.Echo/Stream", 4},
+		{"/echo.Echo/Unary", 3},
+		{"/other.Svc/Call", 2},
+	}
+	for _, tt := range tests {
+		p, ok := config.Policy(tt.method)
+		if !ok || p.MaxAttempts != tt.want {
+			t.Errorf("Policy(%q) = %d, %v; want %d", tt.method, p.MaxAttempts, ok, tt.want)
+		}
+	}
+}
+
+// TestGRPCRetryOptions verifies that only retryable status codes are retried.
+func TestGRPCRetryOptions(t *testing.T) {
+	p := grpcretry.Policy{
+		MaxAttempts:          3,
+		InitialBackoff:       time.Millisecond,
+		MaxBackoff:           time.Millisecond,
+		BackoffMultiplier:    2,
+		RetryableStatusCodes: []uint32{14},
+	}
+
+	callCount := 0
+	result := retrier.Retry(context.Background(), noopLogger, func() (string, error) {
+		callCount++
+		return "", &grpcError{code: 14}
+	}, grpcretry.RetryOptions(p, grpcStatusCode)...)
+	if callCount != 3 || result.IsSuccess() {
+		t.Errorf("expected 3 failed attempts for UNAVAILABLE, got %d", callCount)
+	}
+
+	callCount = 0
+	retrier.Retry(context.Background(), noopLogger, func() (string, error) {
+		callCount++
+		return "", &grpcError{code: 3}
+	}, grpcretry.RetryOptions(p, grpcStatusCode)...)
+	if callCount != 1 {
+		t.Errorf("expected INVALID_ARGUMENT not to be retried, got %d calls", callCount)
+	}
+}
+
+// TestGRPCRetryOptions_FullJitter verifies that delays are drawn below the backoff curve,
+// as gRPC specifies.
+func TestGRPCRetryOptions_FullJitter(t *testing.T) {
+	p := grpcretry.Policy{
+		MaxAttempts:          5,
+		InitialBackoff:       2 * time.Millisecond,
+		MaxBackoff:           8 * time.Millisecond,
+		BackoffMultiplier:    2,
+		RetryableStatusCodes: []uint32{14},
+	}
+	curve := []time.Duration{2 * time.Millisecond, 4 * time.Millisecond, 8 * time.Millisecond, 8 * time.Millisecond}
+
+	logger := &backoffMockLogger{enabled: true}
+	retrier.Retry(context.Background(), logger, func() (string, error) {
+		return "", &grpcError{code: 14}
+	}, grpcretry.RetryOptions(p, grpcStatusCode)...)
+
+	calls := logger.logRetryCalls[:len(logger.logRetryCalls)-1]
+	if len(calls) != len(curve) {
+		t.Fatalf("expected %d retries, got %d", len(curve), len(calls))
+	}
+	for retry, call := range calls {
+		if call.backoff >= curve[retry] {
+			t.Errorf("retry %d: expected a delay below %v, got %v", retry+1, curve[retry], call.backoff)
+		}
+	}
+}
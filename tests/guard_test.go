@@ -0,0 +1,189 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestRetryGuard_SuppressesBeyondCap verifies that operations failing while the
+// guard is full return immediately with ErrRetrySuppressed.
+func TestRetryGuard_SuppressesBeyondCap(t *testing.T) {
+	guard := retrier.NewRetryGuard(1)
+	release := make(chan struct{})
+	retrying := make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		calls := 0
+		fn := func() (string, error) {
+			calls++
+			if calls == 1 {
+				return "", errors.New("transient")
+			}
+			close(retrying)
+			<-release
+			return "ok", nil
+		}
+		retrier.Retry(context.Background(), noopLogger, fn,
+			retrier.WithInitialDuration(time.Millisecond),
+			retrier.WithRetryGuard(guard),
+		)
+	}()
+
+	<-retrying
+	if guard.Active() != 1 {
+		t.Errorf("expected 1 active retry, got %d", guard.Active())
+	}
+
+	calls := 0
+	fn := func() (string, error) {
+		calls++
+		return "", errors.New("transient")
+	}
+	result := retrier.Retry(context.Background(), noopLogger, fn,
+		retrier.WithInitialDuration(time.Millisecond),
+		retrier.WithRetryGuard(guard),
+	)
+
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) {
+		t.Fatalf("expected RetryError, got %T", result.Err())
+	}
+	if retryErr.Cause != retrier.ErrRetrySuppressed {
+		t.Errorf("expected cause %q, got %q", retrier.ErrRetrySuppressed, retryErr.Cause)
+	}
+
+	close(release)
+	wg.Wait()
+	if guard.Active() != 0 {
+		t.Errorf("expected the slot to be released, got %d active", guard.Active())
+	}
+}
+
+// TestRetryGuard_FirstAttemptUnaffected verifies that a full guard does not
+// block operations that succeed on their first attempt.
+func TestRetryGuard_FirstAttemptUnaffected(t *testing.T) {
+	guard := retrier.NewRetryGuard(0)
+	fn := func() (string, error) { return "ok", nil }
+
+	result := retrier.Retry(context.Background(), noopLogger, fn, retrier.WithRetryGuard(guard))
+
+	if result.IsFailure() {
+		t.Fatalf("expected success, got: %v", result.Err())
+	}
+}
+
+// TestRetryGuard_ReleasedOnExhaustion verifies that the slot is released when
+// attempts are exhausted.
+func TestRetryGuard_ReleasedOnExhaustion(t *testing.T) {
+	guard := retrier.NewRetryGuard(1)
+	fn := func() (string, error) { return "", errors.New("transient") }
+
+	for i := 0; i < 2; i++ {
+		result := retrier.Retry(context.Background(), noopLogger, fn,
+			retrier.WithInitialDuration(time.Millisecond),
+			retrier.WithMaxAttempts(2),
+			retrier.WithRetryGuard(guard),
+		)
+		if result.Attempts() != 2 {
+			t.Errorf("run %d: expected 2 attempts, got %d", i, result.Attempts())
+		}
+	}
+	if guard.Active() != 0 {
+		t.Errorf("expected 0 active, got %d", guard.Active())
+	}
+}
+
+// TestRetryGuard_Pressure verifies that Pressure tracks the fraction of the cap
+// in use and that OnPressure fires on threshold crossings in both directions.
+func TestRetryGuard_Pressure(t *testing.T) {
+	guard := retrier.NewRetryGuard(2)
+	var mu sync.Mutex
+	var events []float64
+	guard.OnPressure(1, func(p float64) {
+		mu.Lock()
+		events = append(events, p)
+		mu.Unlock()
+	})
+
+	if guard.Pressure() != 0 {
+		t.Errorf("expected pressure 0, got %v", guard.Pressure())
+	}
+
+	release := make(chan struct{})
+	entered := make(chan struct{}, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			calls := 0
+			retrier.Retry(context.Background(), noopLogger, func() (string, error) {
+				calls++
+				if calls == 1 {
+					return "", errors.New("transient")
+				}
+				entered <- struct{}{}
+				<-release
+				return "ok", nil
+			}, retrier.WithInitialDuration(time.Millisecond), retrier.WithRetryGuard(guard))
+		}()
+	}
+	<-entered
+	<-entered
+
+	if guard.Pressure() != 1 {
+		t.Errorf("expected pressure 1, got %v", guard.Pressure())
+	}
+	close(release)
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 2 || events[0] != 1 || events[1] >= 1 {
+		t.Errorf("expected a rise to 1 and a fall below it, got %v", events)
+	}
+}
+
+// TestRetryGuard_PressureReentrant verifies that an OnPressure callback may
+// call back into the guard.
+func TestRetryGuard_PressureReentrant(t *testing.T) {
+	guard := retrier.NewRetryGuard(1)
+	seen := make(chan float64, 4)
+	guard.OnPressure(1, func(p float64) {
+		seen <- guard.Pressure()
+		guard.OnPressure(0.5, func(float64) {})
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		calls := 0
+		retrier.Retry(context.Background(), noopLogger, func() (string, error) {
+			calls++
+			if calls == 1 {
+				return "", errors.New("transient")
+			}
+			return "ok", nil
+		}, retrier.WithInitialDuration(time.Millisecond), retrier.WithRetryGuard(guard))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Retry deadlocked in the OnPressure callback")
+	}
+	if len(seen) != 2 {
+		t.Errorf("expected the callback on entering and leaving, got %d calls", len(seen))
+	}
+}
@@ -347,6 +347,59 @@ func TestRetry_MaxAttemptsLessThanOne(t *testing.T) {
 	}
 }
 
+// TestRetry_ZeroAttemptsUnlimited verifies that a limit of 0 retries until
+// success when opted in, and is reflected in the result and events.
+func TestRetry_ZeroAttemptsUnlimited(t *testing.T) {
+	events := make(chan retrier.RetryEvent, 20)
+	calls := 0
+	opts := append(defaultTestOpts(),
+		retrier.WithMaxAttempts(0),
+		retrier.WithZeroAttempts(retrier.ZeroAttemptsUnlimited),
+		retrier.WithNotifyChannel(events),
+	)
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		calls++
+		if calls < 10 {
+			return 0, errors.New("transient")
+		}
+		return calls, nil
+	}, opts...)
+	close(events)
+
+	if result.IsFailure() || result.Attempts() != 10 {
+		t.Fatalf("expected success on attempt 10, got %v after %d", result.Err(), result.Attempts())
+	}
+	if result.MaxAttempts() != 0 {
+		t.Errorf("expected no attempt limit, got %d", result.MaxAttempts())
+	}
+	for e := range events {
+		if e.MaxAttempts != 0 {
+			t.Errorf("expected events without attempt limit, got %+v", e)
+		}
+	}
+}
+
+// TestRetry_NegativeAttempts verifies that a negative limit is rejected
+// whatever WithZeroAttempts says.
+func TestRetry_NegativeAttempts(t *testing.T) {
+	called := false
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		called = true
+		return 1, nil
+	}, retrier.WithMaxAttempts(-1), retrier.WithZeroAttempts(retrier.ZeroAttemptsUnlimited))
+
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrZeroAttempt {
+		t.Fatalf("expected ErrZeroAttempt, got %v", result.Err())
+	}
+	if called {
+		t.Error("expected fn not to be called")
+	}
+	if result.MaxAttempts() != -1 {
+		t.Errorf("expected the configured limit in the result, got %d", result.MaxAttempts())
+	}
+}
+
 // TestRetry_GenericTypePointer verifies that Retry works with pointer types
 func TestRetry_GenericTypePointer(t *testing.T) {
 	type Data struct {
@@ -1333,3 +1386,218 @@ func TestRetry_DelaySuggestioner_NonRetryableStopsImmediately(t *testing.T) {
 		t.Fatalf("expected 1 attempt (non-retryable), got: %d", result.Attempts())
 	}
 }
+
+// TestRetry_WithRetryIf verifies that the predicate overrides the retry policy.
+func TestRetry_WithRetryIf(t *testing.T) {
+	permanent := errors.New("permanent")
+	callCount := 0
+	fn := func() (string, error) {
+		callCount++
+		if callCount == 1 {
+			return "", &mockErrorWithDelay{msg: "manual", retryable: false}
+		}
+		return "", permanent
+	}
+
+	opts := append(defaultTestOpts(),
+		retrier.WithMaxAttempts(5),
+		retrier.WithRetryIf(func(err error) bool { return err != permanent }),
+	)
+	result := retrier.Retry(context.Background(), noopLogger, fn, opts...)
+
+	if callCount != 2 {
+		t.Errorf("expected 2 calls, got %d", callCount)
+	}
+	if result.Err() != permanent {
+		t.Errorf("expected the permanent error to be returned as is, got %v", result.Err())
+	}
+}
+
+// TestRetry_WithOnRetry verifies that the callback runs before each retry.
+func TestRetry_WithOnRetry(t *testing.T) {
+	var attempts []int
+	innerErr := errors.New("transient")
+	fn := func() (string, error) { return "", innerErr }
+
+	opts := append(defaultTestOpts(),
+		retrier.WithMaxAttempts(3),
+		retrier.WithOnRetry(func(attempt int, err error) {
+			if err != innerErr {
+				t.Errorf("expected the attempt error, got %v", err)
+			}
+			attempts = append(attempts, attempt)
+		}),
+	)
+	retrier.Retry(context.Background(), noopLogger, fn, opts...)
+
+	if len(attempts) != 2 || attempts[0] != 1 || attempts[1] != 2 {
+		t.Errorf("expected callbacks for attempts [1 2], got %v", attempts)
+	}
+}
+
+// TestRetry_WithEnabledFunc verifies that the kill switch is consulted before
+// each retry but not before the first attempt.
+func TestRetry_WithEnabledFunc(t *testing.T) {
+	checks := 0
+	callCount := 0
+	innerErr := errors.New("transient")
+	fn := func() (string, error) {
+		callCount++
+		return "", innerErr
+	}
+
+	opts := append(defaultTestOpts(),
+		retrier.WithMaxAttempts(5),
+		retrier.WithEnabledFunc(func(context.Context) bool {
+			checks++
+			return checks < 2
+		}),
+	)
+	result := retrier.Retry(context.Background(), noopLogger, fn, opts...)
+
+	if callCount != 2 || checks != 2 {
+		t.Errorf("expected 2 calls and 2 checks, got %d and %d", callCount, checks)
+	}
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrRetriesDisabled {
+		t.Fatalf("expected ErrRetriesDisabled, got %v", result.Err())
+	}
+	if !errors.Is(result.Err(), innerErr) {
+		t.Error("expected the last error to be wrapped")
+	}
+}
+
+// TestRetry_WithEnabledFunc_NotCheckedOnSuccess verifies that a first-attempt
+// success never consults the switch.
+func TestRetry_WithEnabledFunc_NotCheckedOnSuccess(t *testing.T) {
+	result := retrier.Retry(context.Background(), noopLogger,
+		func() (string, error) { return "ok", nil },
+		retrier.WithEnabledFunc(func(context.Context) bool {
+			t.Error("switch consulted without a retry")
+			return false
+		}),
+	)
+	if result.IsFailure() {
+		t.Errorf("expected success, got %v", result.Err())
+	}
+}
+
+// TestRetry_WithRetryOnResult verifies that results failing the check are retried.
+func TestRetry_WithRetryOnResult(t *testing.T) {
+	callCount := 0
+	fn := func() (string, error) {
+		callCount++
+		if callCount < 3 {
+			return "pending", nil
+		}
+		return "done", nil
+	}
+
+	pending := errors.New("still pending")
+	opts := append(defaultTestOpts(),
+		retrier.WithMaxAttempts(5),
+		retrier.WithRetryOnResult(func(status string) error {
+			if status == "pending" {
+				return pending
+			}
+			return nil
+		}),
+	)
+	result := retrier.Retry(context.Background(), noopLogger, fn, opts...)
+
+	if callCount != 3 || result.Value() != "done" {
+		t.Errorf("expected \"done\" after 3 calls, got %q after %d", result.Value(), callCount)
+	}
+	if errs := result.Errors(); len(errs) != 2 || errs[0] != pending {
+		t.Errorf("expected 2 pending errors in the history, got %v", errs)
+	}
+}
+
+// TestRetry_WithRetryOnResult_TypeMismatch verifies that a check of another type than
+// the results fails the call before the first attempt instead of being skipped.
+func TestRetry_WithRetryOnResult_TypeMismatch(t *testing.T) {
+	called := false
+	result := retrier.Retry(context.Background(), noopLogger, func() (string, error) {
+		called = true
+		return "pending", nil
+	}, append(defaultTestOpts(), retrier.WithRetryOnResult(func(int) error { return nil }))...)
+
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrResultTypeMismatch {
+		t.Fatalf("expected ErrResultTypeMismatch, got %v", result.Err())
+	}
+	if called {
+		t.Error("expected no attempt")
+	}
+
+	// Interface results are checked by their dynamic type
+	checked := false
+	anyResult := retrier.Retry(context.Background(), noopLogger, func() (any, error) {
+		return "done", nil
+	}, append(defaultTestOpts(), retrier.WithRetryOnResult(func(string) error { checked = true; return nil }))...)
+	if anyResult.IsFailure() || !checked {
+		t.Errorf("expected the string check to run on an any result, got %v", anyResult.Err())
+	}
+}
+
+// TestRetry_WithRetryOnResult_Exhausted verifies that a result failing every
+// check exhausts the attempts without a value.
+func TestRetry_WithRetryOnResult_Exhausted(t *testing.T) {
+	pending := errors.New("still pending")
+	opts := append(defaultTestOpts(),
+		retrier.WithRetryOnResult(func(status string) error { return pending }),
+	)
+	result := retrier.Retry(context.Background(), noopLogger, func() (string, error) {
+		return "pending", nil
+	}, opts...)
+
+	if !errors.Is(result.Err(), pending) || result.Value() != "" || result.Attempts() != 3 {
+		t.Errorf("expected exhausted attempts wrapping the check error, got %q, %v after %d", result.Value(), result.Err(), result.Attempts())
+	}
+}
+
+// TestWithHealthCheck_ExtendsWait verifies that failing probes extend the
+// backoff delay without spending attempts.
+func TestWithHealthCheck_ExtendsWait(t *testing.T) {
+	probes, calls := 0, 0
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, errors.New("down")
+		}
+		return 42, nil
+	}, append(defaultTestOpts(), retrier.WithMaxAttempts(2), retrier.WithHealthCheck(func(context.Context) bool {
+		probes++
+		return probes > 3
+	}))...)
+
+	if !result.IsSuccess() || result.Attempts() != 2 {
+		t.Fatalf("expected success on attempt 2, got %v after %d", result.Err(), result.Attempts())
+	}
+	if probes != 4 {
+		t.Errorf("expected 4 probes, got %d", probes)
+	}
+}
+
+// TestWithHealthCheck_ContextCancelled verifies that a dependency that stays
+// down keeps the loop waiting until the context is done.
+func TestWithHealthCheck_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	calls := 0
+
+	result := retrier.Retry(ctx, noopLogger, func() (int, error) {
+		calls++
+		return 0, errors.New("down")
+	}, append(defaultTestOpts(), retrier.WithMaxAttempts(5), retrier.WithHealthCheck(func(context.Context) bool {
+		return false
+	}))...)
+
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrContextCancelled {
+		t.Fatalf("expected ErrContextCancelled, got %v", result.Err())
+	}
+	if calls != 1 || result.Attempts() != 1 {
+		t.Errorf("expected a single attempt, got %d calls and %d attempts", calls, result.Attempts())
+	}
+}
@@ -0,0 +1,652 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/httpretry"
+)
+
+// newTestClient returns an httpretry.Client with short waits for testing.
+func newTestClient(retryMax int) *httpretry.Client {
+	client := httpretry.NewClient()
+	client.RetryWaitMin = time.Millisecond
+	client.RetryWaitMax = 5 * time.Millisecond
+	client.RetryMax = retryMax
+	return client
+}
+
+// TestClient_RetriesServerErrors verifies that 5xx responses are retried and the
+// request body is replayed on every attempt.
+func TestClient_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("attempt %d: expected body %q, got %q", calls.Load()+1, "payload", body)
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	resp, err := newTestClient(4).Post(server.URL, "text/plain", []byte("payload"))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Errorf("unexpected response %d %q", resp.StatusCode, body)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 calls, got %d", calls.Load())
+	}
+}
+
+// TestClient_DoesNotRetryClientErrors verifies that 4xx responses are returned as is.
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	resp, err := newTestClient(4).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound || calls.Load() != 1 {
+		t.Errorf("expected a single 404, got %d after %d calls", resp.StatusCode, calls.Load())
+	}
+}
+
+// TestClient_GivesUp verifies the error returned when retries run out, and the
+// ErrorHandler override.
+func TestClient_GivesUp(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := newTestClient(2)
+	if _, err := client.Get(server.URL); err == nil || !containsString(err.Error(), "giving up after 3 attempt(s)") {
+		t.Errorf("expected giving up error, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 calls, got %d", calls.Load())
+	}
+
+	client.ErrorHandler = func(resp *http.Response, err error, numTries int) (*http.Response, error) {
+		if numTries != 3 {
+			t.Errorf("expected 3 tries, got %d", numTries)
+		}
+		return resp, nil
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected ErrorHandler result, got %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the last response, got %d", resp.StatusCode)
+	}
+}
+
+// TestClient_CheckRetryError verifies that an error from CheckRetry stops retries.
+func TestClient_CheckRetryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	stop := errors.New("stop")
+	client := newTestClient(4)
+	client.CheckRetry = func(_ context.Context, _ *http.Response, _ error) (bool, error) {
+		return false, stop
+	}
+	if _, err := client.Get(server.URL); err != stop {
+		t.Errorf("expected CheckRetry error, got %v", err)
+	}
+}
+
+// TestClient_StandardClient verifies that the standard client retries.
+func TestClient_StandardClient(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	resp, err := newTestClient(1).StandardClient().Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent || calls.Load() != 2 {
+		t.Errorf("expected 204 after 2 calls, got %d after %d", resp.StatusCode, calls.Load())
+	}
+}
+
+// TestClient_ErrorBodyRetryPolicy verifies that body hints override the status
+// code classification and that the body is still readable afterwards.
+func TestClient_ErrorBodyRetryPolicy(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"title": "Locked", "retryable": true, "retry_after": 0.005}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"title": "Corrupt", "retryable": false}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(4)
+	client.CheckRetry = httpretry.ErrorBodyRetryPolicy
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusInternalServerError || !containsString(string(body), "Corrupt") {
+		t.Errorf("expected the permanent 500 with its body, got %d %q", resp.StatusCode, body)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 calls, got %d", calls.Load())
+	}
+}
+
+// TestClient_ProxyRotator verifies that a rate-limited proxy is rotated away from.
+func TestClient_ProxyRotator(t *testing.T) {
+	var limitedCalls, okCalls atomic.Int32
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limitedCalls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer limited.Close()
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		okCalls.Add(1)
+		if r.URL.Host != "origin.invalid" {
+			t.Errorf("expected a proxied request for origin.invalid, got %q", r.URL.String())
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ok.Close()
+
+	limitedURL, _ := url.Parse(limited.URL)
+	okURL, _ := url.Parse(ok.URL)
+	rotator := httpretry.NewRoundRobinRotator(httpretry.Egress{Proxy: limitedURL}, httpretry.Egress{Proxy: okURL})
+	client := newTestClient(3)
+	client.ProxyRotator = rotator
+
+	resp, err := client.Get("http://origin.invalid/")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if limitedCalls.Load() != 1 || okCalls.Load() != 1 {
+		t.Errorf("expected 1 call per proxy, got %d and %d", limitedCalls.Load(), okCalls.Load())
+	}
+
+	// The next request starts on the proxy that worked
+	resp, err = client.Get("http://origin.invalid/")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if limitedCalls.Load() != 1 || okCalls.Load() != 2 {
+		t.Errorf("expected the second request to skip the limited proxy, got %d and %d", limitedCalls.Load(), okCalls.Load())
+	}
+}
+
+// TestClient_ProxyRotator_CustomTransport verifies that a transport that cannot
+// be cloned per egress is reported.
+func TestClient_ProxyRotator_CustomTransport(t *testing.T) {
+	client := newTestClient(1)
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		t.Error("unexpected request")
+		return nil, errors.New("unexpected")
+	})}
+	client.ProxyRotator = httpretry.NewRoundRobinRotator()
+
+	if _, err := client.Get("http://origin.invalid/"); err == nil {
+		t.Error("expected an error for a custom transport")
+	}
+}
+
+// TestRoundRobinRotator verifies which failures rotate the egress.
+func TestRoundRobinRotator(t *testing.T) {
+	a := httpretry.Egress{LocalAddr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1")}}
+	b := httpretry.Egress{LocalAddr: &net.TCPAddr{IP: net.ParseIP("10.0.0.2")}}
+	rotator := httpretry.NewRoundRobinRotator(a, b)
+
+	steps := []struct {
+		prev *httpretry.Failure
+		want httpretry.Egress
+	}{
+		{nil, a},
+		{&httpretry.Failure{Kind: httpretry.FailureOther, StatusCode: 502, Egress: a}, a},
+		{&httpretry.Failure{Kind: httpretry.FailureBlocked, StatusCode: 403, Egress: a}, b},
+		// A stale failure of an egress already rotated away from
+		{&httpretry.Failure{Kind: httpretry.FailureRateLimited, StatusCode: 429, Egress: a}, b},
+		{&httpretry.Failure{Kind: httpretry.FailureTransport, Egress: b}, a},
+	}
+	for i, step := range steps {
+		if got := rotator.Egress(i+1, step.prev); got.LocalAddr != step.want.LocalAddr {
+			t.Errorf("step %d: got egress %v, want %v", i, got.LocalAddr, step.want.LocalAddr)
+		}
+	}
+
+	if got := httpretry.ClassifyFailure(nil, errors.New("dial")); got != httpretry.FailureTransport {
+		t.Errorf("ClassifyFailure(transport error) = %v", got)
+	}
+	if got := httpretry.ClassifyFailure(&http.Response{StatusCode: 407}, nil); got != httpretry.FailureBlocked {
+		t.Errorf("ClassifyFailure(407) = %v", got)
+	}
+}
+
+// roundTripFunc is an http.RoundTripper calling a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// streamBody is a request body without GetBody, like a file or a pipe.
+type streamBody struct{ io.Reader }
+
+// newStreamRequest returns a POST request whose body cannot be replayed
+// through GetBody.
+func newStreamRequest(t *testing.T, url, body string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, streamBody{strings.NewReader(body)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.GetBody != nil {
+		t.Fatal("expected a request without GetBody")
+	}
+	return req
+}
+
+// bodyRecorder returns a server answering 503 to the first request and
+// recording the bodies it receives. Read the bodies once requests are done.
+func bodyRecorder(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		first := len(bodies) == 1
+		mu.Unlock()
+		if first {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies
+}
+
+// TestClient_StandardClient_BuffersSmallBodies verifies that small bodies
+// without GetBody are buffered and replayed intact.
+func TestClient_StandardClient_BuffersSmallBodies(t *testing.T) {
+	server, bodies := bodyRecorder(t)
+
+	resp, err := newTestClient(1).StandardClient().Do(newStreamRequest(t, server.URL, "payload"))
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent || len(*bodies) != 2 || (*bodies)[1] != "payload" {
+		t.Errorf("expected a retry with the same body, got %d and bodies %q", resp.StatusCode, *bodies)
+	}
+}
+
+// TestClient_StandardClient_LargeBodyNotRetried verifies that bodies too
+// large to buffer are sent once and their failures are not retried.
+func TestClient_StandardClient_LargeBodyNotRetried(t *testing.T) {
+	server, bodies := bodyRecorder(t)
+	client := newTestClient(1)
+	client.MaxBufferedBody = 4
+
+	resp, err := client.StandardClient().Do(newStreamRequest(t, server.URL, "large payload"))
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || len(*bodies) != 1 || (*bodies)[0] != "large payload" {
+		t.Errorf("expected the 503 of a single complete request, got %d and bodies %q", resp.StatusCode, *bodies)
+	}
+}
+
+// TestClient_StandardClient_LargeBodyTransportError verifies that transport
+// errors of requests with consumed bodies report ErrBodyNotReplayable.
+func TestClient_StandardClient_LargeBodyTransportError(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(2)
+	client.MaxBufferedBody = 4
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		_, _ = io.Copy(io.Discard, req.Body)
+		return nil, errors.New("connection reset")
+	})}
+
+	_, err := client.StandardClient().Do(newStreamRequest(t, "http://example.invalid", "large payload"))
+	if !errors.Is(err, httpretry.ErrBodyNotReplayable) || calls.Load() != 1 {
+		t.Errorf("expected ErrBodyNotReplayable after 1 call, got %v after %d", err, calls.Load())
+	}
+}
+
+// TestClient_StandardClient_BufferLargeBodies verifies the opt-in to retry
+// bodies of any size.
+func TestClient_StandardClient_BufferLargeBodies(t *testing.T) {
+	server, bodies := bodyRecorder(t)
+	client := newTestClient(1)
+	client.MaxBufferedBody = 4
+	client.BufferLargeBodies = true
+
+	resp, err := client.StandardClient().Do(newStreamRequest(t, server.URL, "large payload"))
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent || len(*bodies) != 2 || (*bodies)[1] != "large payload" {
+		t.Errorf("expected a retry with the same body, got %d and bodies %q", resp.StatusCode, *bodies)
+	}
+}
+
+// TestClient_Hosts verifies that a host exhausting its retry budget does not
+// hold back the retries of other hosts sharing the client.
+func TestClient_Hosts(t *testing.T) {
+	var failingCalls, flakyCalls atomic.Int32
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		failingCalls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if flakyCalls.Add(1)%2 == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer flaky.Close()
+
+	client := newTestClient(2)
+	client.Hosts = retrier.NewKeyedRetriers(retrier.NewNoOpLogger(), 0, func(host string) []retrier.RetryOption {
+		return []retrier.RetryOption{retrier.WithBudget(retrier.NewRetryBudget(0, retrier.WithMinRetries(2)))}
+	})
+
+	for range 2 {
+		if _, err := client.Get(failing.URL); err == nil {
+			t.Fatal("expected the failing host to give up")
+		}
+	}
+	if got := failingCalls.Load(); got != 4 {
+		t.Errorf("expected 3 calls, then 1 once the budget is exhausted, got %d", got)
+	}
+
+	for range 2 {
+		resp, err := client.Get(flaky.URL)
+		if err != nil {
+			t.Fatalf("expected the flaky host to be retried, got %v", err)
+		}
+		resp.Body.Close()
+	}
+	if got := len(client.Hosts.Keys()); got != 2 {
+		t.Errorf("expected a Retrier per host, got %d", got)
+	}
+}
+
+// TestClient_MaxErrorBody verifies that the error of a request retried until
+// giving up carries a bounded prefix of the last response body, and that
+// ErrorHandler still receives the full body.
+func TestClient_MaxErrorBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = io.WriteString(w, "database unavailable, please try again later")
+	}))
+	defer server.Close()
+
+	client := newTestClient(1)
+	client.MaxErrorBody = 20
+	_, err := client.Get(server.URL)
+
+	var respErr *httpretry.ResponseError
+	if !errors.As(err, &respErr) {
+		t.Fatalf("expected a ResponseError, got %v", err)
+	}
+	if respErr.StatusCode != http.StatusServiceUnavailable || string(respErr.Body) != "database unavailable" || !respErr.Truncated {
+		t.Errorf("unexpected ResponseError %+v", respErr)
+	}
+	if !strings.Contains(err.Error(), "503 Service Unavailable: database unavailable...") {
+		t.Errorf("expected the body prefix in the error, got %q", err)
+	}
+
+	client.ErrorHandler = func(resp *http.Response, err error, numTries int) (*http.Response, error) {
+		return resp, err
+	}
+	resp, _ := client.Get(server.URL)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "database unavailable, please try again later" {
+		t.Errorf("expected ErrorHandler to receive the full body, got %q", body)
+	}
+
+	client.ErrorHandler = nil
+	client.MaxErrorBody = -1
+	if _, err := client.Get(server.URL); !errors.As(err, &respErr) || respErr.Body != nil {
+		t.Errorf("expected no body to be captured, got %v", err)
+	}
+}
+
+// TestClient_AttemptHeaders verifies that every attempt carries its number,
+// the same generated idempotency key, the request's own request ID, and
+// headers from the attempt context.
+func TestClient_AttemptHeaders(t *testing.T) {
+	type tenantKey struct{}
+	var mu sync.Mutex
+	var seen []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Header.Clone())
+		n := len(seen)
+		mu.Unlock()
+		if n < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(4)
+	client.Idempotency = httpretry.NewIdempotencyPolicy()
+	client.AttemptHeaders = &httpretry.AttemptHeaders{
+		Attempt:        httpretry.DefaultAttemptHeader,
+		IdempotencyKey: httpretry.DefaultIdempotencyKeyHeader,
+		RequestID:      httpretry.DefaultRequestIDHeader,
+		FromContext: func(ctx context.Context, attempt int, h http.Header) {
+			if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+				h.Set("X-Tenant", tenant)
+			}
+		},
+	}
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	req, _ := httpretry.NewRequestWithContext(ctx, http.MethodPost, server.URL, "payload")
+	req.Header.Set(httpretry.DefaultRequestIDHeader, "req-1")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if len(seen) != 3 {
+		t.Fatalf("expected the POST to be retried thanks to its key, got %d calls", len(seen))
+	}
+	key := seen[0].Get(httpretry.DefaultIdempotencyKeyHeader)
+	for i, h := range seen {
+		if got := h.Get(httpretry.DefaultAttemptHeader); got != strconv.Itoa(i+1) {
+			t.Errorf("attempt %d: expected attempt header %d, got %q", i+1, i+1, got)
+		}
+		if key == "" || h.Get(httpretry.DefaultIdempotencyKeyHeader) != key {
+			t.Errorf("attempt %d: expected the idempotency key %q, got %q", i+1, key, h.Get(httpretry.DefaultIdempotencyKeyHeader))
+		}
+		if h.Get(httpretry.DefaultRequestIDHeader) != "req-1" || h.Get("X-Tenant") != "acme" {
+			t.Errorf("attempt %d: unexpected headers %v", i+1, h)
+		}
+	}
+}
+
+// TestClient_DoesNotModifyRequest verifies that the attempts of a request
+// are sent from clones, leaving the request of the caller as it was.
+func TestClient_DoesNotModifyRequest(t *testing.T) {
+	server, bodies := bodyRecorder(t)
+	client := newTestClient(1)
+	client.AttemptHeaders = &httpretry.AttemptHeaders{
+		Attempt:        httpretry.DefaultAttemptHeader,
+		IdempotencyKey: httpretry.DefaultIdempotencyKeyHeader,
+	}
+	req, _ := httpretry.NewRequest(http.MethodPut, server.URL, "payload")
+	req.Header.Set("X-Trace", "abc")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if len(*bodies) != 2 || (*bodies)[1] != "payload" {
+		t.Fatalf("expected a retry with the same body, got %q", *bodies)
+	}
+	if len(req.Header) != 1 || req.Header.Get("X-Trace") != "abc" {
+		t.Errorf("expected the headers of the request unchanged, got %v", req.Header)
+	}
+	if req.Body != nil || req.ContentLength != int64(len("payload")) {
+		t.Errorf("expected no body set on the request, got %v with length %d", req.Body, req.ContentLength)
+	}
+}
+
+// closeRecorder is a request body recording when it is closed.
+type closeRecorder struct {
+	io.Reader
+	closed atomic.Bool
+}
+
+func (b *closeRecorder) Close() error {
+	b.closed.Store(true)
+	return nil
+}
+
+// TestFromRequest_DoesNotModifyRequest verifies that buffering a body
+// happens in a clone, without closing the body or changing the request.
+func TestFromRequest_DoesNotModifyRequest(t *testing.T) {
+	body := &closeRecorder{Reader: strings.NewReader("payload")}
+	req, _ := http.NewRequest(http.MethodPost, "http://example.invalid", body)
+	req.ContentLength = -1
+
+	retryable, err := httpretry.FromRequest(req)
+	if err != nil {
+		t.Fatalf("FromRequest() error = %v", err)
+	}
+	if req.ContentLength != -1 || req.Body != io.ReadCloser(body) || body.closed.Load() {
+		t.Errorf("expected the request unchanged, got length %d, body %v, closed %v", req.ContentLength, req.Body, body.closed.Load())
+	}
+	if retryable.Request == req || retryable.ContentLength != int64(len("payload")) {
+		t.Errorf("expected a clone with the length of the body, got length %d", retryable.ContentLength)
+	}
+}
+
+// TestClient_StandardClient_DoesNotModifyRequest verifies that the standard
+// client buffers bodies into clones, and closes the body only once done, as
+// http.RoundTripper requires.
+func TestClient_StandardClient_DoesNotModifyRequest(t *testing.T) {
+	server, bodies := bodyRecorder(t)
+	body := &closeRecorder{Reader: strings.NewReader("payload")}
+	req, _ := http.NewRequest(http.MethodPost, server.URL, body)
+	req.ContentLength = -1
+
+	var closedDuringAttempts bool
+	client := newTestClient(1)
+	client.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		closedDuringAttempts = closedDuringAttempts || body.closed.Load()
+		return httpretry.DefaultRetryPolicy(ctx, resp, err)
+	}
+	resp, err := client.StandardClient().Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if len(*bodies) != 2 || (*bodies)[1] != "payload" {
+		t.Fatalf("expected a retry with the same body, got %q", *bodies)
+	}
+	if req.ContentLength != -1 || req.Body != io.ReadCloser(body) {
+		t.Errorf("expected the request unchanged, got length %d and body %v", req.ContentLength, req.Body)
+	}
+	if closedDuringAttempts || !body.closed.Load() {
+		t.Errorf("expected the body closed once done, closed during attempts: %v", closedDuringAttempts)
+	}
+}
+
+// TestClient_Pacer verifies that the Client observes responses into its Pacer and waits
+// for it before sending.
+func TestClient_Pacer(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("RateLimit-Remaining", "0")
+		w.Header().Set("RateLimit-Reset", "30")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := newTestClient(1)
+	client.Pacer = httpretry.NewPacer(0)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if d := client.Pacer.Delay(); d < 29*time.Second {
+		t.Errorf("expected the exhausted quota observed, got Delay() = %v", d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := httpretry.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the paced request to time out, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected the paced request not sent, got %d calls", calls.Load())
+	}
+}
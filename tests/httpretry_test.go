@@ -0,0 +1,302 @@
+package retrier_test
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/retrier/httpretry"
+)
+
+// TestParseRateLimit tests the supported rate limit header conventions.
+func TestParseRateLimit(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name          string
+		headers       map[string]string
+		wantOK        bool
+		wantLimit     int
+		wantRemaining int
+		wantReset     time.Time
+	}{
+		{
+			name:          "GitHub style epoch seconds",
+			headers:       map[string]string{"X-RateLimit-Limit": "60", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700000030"},
+			wantOK:        true,
+			wantLimit:     60,
+			wantRemaining: 0,
+			wantReset:     now.Add(30 * time.Second),
+		},
+		{
+			name:          "IETF draft delta seconds",
+			headers:       map[string]string{"RateLimit-Limit": "100, 100;w=60", "RateLimit-Remaining": "5", "RateLimit-Reset": "12"},
+			wantOK:        true,
+			wantLimit:     100,
+			wantRemaining: 5,
+			wantReset:     now.Add(12 * time.Second),
+		},
+		{
+			name:          "Discord reset-after",
+			headers:       map[string]string{"X-RateLimit-Remaining": "1", "X-RateLimit-Reset": "1700009999", "X-RateLimit-Reset-After": "1.5"},
+			wantOK:        true,
+			wantLimit:     -1,
+			wantRemaining: 1,
+			wantReset:     now.Add(1500 * time.Millisecond),
+		},
+		{
+			name:          "Twitter style",
+			headers:       map[string]string{"X-Rate-Limit-Remaining": "3", "X-Rate-Limit-Reset": "1700000060"},
+			wantOK:        true,
+			wantLimit:     -1,
+			wantRemaining: 3,
+			wantReset:     now.Add(time.Minute),
+		},
+		{
+			name:          "epoch milliseconds",
+			headers:       map[string]string{"X-RateLimit-Remaining": "3", "X-RateLimit-Reset": "1700000001000"},
+			wantOK:        true,
+			wantLimit:     -1,
+			wantRemaining: 3,
+			wantReset:     now.Add(time.Second),
+		},
+		{
+			name:    "no rate limit headers",
+			headers: map[string]string{"Content-Type": "text/plain"},
+			wantOK:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+			rl, ok := httpretry.ParseRateLimit(h, now)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseRateLimit() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if rl.Limit != tt.wantLimit {
+				t.Errorf("Limit = %d, want %d", rl.Limit, tt.wantLimit)
+			}
+			if rl.Remaining != tt.wantRemaining {
+				t.Errorf("Remaining = %d, want %d", rl.Remaining, tt.wantRemaining)
+			}
+			if !rl.Reset.Equal(tt.wantReset) {
+				t.Errorf("Reset = %v, want %v", rl.Reset, tt.wantReset)
+			}
+		})
+	}
+}
+
+// TestParseRetryAfter tests delta-seconds and HTTP-date formats.
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"delta seconds", "120", 2 * time.Minute, true},
+		{"http date", "Mon, 01 Jan 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"date in the past", "Mon, 01 Jan 2024 11:00:00 GMT", 0, true},
+		{"empty", "", 0, false},
+		{"negative", "-5", 0, false},
+		{"garbage", "soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := httpretry.ParseRetryAfter(tt.value, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ParseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestPacer_WaitsForExhaustedQuota verifies that an exhausted quota delays the next request until reset.
+func TestPacer_WaitsForExhaustedQuota(t *testing.T) {
+	pacer := httpretry.NewPacer(0)
+	h := http.Header{}
+	h.Set("RateLimit-Remaining", "0")
+	h.Set("RateLimit-Reset", "10")
+	pacer.Observe(h)
+
+	if d := pacer.Delay(); d < 9*time.Second || d > 10*time.Second {
+		t.Errorf("Delay() = %v, want about 10s", d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := pacer.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait() = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+// TestPacer_SpreadsLowQuota verifies that requests are spread once the quota drops below the reserve.
+func TestPacer_SpreadsLowQuota(t *testing.T) {
+	pacer := httpretry.NewPacer(10)
+	h := http.Header{}
+	h.Set("RateLimit-Remaining", "3")
+	h.Set("RateLimit-Reset", "8")
+	pacer.Observe(h)
+
+	// 8s spread over the 3 remaining requests plus the reset
+	if d := pacer.Delay(); d < 1900*time.Millisecond || d > 2*time.Second {
+		t.Errorf("Delay() = %v, want about 2s", d)
+	}
+
+	// A late response with more quota left does not bring the next request forward
+	h.Set("RateLimit-Remaining", "50")
+	pacer.Observe(h)
+	if d := pacer.Delay(); d < 1900*time.Millisecond {
+		t.Errorf("Delay() = %v, want about 2s", d)
+	}
+}
+
+// TestPacer_KeepsExhaustedQuota verifies that responses without rate limit headers do not
+// erase a quota known to be exhausted.
+func TestPacer_KeepsExhaustedQuota(t *testing.T) {
+	pacer := httpretry.NewPacer(0)
+	h := http.Header{}
+	h.Set("RateLimit-Remaining", "0")
+	h.Set("RateLimit-Reset", "10")
+	pacer.Observe(h)
+	pacer.Observe(http.Header{})
+
+	if d := pacer.Delay(); d < 9*time.Second {
+		t.Errorf("Delay() = %v, want about 10s", d)
+	}
+}
+
+// TestPacer_ReservesSlots verifies that concurrent Waits are spread over the low quota
+// instead of all returning together.
+func TestPacer_ReservesSlots(t *testing.T) {
+	pacer := httpretry.NewPacer(10)
+	h := http.Header{}
+	h.Set("RateLimit-Remaining", "3")
+	h.Set("RateLimit-Reset", "1")
+	pacer.Observe(h)
+
+	// 1s spread over the 3 remaining requests plus the reset: 250ms apart
+	start := time.Now()
+	var wg sync.WaitGroup
+	waited := make([]time.Duration, 3)
+	for i := range waited {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := pacer.Wait(context.Background()); err != nil {
+				t.Errorf("Wait() = %v, want nil", err)
+			}
+			waited[i] = time.Since(start)
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(waited)
+	if spread := waited[2] - waited[0]; spread < 400*time.Millisecond {
+		t.Errorf("expected the waits spread about 500ms apart, got %v", waited)
+	}
+}
+
+// TestPacer_RetryAfter verifies that Retry-After is honored.
+func TestPacer_RetryAfter(t *testing.T) {
+	pacer := httpretry.NewPacer(0)
+	h := http.Header{}
+	h.Set("Retry-After", "5")
+	pacer.Observe(h)
+
+	if d := pacer.Delay(); d < 4*time.Second || d > 5*time.Second {
+		t.Errorf("Delay() = %v, want about 5s", d)
+	}
+}
+
+// TestPacer_NoHeaders verifies that responses without rate limit headers do not delay requests.
+func TestPacer_NoHeaders(t *testing.T) {
+	pacer := httpretry.NewPacer(10)
+	pacer.Observe(http.Header{})
+
+	if d := pacer.Delay(); d != 0 {
+		t.Errorf("Delay() = %v, want 0", d)
+	}
+	if err := pacer.Wait(context.Background()); err != nil {
+		t.Errorf("Wait() = %v, want nil", err)
+	}
+}
+
+// TestParseErrorBody tests retry hint extraction from JSON error bodies.
+func TestParseErrorBody(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantOK      bool
+		want        httpretry.ErrorHint
+	}{
+		{
+			name:        "problem details with extensions",
+			contentType: "application/problem+json",
+			body:        `{"type": "about:blank", "title": "Busy", "status": 503, "retryable": true, "retry_after": 2}`,
+			wantOK:      true,
+			want:        httpretry.ErrorHint{Retryability: httpretry.RetryabilityTransient, RetryAfter: 2 * time.Second},
+		},
+		{
+			name:        "permanent error envelope",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"error": {"message": "quota deleted", "transient": false}}`,
+			wantOK:      true,
+			want:        httpretry.ErrorHint{Retryability: httpretry.RetryabilityPermanent},
+		},
+		{
+			name:        "google api error with retry info",
+			contentType: "application/json",
+			body:        `{"error": {"code": 503, "status": "UNAVAILABLE", "details": [{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "1.5s"}]}}`,
+			wantOK:      true,
+			want:        httpretry.ErrorHint{Retryability: httpretry.RetryabilityTransient, RetryAfter: 1500 * time.Millisecond},
+		},
+		{
+			name:        "retry after as http date",
+			contentType: "application/json",
+			body:        `{"retryAfter": "` + now.Add(30*time.Second).UTC().Format(http.TimeFormat) + `"}`,
+			wantOK:      true,
+			want:        httpretry.ErrorHint{RetryAfter: 30 * time.Second},
+		},
+		{
+			name:        "no hints",
+			contentType: "application/json",
+			body:        `{"error": {"message": "not found"}}`,
+		},
+		{
+			name:        "not json",
+			contentType: "text/html",
+			body:        `{"retryable": true}`,
+		},
+		{
+			name:        "malformed json",
+			contentType: "application/json",
+			body:        `{"retryable":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := httpretry.ParseErrorBody(tt.contentType, []byte(tt.body), now)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ParseErrorBody() = %+v, %v; want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/httpretry"
+)
+
+// throttledError is a downstream error suggesting a delay.
+type throttledError struct{ delay time.Duration }
+
+func (e throttledError) Error() string                 { return "throttled" }
+func (e throttledError) SuggestedDelay() time.Duration { return e.delay }
+
+// serveUnavailable serves one request with a handler failing with err.
+func serveUnavailable(u *httpretry.Unavailable, err error) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	u.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return err
+	}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec
+}
+
+// exhaust runs a single-attempt retry loop failing with err.
+func exhaust(err error) error {
+	return retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		return 0, err
+	}, retrier.WithMaxAttempts(1)).Err()
+}
+
+// TestUnavailable_Backoff verifies that exhausted loops are answered with 503
+// and the delay the loop would wait after its attempts: 2s, 4s, then 8s.
+func TestUnavailable_Backoff(t *testing.T) {
+	u := &httpretry.Unavailable{Options: []retrier.RetryOption{
+		retrier.WithInitialDuration(2 * time.Second), retrier.WithMultiplier(2),
+	}}
+	err := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		return 0, errors.New("connection refused")
+	}, retrier.WithMaxAttempts(3), retrier.WithInitialDuration(time.Millisecond)).Err()
+	rec := serveUnavailable(u, err)
+
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "8" {
+		t.Errorf("expected 503 with Retry-After 8, got %d with %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
+
+// TestUnavailable_SuggestedDelay verifies that a delay suggested by the
+// dependency takes precedence, within the bounds of Retry-After.
+func TestUnavailable_SuggestedDelay(t *testing.T) {
+	tests := []struct {
+		name  string
+		delay time.Duration
+		want  string
+	}{
+		{"rounded up", 2500 * time.Millisecond, "3"},
+		{"raised to the minimum", 10 * time.Millisecond, "1"},
+		{"lowered to the maximum", time.Hour, "300"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveUnavailable(&httpretry.Unavailable{}, exhaust(throttledError{tt.delay}))
+			if got := rec.Header().Get("Retry-After"); rec.Code != http.StatusServiceUnavailable || got != tt.want {
+				t.Errorf("expected 503 with Retry-After %s, got %d with %q", tt.want, rec.Code, got)
+			}
+		})
+	}
+}
+
+// TestUnavailable_OtherErrors verifies that errors other than retryable
+// failures of a retry loop go to OnError.
+func TestUnavailable_OtherErrors(t *testing.T) {
+	rec := serveUnavailable(&httpretry.Unavailable{}, exhaust(retrier.Permanent(errors.New("not found"))))
+	if rec.Code != http.StatusInternalServerError || rec.Header().Get("Retry-After") != "" {
+		t.Errorf("expected 500 without Retry-After, got %d with %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	var handled error
+	u := &httpretry.Unavailable{OnError: func(w http.ResponseWriter, r *http.Request, err error) {
+		handled = err
+		w.WriteHeader(http.StatusBadRequest)
+	}}
+	invalid := errors.New("invalid query")
+	if rec := serveUnavailable(u, invalid); rec.Code != http.StatusBadRequest || handled != invalid {
+		t.Errorf("expected OnError to handle the error, got %d and %v", rec.Code, handled)
+	}
+}
@@ -0,0 +1,95 @@
+package retrier_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rohmanhakim/retrier/httpretry"
+)
+
+// TestIdempotencyPolicy_Retryable verifies the default rules, idempotency
+// keys, and per-route rules.
+func TestIdempotencyPolicy_Retryable(t *testing.T) {
+	policy := httpretry.NewIdempotencyPolicy().
+		Allow(http.MethodPost, "/v1/search").
+		Deny("*", "/v1/jobs/*/run")
+
+	tests := []struct {
+		method string
+		path   string
+		key    string
+		want   bool
+	}{
+		{http.MethodGet, "/v1/users", "", true},
+		{http.MethodPut, "/v1/users/1", "", true},
+		{http.MethodDelete, "/v1/users/1", "", true},
+		{http.MethodPost, "/v1/users", "", false},
+		{http.MethodPatch, "/v1/users/1", "", false},
+		{http.MethodPost, "/v1/users", "a1b2", true},
+		{http.MethodPost, "/v1/search", "", true},
+		{http.MethodPost, "/v1/search/more", "", false},
+		{http.MethodPut, "/v1/jobs/7/run", "", false},
+		{http.MethodPost, "/v1/jobs/7/run", "a1b2", false},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, "http://example.com"+tt.path, nil)
+		if tt.key != "" {
+			req.Header.Set("Idempotency-Key", tt.key)
+		}
+		if got := policy.Retryable(req); got != tt.want {
+			t.Errorf("Retryable(%s %s, key %q) = %t, want %t", tt.method, tt.path, tt.key, got, tt.want)
+		}
+	}
+}
+
+// TestClient_Idempotency verifies that the client does not retry requests
+// its IdempotencyPolicy rejects, and retries those it allows.
+func TestClient_Idempotency(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := newTestClient(2)
+	client.Idempotency = httpretry.NewIdempotencyPolicy()
+
+	resp, err := client.Post(server.URL+"/orders", "application/json", `{"item": 1}`)
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Errorf("expected the 503 of a single POST, got %d after %d calls", resp.StatusCode, calls.Load())
+	}
+
+	calls.Store(0)
+	req, _ := httpretry.NewRequest(http.MethodPost, server.URL+"/orders", `{"item": 1}`)
+	req.Header.Set("Idempotency-Key", "order-1")
+	if _, err := client.Do(req); err == nil || calls.Load() != 3 {
+		t.Errorf("expected a keyed POST to be retried until giving up, got %v after %d calls", err, calls.Load())
+	}
+}
+
+// TestClient_Idempotency_TransportError verifies that transport errors of
+// non-idempotent requests report ErrNotIdempotent.
+func TestClient_Idempotency_TransportError(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(2)
+	client.Idempotency = httpretry.NewIdempotencyPolicy()
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return nil, errors.New("connection reset")
+	})}
+
+	_, err := client.Post("http://example.invalid/orders", "text/plain", "x")
+	if !errors.Is(err, httpretry.ErrNotIdempotent) || calls.Load() != 1 {
+		t.Errorf("expected ErrNotIdempotent after 1 call, got %v after %d", err, calls.Load())
+	}
+}
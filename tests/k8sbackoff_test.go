@@ -0,0 +1,106 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/k8sbackoff"
+)
+
+// TestK8sBackoff_ToWaitBackoff tests conversion to the wait.Backoff shape.
+func TestK8sBackoff_ToWaitBackoff(t *testing.T) {
+	got := k8sbackoff.ToWaitBackoff(
+		retrier.WithMaxAttempts(5),
+		retrier.WithInitialDuration(200*time.Millisecond),
+		retrier.WithMultiplier(3),
+		retrier.WithMaxDuration(10*time.Second),
+		retrier.WithJitter(100*time.Millisecond),
+	)
+	want := k8sbackoff.WaitBackoff{
+		Duration: 200 * time.Millisecond,
+		Factor:   3,
+		Jitter:   0.5,
+		Steps:    5,
+		Cap:      10 * time.Second,
+	}
+	if got != want {
+		t.Errorf("ToWaitBackoff() = %+v, want %+v", got, want)
+	}
+}
+
+// TestK8sBackoff_FromWaitBackoff tests conversion from the wait.Backoff shape.
+func TestK8sBackoff_FromWaitBackoff(t *testing.T) {
+	got := retrier.ResolveOptions(k8sbackoff.FromWaitBackoff(k8sbackoff.WaitBackoff{
+		Duration: 10 * time.Millisecond,
+		Factor:   1.5,
+		Jitter:   0.1,
+		Steps:    4,
+		Cap:      time.Second,
+	})...)
+
+	if got.MaxAttempts != 4 || got.InitialDuration != 10*time.Millisecond || got.Multiplier != 1.5 ||
+		got.MaxDuration != time.Second || got.Jitter != time.Millisecond {
+		t.Errorf("FromWaitBackoff() resolved to %+v", got)
+	}
+}
+
+// TestK8sBackoff_FromWaitBackoffZeroValues tests the handling of unset fields.
+func TestK8sBackoff_FromWaitBackoffZeroValues(t *testing.T) {
+	got := retrier.ResolveOptions(k8sbackoff.FromWaitBackoff(k8sbackoff.WaitBackoff{
+		Duration: time.Second,
+		Steps:    3,
+	})...)
+
+	if got.Multiplier != 1 {
+		t.Errorf("Multiplier = %v, want 1 for a zero Factor", got.Multiplier)
+	}
+	if got.MaxDuration != time.Minute {
+		t.Errorf("MaxDuration = %v, want the default for a zero Cap", got.MaxDuration)
+	}
+}
+
+// TestK8sBackoff_BackoffManager tests per-host failure tracking.
+func TestK8sBackoff_BackoffManager(t *testing.T) {
+	m := k8sbackoff.NewBackoffManager(
+		retrier.WithInitialDuration(100*time.Millisecond),
+		retrier.WithMultiplier(2),
+		retrier.WithMaxDuration(time.Second),
+	)
+	apiA, _ := url.Parse("https://a.example.com/api/v1/pods")
+	apiB, _ := url.Parse("https://b.example.com/api/v1/pods")
+
+	if d := m.CalculateBackoff(apiA); d != 0 {
+		t.Errorf("initial backoff = %v, want 0", d)
+	}
+
+	m.UpdateBackoff(apiA, errors.New("connection refused"), 0)
+	m.UpdateBackoff(apiA, nil, 503)
+	if d := m.CalculateBackoff(apiA); d != 200*time.Millisecond {
+		t.Errorf("backoff after 2 failures = %v, want 200ms", d)
+	}
+	if d := m.CalculateBackoff(apiB); d != 0 {
+		t.Errorf("other host backoff = %v, want 0", d)
+	}
+
+	m.UpdateBackoff(apiA, nil, 200)
+	if d := m.CalculateBackoffWithContext(context.Background(), apiA); d != 0 {
+		t.Errorf("backoff after success = %v, want 0", d)
+	}
+}
+
+// TestK8sBackoff_SleepWithContext verifies that sleeping stops on cancellation.
+func TestK8sBackoff_SleepWithContext(t *testing.T) {
+	m := k8sbackoff.NewBackoffManager()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	m.SleepWithContext(ctx, time.Minute)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("SleepWithContext ignored cancellation, took %v", elapsed)
+	}
+}
@@ -0,0 +1,74 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestKeyedRetriers_PerKey tests that each key gets its own Retrier with its
+// own options and stats.
+func TestKeyedRetriers_PerKey(t *testing.T) {
+	var created []string
+	keyed := retrier.NewKeyedRetriers(noopLogger, 0, func(key string) []retrier.RetryOption {
+		created = append(created, key)
+		attempts := 2
+		if key == "b" {
+			attempts = 4
+		}
+		return append(defaultTestOpts(), retrier.WithMaxAttempts(attempts))
+	})
+
+	a := keyed.Get("a")
+	if keyed.Get("a") != a {
+		t.Error("expected the same Retrier for the same key")
+	}
+	b := keyed.Get("b")
+	if a == b {
+		t.Fatal("expected different Retriers for different keys")
+	}
+
+	fail := func() (int, error) { return 0, errors.New("down") }
+	if got := retrier.Do(context.Background(), a, fail).Attempts(); got != 2 {
+		t.Errorf("key a attempts = %d, want 2", got)
+	}
+	if got := retrier.Do(context.Background(), b, fail).Attempts(); got != 4 {
+		t.Errorf("key b attempts = %d, want 4", got)
+	}
+	if a.Stats().Calls != 1 || b.Stats().Calls != 1 {
+		t.Errorf("expected one call per Retrier, got %d and %d", a.Stats().Calls, b.Stats().Calls)
+	}
+	if !reflect.DeepEqual(created, []string{"a", "b"}) {
+		t.Errorf("created = %v, want [a b]", created)
+	}
+	if got := keyed.Keys(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Keys() = %v, want [a b]", got)
+	}
+
+	keyed.Remove("a")
+	if keyed.Get("a") == a {
+		t.Error("expected a new Retrier after Remove")
+	}
+}
+
+// TestKeyedRetriers_EvictIdle tests that idle Retriers are dropped.
+func TestKeyedRetriers_EvictIdle(t *testing.T) {
+	keyed := retrier.NewKeyedRetriers(noopLogger, 20*time.Millisecond, func(string) []retrier.RetryOption {
+		return nil
+	})
+
+	idle := keyed.Get("idle")
+	time.Sleep(30 * time.Millisecond)
+	keyed.Get("busy")
+
+	if got := keyed.Keys(); !reflect.DeepEqual(got, []string{"busy"}) {
+		t.Errorf("Keys() = %v, want [busy]", got)
+	}
+	if keyed.Get("idle") == idle {
+		t.Error("expected a new Retrier for an evicted key")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/retriertest"
 )
 
 // TestNoOpLogger_Enabled tests that NoOpLogger.Enabled returns false.
@@ -105,3 +106,40 @@ func TestWithLogAttrs_EmptyAttrs(t *testing.T) {
 		t.Errorf("expected 0 attrs, got %d", len(mock.logRetryCalls[0].attrs))
 	}
 }
+
+// timeLogger records the LogTime of each LogRetry call.
+type timeLogger struct {
+	times []time.Time
+}
+
+func (l *timeLogger) Enabled() bool { return true }
+
+func (l *timeLogger) LogRetry(ctx context.Context, _ int, _ int, _ time.Duration, _ error, _ ...any) {
+	l.times = append(l.times, retrier.LogTime(ctx))
+}
+
+// TestLogTime verifies that log calls are timestamped with the Clock of the
+// loop, and LogTime falls back to the current time elsewhere.
+func TestLogTime(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := retriertest.NewFakeClock(start)
+	logger := &timeLogger{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		retrier.Retry(context.Background(), logger, func() (int, error) {
+			return 0, errors.New("down")
+		}, retrier.WithMaxAttempts(2), retrier.WithInitialDuration(time.Hour), retrier.WithClock(clock))
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	<-done
+
+	want := []time.Time{start, start.Add(time.Hour)}
+	if len(logger.times) != len(want) || !logger.times[0].Equal(want[0]) || !logger.times[1].Equal(want[1]) {
+		t.Errorf("expected log times %v, got %v", want, logger.times)
+	}
+	if got := retrier.LogTime(context.Background()); time.Since(got) > time.Minute {
+		t.Errorf("expected the current time outside log calls, got %v", got)
+	}
+}
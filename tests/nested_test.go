@@ -0,0 +1,91 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestRetryNested_Success verifies that attempts are counted per level when
+// the second endpoint succeeds.
+func TestRetryNested_Success(t *testing.T) {
+	endpoints := []string{"eu", "us"}
+	down := errors.New("eu down")
+	var calls []string
+
+	result := retrier.RetryNested(context.Background(), noopLogger,
+		append(defaultTestOpts(), retrier.WithMaxAttempts(2)),
+		append(defaultTestOpts(), retrier.WithMaxAttempts(3)),
+		func(_ context.Context, outerAttempt int) (string, error) {
+			endpoint := endpoints[outerAttempt-1]
+			calls = append(calls, endpoint)
+			if endpoint == "eu" {
+				return "", down
+			}
+			return "ok from " + endpoint, nil
+		})
+
+	if result.Value() != "ok from us" {
+		t.Fatalf("Value() = %q, err %v", result.Value(), result.Err())
+	}
+	if result.Attempts() != 2 || result.TotalAttempts() != 4 {
+		t.Errorf("got %d attempts and %d in total, want 2 and 4", result.Attempts(), result.TotalAttempts())
+	}
+	history := result.History()
+	if len(history) != 1 || history[0].Attempt != 1 || history[0].Err != down {
+		t.Fatalf("expected outer attempt 1 to fail with the inner error, got %+v", history)
+	}
+	if len(history[0].Inner) != 3 || history[0].Inner[2].Attempt != 3 {
+		t.Errorf("expected 3 inner attempts under outer attempt 1, got %+v", history[0].Inner)
+	}
+	if fmt.Sprint(calls) != "[eu eu eu us]" {
+		t.Errorf("calls = %v", calls)
+	}
+}
+
+// TestRetryNested_Exhausted verifies that the final error wraps the error of
+// the last inner attempt once, with the inner histories attached.
+func TestRetryNested_Exhausted(t *testing.T) {
+	boom := errors.New("boom")
+	result := retrier.RetryNested(context.Background(), noopLogger,
+		append(defaultTestOpts(), retrier.WithMaxAttempts(2)),
+		append(defaultTestOpts(), retrier.WithMaxAttempts(2)),
+		func(context.Context, int) (int, error) {
+			return 0, boom
+		})
+
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrExhaustedAttempts {
+		t.Fatalf("expected ErrExhaustedAttempts, got %v", result.Err())
+	}
+	if errors.Unwrap(retryErr) != boom {
+		t.Errorf("expected the final error to wrap the attempt error directly, got %v", errors.Unwrap(retryErr))
+	}
+	if result.Attempts() != 2 || result.TotalAttempts() != 4 {
+		t.Errorf("got %d attempts and %d in total, want 2 and 4", result.Attempts(), result.TotalAttempts())
+	}
+	for _, h := range retryErr.History() {
+		if len(h.Inner) != 2 {
+			t.Errorf("outer attempt %d: expected 2 inner attempts, got %d", h.Attempt, len(h.Inner))
+		}
+	}
+}
+
+// TestRetryNested_Permanent verifies that a permanent error stops both loops.
+func TestRetryNested_Permanent(t *testing.T) {
+	calls := 0
+	result := retrier.RetryNested(context.Background(), noopLogger,
+		append(defaultTestOpts(), retrier.WithMaxAttempts(3)),
+		append(defaultTestOpts(), retrier.WithMaxAttempts(3)),
+		func(context.Context, int) (int, error) {
+			calls++
+			return 0, retrier.Permanent(errors.New("bad request"))
+		})
+
+	if !retrier.IsPermanent(result.Err()) || calls != 1 || result.TotalAttempts() != 1 {
+		t.Errorf("expected a permanent failure after 1 call, got %v after %d calls", result.Err(), calls)
+	}
+}
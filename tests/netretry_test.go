@@ -0,0 +1,203 @@
+package retrier_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/netretry"
+)
+
+// timeoutError is a net.Error timing out with msg.
+type timeoutError struct{ msg string }
+
+func (e timeoutError) Error() string   { return e.msg }
+func (e timeoutError) Timeout() bool   { return true }
+func (e timeoutError) Temporary() bool { return true }
+
+// TestNetRetry_TLSRules tests the default TLS classification.
+func TestNetRetry_TLSRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   retrier.RetryPolicy
+		wantOK bool
+	}{
+		{"unknown authority", fmt.Errorf("dial: %w", &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}), retrier.RetryPolicyNever, true},
+		{"hostname mismatch", x509.HostnameError{Host: "example.com", Certificate: &x509.Certificate{}}, retrier.RetryPolicyNever, true},
+		{"expired", x509.CertificateInvalidError{Reason: x509.Expired, Cert: &x509.Certificate{}}, retrier.RetryPolicyNever, true},
+		{"plaintext port", tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}, retrier.RetryPolicyNever, true},
+		{"handshake timeout", timeoutError{"net/http: TLS handshake timeout"}, retrier.RetryPolicyAuto, true},
+		{"remote internal error", &net.OpError{Op: "remote error", Err: errors.New(tls.AlertError(80).Error())}, retrier.RetryPolicyAuto, true},
+		{"remote handshake failure", &net.OpError{Op: "remote error", Err: errors.New(tls.AlertError(40).Error())}, retrier.RetryPolicyNever, true},
+		{"quic alert", fmt.Errorf("quic: %w", tls.AlertError(90)), retrier.RetryPolicyAuto, true},
+		{"other timeout", timeoutError{"i/o timeout"}, 0, false},
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := netretry.Classify(tt.err, netretry.TLSRules()...)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Classify() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestNetRetry_RetryIf tests customized rules and the fallback to IsTransient.
+func TestNetRetry_RetryIf(t *testing.T) {
+	handshakeFailure := &net.OpError{Op: "remote error", Err: errors.New(tls.AlertError(40).Error())}
+
+	if netretry.RetryIf(netretry.TLSRules()...)(handshakeFailure) {
+		t.Error("expected handshake_failure not to be retried by default")
+	}
+	retryIf := netretry.RetryIf(netretry.CertificateRule, netretry.AlertRule(40))
+	if !retryIf(handshakeFailure) {
+		t.Error("expected handshake_failure to be retried when listed as transient")
+	}
+	if !retryIf(errors.New("connection reset")) || retryIf(retrier.Permanent(errors.New("bad"))) {
+		t.Error("expected unrecognized errors to follow IsTransient")
+	}
+}
+
+// TestClient_DoesNotRetryCertificateErrors verifies that httpretry gives up on
+// certificate validation failures after one attempt.
+func TestClient_DoesNotRetryCertificateErrors(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	client := newTestClient(3)
+	_, err := client.Get(server.URL)
+	var verifyErr *tls.CertificateVerificationError
+	if !errors.As(err, &verifyErr) {
+		t.Fatalf("expected a certificate verification error, got %v", err)
+	}
+	if conns.Load() != 1 {
+		t.Errorf("expected 1 connection attempt, got %d", conns.Load())
+	}
+}
+
+// fixedResolver resolves every host to addrs.
+type fixedResolver []netip.Addr
+
+func (r fixedResolver) LookupNetIP(context.Context, string, string) ([]netip.Addr, error) {
+	return append([]netip.Addr(nil), r...), nil
+}
+
+// acceptOn listens on ip at port (0 for any) and counts accepted connections.
+func acceptOn(t *testing.T, ip string, port int) (net.Listener, *atomic.Int32) {
+	t.Helper()
+	l, err := net.Listen("tcp", net.JoinHostPort(ip, strconv.Itoa(port)))
+	if err != nil {
+		t.Skipf("cannot listen on %s: %v", ip, err)
+	}
+	var accepted atomic.Int32
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			conn.Close()
+		}
+	}()
+	return l, &accepted
+}
+
+// TestNetRetry_Dialer_Rotate tests rotating across resolved addresses.
+func TestNetRetry_Dialer_Rotate(t *testing.T) {
+	first, firstAccepted := acceptOn(t, "127.0.0.1", 0)
+	defer first.Close()
+	port := first.Addr().(*net.TCPAddr).Port
+	second, secondAccepted := acceptOn(t, "127.0.0.2", port)
+
+	dialer := &netretry.Dialer{Resolver: fixedResolver{netip.MustParseAddr("127.0.0.2"), netip.MustParseAddr("127.0.0.1")}}
+	address := net.JoinHostPort("service.test", strconv.Itoa(port))
+	dial := func() {
+		t.Helper()
+		conn, err := dialer.DialContext(context.Background(), "tcp", address)
+		if err != nil {
+			t.Fatalf("DialContext() error = %v", err)
+		}
+		conn.Close()
+	}
+	waitAccepted := func(counter *atomic.Int32, want int32) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for counter.Load() < want && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if got := counter.Load(); got != want {
+			t.Errorf("expected %d accepted connections, got %d", want, got)
+		}
+	}
+
+	// Addresses are dialed in sorted order
+	dial()
+	waitAccepted(firstAccepted, 1)
+
+	dialer.Rotate("service.test")
+	dial()
+	waitAccepted(secondAccepted, 1)
+
+	// A dead address is skipped, and later dials stay on the live one
+	second.Close()
+	dial()
+	dial()
+	waitAccepted(firstAccepted, 3)
+}
+
+// TestClient_ReResolve verifies that an attempt after a transport error dials a
+// new connection through the rotating dialer.
+func TestClient_ReResolve(t *testing.T) {
+	var calls, conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := newTestClient(2)
+	client.ReResolve = true
+	client.RotateAddresses = true
+	resp, err := client.Post(server.URL, "text/plain", "payload")
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 2 || conns.Load() != 2 {
+		t.Errorf("expected 2 calls on 2 connections, got %d calls on %d", calls.Load(), conns.Load())
+	}
+}
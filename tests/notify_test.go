@@ -0,0 +1,219 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestWithNotifyChannel verifies the events of a loop that succeeds on its third attempt.
+func TestWithNotifyChannel(t *testing.T) {
+	events := make(chan retrier.RetryEvent, 10)
+	boom := errors.New("boom")
+	calls := 0
+	opts := append(defaultTestOpts(), retrier.WithNotifyChannel(events))
+	retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, boom
+		}
+		return 42, nil
+	}, opts...)
+	close(events)
+
+	var got []retrier.RetryEvent
+	for e := range events {
+		got = append(got, e)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 events, got %d: %+v", len(got), got)
+	}
+	for i, e := range got[:2] {
+		if e.Kind != retrier.EventRetry || e.Attempt != i+1 || e.Err != boom || e.Backoff <= 0 || e.MaxAttempts != 3 || e.Time.IsZero() {
+			t.Errorf("event %d: unexpected %+v", i, e)
+		}
+	}
+	if last := got[2]; last.Kind != retrier.EventSuccess || last.Attempt != 3 || last.Err != nil {
+		t.Errorf("unexpected outcome event %+v", last)
+	}
+}
+
+// TestWithNotifyChannel_Failure verifies the outcome event of a failed loop.
+func TestWithNotifyChannel_Failure(t *testing.T) {
+	events := make(chan retrier.RetryEvent, 10)
+	opts := append(defaultTestOpts(), retrier.WithMaxAttempts(2), retrier.WithNotifyChannel(events))
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		return 0, errors.New("boom")
+	}, opts...)
+	close(events)
+
+	var last retrier.RetryEvent
+	count := 0
+	for e := range events {
+		last = e
+		count++
+	}
+	if count != 2 || last.Kind != retrier.EventFailure || last.Attempt != 2 || last.Err.Error() != result.Err().Error() {
+		t.Errorf("expected a failure outcome after 2 events, got %d events ending with %+v", count, last)
+	}
+}
+
+// TestWithNotifyChannel_Drop verifies that a full channel does not block the loop.
+func TestWithNotifyChannel_Drop(t *testing.T) {
+	events := make(chan retrier.RetryEvent)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		opts := append(defaultTestOpts(), retrier.WithNotifyChannel(events))
+		retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+			return 0, errors.New("boom")
+		}, opts...)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Retry blocked on a full notify channel")
+	}
+}
+
+// TestWithNotifyMode_Block verifies that blocking mode delivers every event,
+// and gives up when the context is done.
+func TestWithNotifyMode_Block(t *testing.T) {
+	events := make(chan retrier.RetryEvent)
+	received := make(chan []retrier.EventKind)
+	go func() {
+		var kinds []retrier.EventKind
+		for e := range events {
+			time.Sleep(5 * time.Millisecond) // slow consumer
+			kinds = append(kinds, e.Kind)
+		}
+		received <- kinds
+	}()
+
+	opts := append(defaultTestOpts(), retrier.WithNotifyChannel(events), retrier.WithNotifyMode(retrier.NotifyBlock))
+	retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		return 0, errors.New("boom")
+	}, opts...)
+	close(events)
+
+	kinds := <-received
+	want := []retrier.EventKind{retrier.EventRetry, retrier.EventRetry, retrier.EventFailure}
+	if len(kinds) != len(want) {
+		t.Fatalf("expected %v, got %v", want, kinds)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("event %d: expected %v, got %v", i, want[i], kinds[i])
+		}
+	}
+
+	// Nobody reads: the cancelled context unblocks the loop
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	blocked := make(chan retrier.RetryEvent)
+	opts = append(defaultTestOpts(), retrier.WithNotifyChannel(blocked), retrier.WithNotifyMode(retrier.NotifyBlock))
+	result := retrier.Retry(ctx, noopLogger, func() (int, error) {
+		return 0, errors.New("boom")
+	}, opts...)
+	if !errors.Is(result.Err(), context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to end the loop, got %v", result.Err())
+	}
+}
+
+// warningLogger records warnings.
+type warningLogger struct {
+	retrier.NoOpLogger
+	warnings []string
+	attrs    [][]any
+}
+
+func (l *warningLogger) LogWarning(_ context.Context, msg string, attrs ...any) {
+	l.warnings = append(l.warnings, msg)
+	l.attrs = append(l.attrs, attrs)
+}
+
+// TestWithSoftMaxAttempts verifies that exceeding the soft limit warns once
+// without stopping the loop.
+func TestWithSoftMaxAttempts(t *testing.T) {
+	events := make(chan retrier.RetryEvent, 10)
+	logger := &warningLogger{}
+	calls := 0
+	opts := append(defaultTestOpts(),
+		retrier.WithMaxAttempts(5),
+		retrier.WithSoftMaxAttempts(2),
+		retrier.WithNotifyChannel(events),
+	)
+	result := retrier.Retry(context.Background(), logger, func() (int, error) {
+		calls++
+		if calls < 4 {
+			return 0, errors.New("boom")
+		}
+		return 1, nil
+	}, opts...)
+	close(events)
+
+	if result.IsFailure() || result.Attempts() != 4 {
+		t.Fatalf("expected success on attempt 4, got %v after %d", result.Err(), result.Attempts())
+	}
+	var soft []retrier.RetryEvent
+	for e := range events {
+		if e.Kind == retrier.EventSoftLimitExceeded {
+			soft = append(soft, e)
+		}
+	}
+	if len(soft) != 1 || soft[0].Attempt != 2 {
+		t.Errorf("expected one soft limit event at attempt 2, got %+v", soft)
+	}
+	if len(logger.warnings) != 1 || logger.warnings[0] != "soft attempt limit exceeded" {
+		t.Errorf("expected one warning, got %v", logger.warnings)
+	}
+}
+
+// TestWithSoftMaxAttempts_NotReached verifies that no warning is emitted when
+// the loop ends within the soft limit or gives up at it.
+func TestWithSoftMaxAttempts_NotReached(t *testing.T) {
+	logger := &warningLogger{}
+	opts := append(defaultTestOpts(), retrier.WithSoftMaxAttempts(3))
+	retrier.Retry(context.Background(), logger, func() (int, error) {
+		return 0, errors.New("boom")
+	}, opts...)
+	if len(logger.warnings) != 0 {
+		t.Errorf("expected no warning when giving up at the soft limit, got %v", logger.warnings)
+	}
+}
+
+// TestWithNotifyChannel_RawBackoff verifies that retry events carry the
+// delay of the backoff curve before the cap.
+func TestWithNotifyChannel_RawBackoff(t *testing.T) {
+	events := make(chan retrier.RetryEvent, 10)
+	retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		return 0, errors.New("boom")
+	}, retrier.WithMaxAttempts(4), retrier.WithInitialDuration(time.Millisecond),
+		retrier.WithMultiplier(10), retrier.WithMaxDuration(5*time.Millisecond),
+		retrier.WithNotifyChannel(events))
+	close(events)
+
+	want := []struct{ backoff, raw time.Duration }{
+		{time.Millisecond, time.Millisecond},
+		{5 * time.Millisecond, 10 * time.Millisecond},
+		{5 * time.Millisecond, 100 * time.Millisecond},
+	}
+	var got []retrier.RetryEvent
+	for e := range events {
+		if e.Kind == retrier.EventRetry {
+			got = append(got, e)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d retry events, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		if got[i].Backoff != w.backoff || got[i].RawBackoff != w.raw {
+			t.Errorf("event %d: Backoff %v, RawBackoff %v, want %v and %v", i, got[i].Backoff, got[i].RawBackoff, w.backoff, w.raw)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestRegistry_WriteOpenMetrics verifies the OpenMetrics exposition of
+// registered Retriers.
+func TestRegistry_WriteOpenMetrics(t *testing.T) {
+	payments := retrier.NewRetrier(noopLogger,
+		retrier.WithMaxAttempts(3),
+		retrier.WithInitialDuration(0),
+		retrier.WithRetryGuard(retrier.NewRetryGuard(8)),
+	)
+	retrier.Do(context.Background(), payments, func() (string, error) { return "ok", nil })
+	retrier.Do(context.Background(), payments, func() (string, error) {
+		return "", errors.New("connection refused")
+	})
+
+	registry := retrier.NewRegistry()
+	registry.Register("payments", payments)
+	registry.Register(`say "hi"`, retrier.NewRetrier(noopLogger))
+
+	var b strings.Builder
+	if err := registry.WriteOpenMetrics(context.Background(), &b); err != nil {
+		t.Fatalf("WriteOpenMetrics() error = %v", err)
+	}
+
+	want := `# TYPE retrier_calls counter
+# HELP retrier_calls Calls that returned.
+retrier_calls_total{retrier="payments"} 2
+retrier_calls_total{retrier="say \"hi\""} 0
+# TYPE retrier_successes counter
+# HELP retrier_successes Calls that returned a value.
+retrier_successes_total{retrier="payments"} 1
+retrier_successes_total{retrier="say \"hi\""} 0
+# TYPE retrier_failures counter
+# HELP retrier_failures Calls that returned an error.
+retrier_failures_total{retrier="payments"} 1
+retrier_failures_total{retrier="say \"hi\""} 0
+# TYPE retrier_retries counter
+# HELP retrier_retries Attempts made beyond the first of each call.
+retrier_retries_total{retrier="payments"} 2
+retrier_retries_total{retrier="say \"hi\""} 0
+# TYPE retrier_errors counter
+# HELP retrier_errors Failed calls by error fingerprint.
+retrier_errors_total{retrier="payments",fingerprint="connection refused"} 1
+# TYPE retrier_attempts histogram
+# HELP retrier_attempts Attempts made per call.
+retrier_attempts_bucket{retrier="payments",le="1"} 1
+retrier_attempts_bucket{retrier="payments",le="2"} 1
+retrier_attempts_bucket{retrier="payments",le="3"} 2
+retrier_attempts_bucket{retrier="payments",le="5"} 2
+retrier_attempts_bucket{retrier="payments",le="10"} 2
+retrier_attempts_bucket{retrier="payments",le="+Inf"} 2
+retrier_attempts_count{retrier="payments"} 2
+retrier_attempts_sum{retrier="payments"} 4
+retrier_attempts_bucket{retrier="say \"hi\"",le="1"} 0
+retrier_attempts_bucket{retrier="say \"hi\"",le="2"} 0
+retrier_attempts_bucket{retrier="say \"hi\"",le="3"} 0
+retrier_attempts_bucket{retrier="say \"hi\"",le="5"} 0
+retrier_attempts_bucket{retrier="say \"hi\"",le="10"} 0
+retrier_attempts_bucket{retrier="say \"hi\"",le="+Inf"} 0
+retrier_attempts_count{retrier="say \"hi\""} 0
+retrier_attempts_sum{retrier="say \"hi\""} 0
+# TYPE retrier_guard_active gauge
+# HELP retrier_guard_active Operations currently retrying under the retry guard.
+retrier_guard_active{retrier="payments"} 0
+# TYPE retrier_guard_max gauge
+# HELP retrier_guard_max Operations allowed to retry concurrently by the retry guard.
+retrier_guard_max{retrier="payments"} 8
+# EOF
+`
+	if got := b.String(); got != want {
+		t.Errorf("unexpected exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// traceKey is the context key of the trace ID in exemplar tests.
+type traceKey struct{}
+
+// TestRetrier_Exemplars verifies that failing and retrying calls record the
+// trace of their context, exposed as OpenMetrics exemplars.
+func TestRetrier_Exemplars(t *testing.T) {
+	r := retrier.NewRetrier(noopLogger,
+		retrier.WithMaxAttempts(2),
+		retrier.WithInitialDuration(0),
+		retrier.WithExemplars(func(ctx context.Context) string {
+			id, _ := ctx.Value(traceKey{}).(string)
+			return id
+		}),
+	)
+	fail := func() (int, error) { return 0, errors.New("connection refused") }
+	retrier.Do(context.WithValue(context.Background(), traceKey{}, "4bf92f35"), r, fail)
+	retrier.Do(context.Background(), r, fail) // untraced calls keep the exemplar
+	retrier.Do(context.WithValue(context.Background(), traceKey{}, "00f067aa"), r, func() (int, error) { return 1, nil })
+
+	exemplars := r.Exemplars()
+	if exemplars.Failures == nil || exemplars.Failures.TraceID != "4bf92f35" || exemplars.Errors["connection refused"].TraceID != "4bf92f35" {
+		t.Errorf("expected the failing trace as failure exemplar, got %+v", exemplars)
+	}
+	if exemplars.Retries == nil || exemplars.Retries.TraceID != "4bf92f35" || exemplars.Retries.Value != 1 {
+		t.Errorf("expected the retrying trace as retry exemplar, got %+v", exemplars.Retries)
+	}
+
+	registry := retrier.NewRegistry()
+	registry.Register("payments", r)
+	var b strings.Builder
+	if err := registry.WriteOpenMetrics(context.Background(), &b); err != nil {
+		t.Fatalf("WriteOpenMetrics() error = %v", err)
+	}
+	ts := strconv.FormatFloat(float64(exemplars.Failures.Time.UnixMilli())/1000, 'f', 3, 64)
+	for _, want := range []string{
+		`retrier_failures_total{retrier="payments"} 2 # {trace_id="4bf92f35"} 1 ` + ts + "\n",
+		`retrier_retries_total{retrier="payments"} 2 # {trace_id="4bf92f35"} 1 ` + ts + "\n",
+		`retrier_errors_total{retrier="payments",fingerprint="connection refused"} 2 # {trace_id="4bf92f35"} 1 ` + ts + "\n",
+		"retrier_calls_total{retrier=\"payments\"} 3\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("expected %q in the exposition:\n%s", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package retrier_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestResolveOptions_Defaults verifies that ResolveOptions reports the defaults.
+func TestResolveOptions_Defaults(t *testing.T) {
+	got := retrier.ResolveOptions()
+	want := retrier.Options{
+		MaxAttempts:     3,
+		InitialDuration: 1 * time.Second,
+		Multiplier:      2.0,
+		MaxDuration:     1 * time.Minute,
+		Jitter:          0,
+		RetryPolicy:     retrier.RetryPolicyAuto,
+	}
+	if got != want {
+		t.Errorf("ResolveOptions() = %+v, want %+v", got, want)
+	}
+}
+
+// TestResolveOptions_RoundTrip verifies that Options converts back into equivalent RetryOptions.
+func TestResolveOptions_RoundTrip(t *testing.T) {
+	original := retrier.ResolveOptions(
+		retrier.WithMaxAttempts(7),
+		retrier.WithInitialDuration(250*time.Millisecond),
+		retrier.WithMultiplier(1.5),
+		retrier.WithMaxDuration(10*time.Second),
+		retrier.WithJitter(50*time.Millisecond),
+		retrier.WithRetryPolicy(retrier.RetryPolicyNever),
+	)
+
+	roundTripped := retrier.ResolveOptions(original.RetryOptions()...)
+	if roundTripped != original {
+		t.Errorf("round trip = %+v, want %+v", roundTripped, original)
+	}
+}
+
+// TestOptions_Delay verifies the exponential schedule reported by Delay.
+func TestOptions_Delay(t *testing.T) {
+	o := retrier.ResolveOptions(
+		retrier.WithInitialDuration(100*time.Millisecond),
+		retrier.WithMultiplier(2),
+		retrier.WithMaxDuration(300*time.Millisecond),
+	)
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for i, w := range want {
+		if got := o.Delay(i + 1); got != w {
+			t.Errorf("Delay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+// TestOptions_Delay_Saturates verifies that huge multipliers and retry counts
+// saturate instead of overflowing into negative delays.
+func TestOptions_Delay_Saturates(t *testing.T) {
+	const maxDelay = time.Duration(math.MaxInt64)
+	tests := []struct {
+		name string
+		o    retrier.Options
+		want time.Duration
+	}{
+		{"capped", retrier.Options{InitialDuration: time.Second, Multiplier: 10, MaxDuration: time.Hour}, time.Hour},
+		{"uncapped", retrier.Options{InitialDuration: time.Second, Multiplier: 10, MaxDuration: maxDelay}, maxDelay},
+		{"jitter past the largest delay", retrier.Options{InitialDuration: time.Second, Multiplier: 10, MaxDuration: maxDelay, Jitter: time.Hour}, maxDelay},
+		{"zero initial", retrier.Options{InitialDuration: 0, Multiplier: 10, MaxDuration: time.Hour}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, retry := range []int{50, 1000, math.MaxInt32} {
+				if got := tt.o.Delay(retry); got != tt.want {
+					t.Errorf("Delay(%d) = %v, want %v", retry, got, tt.want)
+				}
+			}
+		})
+	}
+}
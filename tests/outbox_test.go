@@ -0,0 +1,138 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/outbox"
+)
+
+// TestOutbox_PublishDelivered tests that a message delivered after retries is marked done.
+func TestOutbox_PublishDelivered(t *testing.T) {
+	store := outbox.NewMemoryStore()
+	calls := 0
+	p := outbox.NewPublisher(store, func(ctx context.Context, msg outbox.Message) error {
+		calls++
+		if calls < 3 {
+			return errors.New("broker unavailable")
+		}
+		return nil
+	}, noopLogger, defaultTestOpts()...)
+
+	if err := p.Publish(context.Background(), outbox.Message{ID: "m1", Topic: "orders"}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 deliveries, got %d", calls)
+	}
+	done := store.Done()
+	if len(done) != 1 || done[0].ID != "m1" || done[0].CreatedAt.IsZero() {
+		t.Errorf("expected m1 done with a creation time, got %+v", done)
+	}
+	if pending, _ := store.Pending(context.Background()); len(pending) != 0 {
+		t.Errorf("expected nothing pending, got %+v", pending)
+	}
+}
+
+// TestOutbox_PublishDeadLetter tests that exhausted and permanent failures are dead-lettered.
+func TestOutbox_PublishDeadLetter(t *testing.T) {
+	store := outbox.NewMemoryStore()
+	rejected := errors.New("schema rejected")
+	p := outbox.NewPublisher(store, func(ctx context.Context, msg outbox.Message) error {
+		if msg.ID == "bad" {
+			return retrier.Permanent(rejected)
+		}
+		return errors.New("broker unavailable")
+	}, noopLogger, append(defaultTestOpts(), retrier.WithMaxAttempts(2))...)
+
+	if err := p.Publish(context.Background(), outbox.Message{ID: "bad"}); !errors.Is(err, rejected) {
+		t.Errorf("Publish(bad) error = %v, want %v", err, rejected)
+	}
+	if err := p.Publish(context.Background(), outbox.Message{ID: "down"}); err == nil {
+		t.Error("expected Publish(down) to fail")
+	}
+
+	dead := store.Dead()
+	if len(dead) != 2 || dead[0].ID != "bad" || dead[1].ID != "down" {
+		t.Fatalf("expected bad and down dead-lettered, got %+v", dead)
+	}
+	if !errors.Is(dead[0].Reason, rejected) {
+		t.Errorf("expected the rejection as reason, got %v", dead[0].Reason)
+	}
+}
+
+// TestOutbox_Redeliver tests that interrupted deliveries stay pending and are
+// delivered by Redeliver.
+func TestOutbox_Redeliver(t *testing.T) {
+	store := outbox.NewMemoryStore()
+	stop := retrier.NewStopSignal()
+	stop.Stop()
+	brokerUp := false
+	deliver := func(ctx context.Context, msg outbox.Message) error {
+		if !brokerUp {
+			return errors.New("broker unavailable")
+		}
+		return nil
+	}
+
+	interrupted := outbox.NewPublisher(store, deliver, noopLogger, append(defaultTestOpts(), retrier.WithStopSignal(stop))...)
+	for _, id := range []string{"a", "b"} {
+		if err := interrupted.Publish(context.Background(), outbox.Message{ID: id}); err == nil {
+			t.Fatalf("expected Publish(%s) to be interrupted", id)
+		}
+	}
+	if pending, _ := store.Pending(context.Background()); len(pending) != 2 {
+		t.Fatalf("expected 2 pending messages, got %+v", pending)
+	}
+
+	brokerUp = true
+	p := outbox.NewPublisher(store, deliver, noopLogger, defaultTestOpts()...)
+	if err := p.Redeliver(context.Background()); err != nil {
+		t.Fatalf("Redeliver() error = %v", err)
+	}
+	if done := store.Done(); len(done) != 2 || done[0].ID != "a" || done[1].ID != "b" {
+		t.Errorf("expected a and b delivered in order, got %+v", done)
+	}
+	if len(store.Dead()) != 0 {
+		t.Errorf("expected no dead letters, got %+v", store.Dead())
+	}
+}
+
+// TestOutbox_InterruptedStaysPending tests that deliveries cut short by cancellation or
+// deadlines stay pending instead of being dead-lettered.
+func TestOutbox_InterruptedStaysPending(t *testing.T) {
+	store := outbox.NewMemoryStore()
+	ctx, shutdown := context.WithCancel(context.Background())
+	defer shutdown()
+	p := outbox.NewPublisher(store, func(ctx context.Context, msg outbox.Message) error {
+		switch msg.ID {
+		case "shutdown":
+			shutdown()
+			return ctx.Err()
+		case "canceled":
+			return context.Canceled
+		default:
+			return fmt.Errorf("send: %w", context.DeadlineExceeded)
+		}
+	}, noopLogger, append(defaultTestOpts(), retrier.WithMaxAttempts(2), retrier.WithInitialDuration(time.Millisecond))...)
+
+	for _, id := range []string{"canceled", "timeout"} {
+		if err := p.Publish(context.Background(), outbox.Message{ID: id}); err == nil {
+			t.Errorf("expected Publish(%s) to fail", id)
+		}
+	}
+	if err := p.Publish(ctx, outbox.Message{ID: "shutdown"}); err == nil {
+		t.Error("expected Publish(shutdown) to fail")
+	}
+
+	if dead := store.Dead(); len(dead) != 0 {
+		t.Errorf("expected no dead letters, got %+v", dead)
+	}
+	if pending, _ := store.Pending(context.Background()); len(pending) != 3 {
+		t.Errorf("expected 3 pending messages, got %+v", pending)
+	}
+}
@@ -0,0 +1,128 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// countingLimiter counts the calls it paced.
+type countingLimiter struct {
+	waits int
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.waits++
+	return ctx.Err()
+}
+
+// fetchNumbers serves pages of 1 item, up to last, failing every other call.
+func fetchNumbers(last int, calls *int) func(ctx context.Context, cursor string) (int, string, error) {
+	return func(ctx context.Context, cursor string) (int, string, error) {
+		*calls++
+		if *calls%2 == 1 {
+			return 0, "", errors.New("rate limited")
+		}
+		n := 1
+		if cursor != "" {
+			n, _ = strconv.Atoi(cursor)
+		}
+		next := ""
+		if n < last {
+			next = strconv.Itoa(n + 1)
+		}
+		return n, next, nil
+	}
+}
+
+// TestRetryPages verifies that every page is fetched with retries, handled
+// in order, and checkpointed, and that a finished job does not run again.
+func TestRetryPages(t *testing.T) {
+	store := retrier.NewMemoryCheckpoints()
+	limiter := &countingLimiter{}
+	p := retrier.Pagination{Job: "export", Store: store, Limiter: limiter}
+	calls := 0
+	var handled []int
+	handle := func(ctx context.Context, n int) error {
+		handled = append(handled, n)
+		return nil
+	}
+
+	cp, err := retrier.RetryPages(context.Background(), noopLogger, p, fetchNumbers(3, &calls), handle, defaultTestOpts()...)
+	if err != nil {
+		t.Fatalf("RetryPages() error = %v", err)
+	}
+	if want := (retrier.Checkpoint{Pages: 3, Done: true}); cp != want {
+		t.Errorf("expected checkpoint %+v, got %+v", want, cp)
+	}
+	if len(handled) != 3 || handled[0] != 1 || handled[2] != 3 {
+		t.Errorf("expected pages 1 to 3 in order, got %v", handled)
+	}
+	if limiter.waits != calls || calls != 6 {
+		t.Errorf("expected the limiter to pace all 6 calls, got %d waits for %d calls", limiter.waits, calls)
+	}
+
+	cp, err = retrier.RetryPages(context.Background(), noopLogger, p, fetchNumbers(3, &calls), handle, defaultTestOpts()...)
+	if err != nil || !cp.Done || calls != 6 {
+		t.Errorf("expected a finished job to return at once, got %+v, %v after %d calls", cp, err, calls)
+	}
+}
+
+// TestRetryPages_Resume verifies that a job stopped by a failing page
+// resumes after the last handled page.
+func TestRetryPages_Resume(t *testing.T) {
+	store := retrier.NewMemoryCheckpoints()
+	p := retrier.Pagination{Job: "export", Store: store}
+	calls := 0
+	failOn := 2
+	var handled []int
+	handle := func(ctx context.Context, n int) error {
+		if n == failOn {
+			return errors.New("disk full")
+		}
+		handled = append(handled, n)
+		return nil
+	}
+
+	cp, err := retrier.RetryPages(context.Background(), noopLogger, p, fetchNumbers(3, &calls), handle, defaultTestOpts()...)
+	if err == nil || cp.Pages != 1 || cp.Cursor != "2" {
+		t.Fatalf("expected the job to stop after page 1, got %+v, %v", cp, err)
+	}
+
+	failOn = 0
+	cp, err = retrier.RetryPages(context.Background(), noopLogger, p, fetchNumbers(3, &calls), handle, defaultTestOpts()...)
+	if err != nil || !cp.Done || cp.Pages != 3 {
+		t.Fatalf("expected the job to finish, got %+v, %v", cp, err)
+	}
+	if len(handled) != 3 || handled[1] != 2 {
+		t.Errorf("expected each page handled once, got %v", handled)
+	}
+}
+
+// TestRetryPages_MaxElapsed verifies that the job budget stops the job with
+// ErrJobTimeout, keeping its checkpoint.
+func TestRetryPages_MaxElapsed(t *testing.T) {
+	store := retrier.NewMemoryCheckpoints()
+	p := retrier.Pagination{Job: "export", Store: store, MaxElapsed: 20 * time.Millisecond}
+	cp, err := retrier.RetryPages(context.Background(), noopLogger, p,
+		func(ctx context.Context, cursor string) (int, string, error) {
+			if cursor == "" {
+				return 1, "next", nil
+			}
+			<-ctx.Done()
+			return 0, "", ctx.Err()
+		},
+		func(ctx context.Context, n int) error { return nil },
+		defaultTestOpts()...)
+
+	if !errors.Is(err, retrier.ErrJobTimeout) {
+		t.Fatalf("expected ErrJobTimeout, got %v", err)
+	}
+	if saved, _ := store.LoadCheckpoint(context.Background(), "export"); saved != cp || cp.Cursor != "next" {
+		t.Errorf("expected the checkpoint after page 1 to be saved, got %+v and %+v", saved, cp)
+	}
+}
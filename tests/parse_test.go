@@ -0,0 +1,78 @@
+package retrier_test
+
+import (
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestParsePolicy_Format tests the compact policy format.
+func TestParsePolicy_Format(t *testing.T) {
+	tests := []struct {
+		input string
+		want  retrier.Options
+	}{
+		{
+			input: "exponential(100ms, x2, jitter=50ms, max=30s, attempts=5)",
+			want: retrier.Options{
+				MaxAttempts: 5, InitialDuration: 100 * time.Millisecond, Multiplier: 2,
+				MaxDuration: 30 * time.Second, Jitter: 50 * time.Millisecond, RetryPolicy: retrier.RetryPolicyAuto,
+			},
+		},
+		{
+			input: " constant( 1s , attempts=3 , policy=manual ) ",
+			want: retrier.Options{
+				MaxAttempts: 3, InitialDuration: time.Second, Multiplier: 1,
+				MaxDuration: time.Minute, RetryPolicy: retrier.RetryPolicyManual,
+			},
+		},
+		{
+			input: "exponential()",
+			want:  retrier.ResolveOptions(),
+		},
+		{
+			input: "exponential(multiplier=1.5, initial=2s)",
+			want: retrier.Options{
+				MaxAttempts: 3, InitialDuration: 2 * time.Second, Multiplier: 1.5,
+				MaxDuration: time.Minute, RetryPolicy: retrier.RetryPolicyAuto,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			opts, err := retrier.ParsePolicy(tt.input)
+			if err != nil {
+				t.Fatalf("ParsePolicy() error = %v", err)
+			}
+			if got := retrier.ResolveOptions(opts...); got != tt.want {
+				t.Errorf("ParsePolicy() resolved to %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParsePolicy_Invalid tests that malformed policies are rejected.
+func TestParsePolicy_Invalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"exponential",
+		"exponential(100ms",
+		"linear(100ms)",
+		"exponential(100ms, 200ms)",
+		"exponential(fast)",
+		"exponential(x0.5)",
+		"exponential(attempts=0)",
+		"exponential(jitter=-1s)",
+		"exponential(max=0s)",
+		"exponential(0s, max=0s, attempts=2)",
+		"exponential(policy=sometimes)",
+		"exponential(retries=3)",
+	}
+	for _, input := range inputs {
+		if _, err := retrier.ParsePolicy(input); err == nil {
+			t.Errorf("ParsePolicy(%q) expected error", input)
+		}
+	}
+}
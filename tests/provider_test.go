@@ -0,0 +1,74 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestDynamicPolicy_OverridesCallOptions verifies that provider options take
+// precedence over call-site options and that Set affects subsequent calls.
+func TestDynamicPolicy_OverridesCallOptions(t *testing.T) {
+	policy := retrier.NewDynamicPolicy(retrier.WithMaxAttempts(2))
+	callCount := 0
+	fn := func() (string, error) {
+		callCount++
+		return "", errors.New("transient")
+	}
+	opts := append(defaultTestOpts(),
+		retrier.WithMaxAttempts(5),
+		retrier.WithPolicyProvider(policy),
+	)
+
+	result := retrier.Retry(context.Background(), noopLogger, fn, opts...)
+	if result.Attempts() != 2 || callCount != 2 {
+		t.Errorf("expected the provider's 2 attempts, got %d", result.Attempts())
+	}
+
+	policy.Set(retrier.WithMaxAttempts(1))
+	callCount = 0
+	retrier.Retry(context.Background(), noopLogger, fn, opts...)
+	if callCount != 1 {
+		t.Errorf("expected 1 call after Set, got %d", callCount)
+	}
+
+	policy.Set()
+	callCount = 0
+	retrier.Retry(context.Background(), noopLogger, fn, opts...)
+	if callCount != 5 {
+		t.Errorf("expected the call-site 5 attempts with an empty policy, got %d", callCount)
+	}
+}
+
+// TestDynamicPolicy_ResolveOptions verifies that ResolveOptions reflects the provider.
+func TestDynamicPolicy_ResolveOptions(t *testing.T) {
+	policy := retrier.NewDynamicPolicy(retrier.WithInitialDuration(time.Hour))
+	got := retrier.ResolveOptions(retrier.WithPolicyProvider(policy))
+	if got.InitialDuration != time.Hour {
+		t.Errorf("expected initial duration 1h, got %v", got.InitialDuration)
+	}
+}
+
+// TestDynamicPolicy_ConcurrentSet verifies that Set is safe during Retry calls.
+func TestDynamicPolicy_ConcurrentSet(t *testing.T) {
+	policy := retrier.NewDynamicPolicy()
+	fn := func() (string, error) { return "ok", nil }
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			retrier.Retry(context.Background(), noopLogger, fn, retrier.WithPolicyProvider(policy))
+		}()
+		go func(n int) {
+			defer wg.Done()
+			policy.Set(retrier.WithMaxAttempts(n + 1))
+		}(i)
+	}
+	wg.Wait()
+}
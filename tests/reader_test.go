@@ -0,0 +1,112 @@
+package retrier_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// flakyStream serves data from an offset and fails after failAfter bytes.
+type flakyStream struct {
+	data      []byte
+	pos       int
+	failAfter int
+	closed    bool
+}
+
+func (s *flakyStream) Read(p []byte) (int, error) {
+	if s.pos >= len(s.data) {
+		return 0, io.EOF
+	}
+	if s.failAfter == 0 {
+		return 0, errors.New("connection reset by peer")
+	}
+	n := min(len(p), len(s.data)-s.pos, s.failAfter)
+	copy(p, s.data[s.pos:s.pos+n])
+	s.pos += n
+	s.failAfter -= n
+	return n, nil
+}
+
+func (s *flakyStream) Close() error {
+	s.closed = true
+	return nil
+}
+
+// TestRetryReader_ResumesAtOffset verifies that a stream failing mid-way is
+// re-opened at the failed offset and read in full.
+func TestRetryReader_ResumesAtOffset(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	var offsets []int64
+	var streams []*flakyStream
+	r := retrier.NewRetryReader(context.Background(), noopLogger, func(offset int64) (io.ReadCloser, error) {
+		offsets = append(offsets, offset)
+		s := &flakyStream{data: data, pos: int(offset), failAfter: 300}
+		streams = append(streams, s)
+		return s, nil
+	}, defaultTestOpts()...)
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("expected %d bytes of data, got %d", len(data), len(got))
+	}
+	want := []int64{0, 300, 600, 900}
+	if len(offsets) != len(want) {
+		t.Fatalf("expected opens at %v, got %v", want, offsets)
+	}
+	for i := range want {
+		if offsets[i] != want[i] {
+			t.Errorf("open %d: expected offset %d, got %d", i, want[i], offsets[i])
+		}
+	}
+	for i, s := range streams[:3] {
+		if !s.closed {
+			t.Errorf("stream %d: expected the failed stream to be closed", i)
+		}
+	}
+
+	if err := r.Close(); err != nil || !streams[3].closed {
+		t.Errorf("expected Close to close the last stream, got %v", err)
+	}
+	if _, err := r.Read(make([]byte, 1)); err == nil {
+		t.Error("expected reads after Close to fail")
+	}
+}
+
+// TestRetryReader_GivesUp verifies that consecutive failures exhaust the
+// attempts, and the error sticks.
+func TestRetryReader_GivesUp(t *testing.T) {
+	opens := 0
+	r := retrier.NewRetryReader(context.Background(), noopLogger, func(offset int64) (io.ReadCloser, error) {
+		opens++
+		if opens > 1 {
+			return nil, errors.New("503 service unavailable")
+		}
+		return &flakyStream{data: []byte("hello world"), failAfter: 5}, nil
+	}, defaultTestOpts()...)
+
+	buf := make([]byte, 64)
+	n, err := r.Read(buf)
+	if n != 5 || err != nil {
+		t.Fatalf("expected the first 5 bytes, got %d, %v", n, err)
+	}
+	_, err = r.Read(buf)
+	var retryErr *retrier.RetryError
+	if !errors.As(err, &retryErr) || retryErr.Cause != retrier.ErrExhaustedAttempts {
+		t.Fatalf("expected exhausted attempts, got %v", err)
+	}
+	if _, again := r.Read(buf); again != err {
+		t.Errorf("expected the error to stick, got %v", again)
+	}
+	// The first attempt of the second Read fails on the open stream
+	if opens != 3 {
+		t.Errorf("expected 1 open and 2 failed re-opens, got %d opens", opens)
+	}
+}
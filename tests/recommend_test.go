@@ -0,0 +1,99 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// recordLoops records n loops that succeeded at attempt, or exhausted their
+// attempts if attempt is 0, to stats.
+func recordLoops(stats *retrier.PolicyStats, n, attempt, maxAttempts, capped int) {
+	for range n {
+		s := retrier.Summary{Attempts: attempt, CappedRetries: capped, TotalLatency: time.Second}
+		if attempt == 0 {
+			s.Attempts = maxAttempts
+			s.FailureKind = string(retrier.ErrExhaustedAttempts)
+		}
+		stats.Record(context.Background(), s)
+	}
+}
+
+// TestPolicyStats_ReduceMaxAttempts verifies that MaxAttempts is reduced when
+// successes happen early.
+func TestPolicyStats_ReduceMaxAttempts(t *testing.T) {
+	stats := retrier.NewPolicyStats()
+	recordLoops(stats, 80, 1, 5, 0)
+	recordLoops(stats, 20, 2, 5, 0)
+	recordLoops(stats, 10, 0, 5, 0)
+
+	report := stats.Recommend(retrier.Options{MaxAttempts: 5, MaxDuration: time.Second})
+	if report.Loops != 110 || report.Successes != 100 || report.Retries != 60 {
+		t.Errorf("unexpected counts %+v", report)
+	}
+	if len(report.Recommendations) != 1 {
+		t.Fatalf("expected 1 recommendation, got %v", report.Recommendations)
+	}
+	rec := report.Recommendations[0]
+	if rec.Setting != "MaxAttempts" || rec.Current != "5" || rec.Suggested != "2" {
+		t.Errorf("unexpected recommendation %+v", rec)
+	}
+	if got, want := rec.String(), "99% of successes happen by attempt 2; change MaxAttempts from 5 to 2"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+// TestPolicyStats_RaiseLimits verifies that MaxAttempts is raised when loops
+// exhaust their attempts while the last attempt still succeeds, and
+// MaxDuration when the cap is mostly reached.
+func TestPolicyStats_RaiseLimits(t *testing.T) {
+	stats := retrier.NewPolicyStats()
+	recordLoops(stats, 30, 2, 4, 0)
+	recordLoops(stats, 30, 4, 4, 3)
+	recordLoops(stats, 20, 0, 4, 3)
+
+	report := stats.Recommend(retrier.Options{MaxAttempts: 4, MaxDuration: time.Second})
+	if len(report.Recommendations) != 2 {
+		t.Fatalf("expected 2 recommendations, got %v", report.Recommendations)
+	}
+	if rec := report.Recommendations[0]; rec.Setting != "MaxAttempts" || rec.Suggested != "6" {
+		t.Errorf("unexpected recommendation %v", rec)
+	}
+	if rec := report.Recommendations[1]; rec.Setting != "MaxDuration" || rec.Suggested != "2s" {
+		t.Errorf("unexpected recommendation %v", rec)
+	}
+}
+
+// TestPolicyStats_TooFewLoops verifies that nothing is recommended from a
+// small sample.
+func TestPolicyStats_TooFewLoops(t *testing.T) {
+	stats := retrier.NewPolicyStats()
+	recordLoops(stats, 10, 1, 5, 0)
+	if report := stats.Recommend(retrier.Options{MaxAttempts: 5}); len(report.Recommendations) != 0 {
+		t.Errorf("expected no recommendation, got %v", report.Recommendations)
+	}
+}
+
+// TestPolicyStats_Record verifies that a PolicyStats collects the Summaries of
+// a Retrier, including the retries capped by WithMaxDuration.
+func TestPolicyStats_Record(t *testing.T) {
+	stats := retrier.NewPolicyStats()
+	r := retrier.NewRetrier(retrier.NewNoOpLogger(),
+		retrier.WithMaxAttempts(4),
+		retrier.WithInitialDuration(time.Millisecond),
+		retrier.WithMaxDuration(time.Millisecond),
+		retrier.WithJitter(0),
+		retrier.WithSummarySink(stats.Record),
+	)
+	retrier.Do(context.Background(), r, func() (int, error) {
+		return 0, errors.New("unavailable")
+	})
+
+	report := stats.Recommend(r.Options())
+	if report.Loops != 1 || report.Retries != 3 || report.CappedRetries != 2 {
+		t.Errorf("expected 3 retries, 2 capped, got %+v", report)
+	}
+}
@@ -0,0 +1,96 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// errorLogger records the text of the errors passed to LogRetry.
+type errorLogger struct {
+	messages []string
+}
+
+func (l *errorLogger) Enabled() bool { return true }
+
+func (l *errorLogger) LogRetry(_ context.Context, _ int, _ int, _ time.Duration, err error, _ ...any) {
+	if err != nil {
+		l.messages = append(l.messages, err.Error())
+	}
+}
+
+func redactToken(text string) string {
+	return strings.ReplaceAll(text, "secret123", "<redacted>")
+}
+
+// TestWithRedactor tests that error text is redacted in logs, callbacks,
+// RetryError messages, and fingerprints, while the error chain is preserved.
+func TestWithRedactor(t *testing.T) {
+	secret := errors.New("auth failed: token=secret123")
+	logger := &errorLogger{}
+	var callbackMessages []string
+	var callbackErrs []error
+
+	r := retrier.NewRetrier(logger, append(defaultTestOpts(),
+		retrier.WithMaxAttempts(3),
+		retrier.WithRedactor(redactToken),
+		retrier.WithOnRetry(func(_ int, err error) {
+			callbackMessages = append(callbackMessages, err.Error())
+			callbackErrs = append(callbackErrs, err)
+		}),
+	)...)
+
+	err := retrier.Do(context.Background(), r, func() (int, error) {
+		return 0, secret
+	}).Err()
+
+	if len(logger.messages) != 3 {
+		t.Fatalf("expected 3 logged errors, got %d", len(logger.messages))
+	}
+	for _, msg := range append(logger.messages, callbackMessages...) {
+		if strings.Contains(msg, "secret123") {
+			t.Errorf("expected redacted text, got %q", msg)
+		}
+	}
+	for _, cbErr := range callbackErrs {
+		if !errors.Is(cbErr, secret) {
+			t.Error("expected the callback error to wrap the original error")
+		}
+	}
+
+	if strings.Contains(err.Error(), "secret123") || !strings.Contains(err.Error(), "token=<redacted>") {
+		t.Errorf("expected a redacted RetryError message, got %q", err.Error())
+	}
+	if !errors.Is(err, secret) {
+		t.Error("expected the RetryError to wrap the original error")
+	}
+
+	for fingerprint := range r.ErrorCounts() {
+		if strings.Contains(fingerprint, "secret") {
+			t.Errorf("expected redacted fingerprint, got %q", fingerprint)
+		}
+	}
+}
+
+// TestWithRedactor_ErrorFormatter tests that redaction applies to formatted messages.
+func TestWithRedactor_ErrorFormatter(t *testing.T) {
+	opts := append(defaultTestOpts(),
+		retrier.WithMaxAttempts(1),
+		retrier.WithRedactor(redactToken),
+		retrier.WithErrorFormatter(func(e *retrier.RetryError) string {
+			return "failed: " + e.Unwrap().Error()
+		}),
+	)
+
+	err := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		return 0, errors.New("token=secret123")
+	}, opts...).Err()
+
+	if got, want := err.Error(), "failed: token=<redacted>"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
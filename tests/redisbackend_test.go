@@ -0,0 +1,198 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/redisbackend"
+)
+
+// fakeRedis is a minimal in-memory Redis understanding the commands used by redisbackend.
+type fakeRedis struct {
+	mu   sync.Mutex
+	data map[string]string
+	ttls map[string]int64
+	err  error
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{data: make(map[string]string), ttls: make(map[string]int64)}
+}
+
+func (f *fakeRedis) Do(_ context.Context, args ...any) (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	switch args[0] {
+	case "GET":
+		value, exists := f.data[args[1].(string)]
+		if !exists {
+			return nil, nil
+		}
+		return []byte(value), nil
+	case "SET":
+		key, value := args[1].(string), args[2].(string)
+		if _, exists := f.data[key]; exists {
+			return nil, nil
+		}
+		f.data[key] = value
+		f.ttls[key] = args[5].(int64)
+		return "OK", nil
+	case "EVAL":
+		if strings.Contains(args[1].(string), "INCRBY") {
+			key, delta := args[3].(string), args[4].(int64)
+			n, _ := strconv.ParseInt(f.data[key], 10, 64)
+			n += delta
+			if n == delta {
+				f.ttls[key] = args[5].(int64)
+			}
+			f.data[key] = strconv.FormatInt(n, 10)
+			return n, nil
+		}
+		key, token := args[3].(string), args[4].(string)
+		if f.data[key] == token {
+			delete(f.data, key)
+			return int64(1), nil
+		}
+		return int64(0), nil
+	}
+	return nil, fmt.Errorf("unsupported command %v", args[0])
+}
+
+// TestRedisCoordinator_AcquireRelease tests the lease lifecycle.
+func TestRedisCoordinator_AcquireRelease(t *testing.T) {
+	client := newFakeRedis()
+	coord := redisbackend.NewCoordinator(client, "retrier:")
+	ctx := context.Background()
+
+	ok, err := coord.Acquire(ctx, "op", 2*time.Second)
+	if err != nil || !ok {
+		t.Fatalf("Acquire() = %v, %v; want true, nil", ok, err)
+	}
+	if _, exists := client.data["retrier:op"]; !exists {
+		t.Error("expected lease key to be prefixed")
+	}
+	if client.ttls["retrier:op"] != 2000 {
+		t.Errorf("expected ttl 2000ms, got %d", client.ttls["retrier:op"])
+	}
+
+	ok, err = coord.Acquire(ctx, "op", time.Second)
+	if err != nil || ok {
+		t.Fatalf("second Acquire() = %v, %v; want false, nil", ok, err)
+	}
+
+	if err := coord.Release(ctx, "op"); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	ok, _ = coord.Acquire(ctx, "op", time.Second)
+	if !ok {
+		t.Error("expected Acquire to succeed after Release")
+	}
+}
+
+// TestRedisCoordinator_ReleaseOnlyOwnLease verifies that a coordinator cannot
+// release a lease owned by another instance.
+func TestRedisCoordinator_ReleaseOnlyOwnLease(t *testing.T) {
+	client := newFakeRedis()
+	owner := redisbackend.NewCoordinator(client, "")
+	other := redisbackend.NewCoordinator(client, "")
+	ctx := context.Background()
+
+	if ok, _ := owner.Acquire(ctx, "op", time.Second); !ok {
+		t.Fatal("expected owner to acquire the lease")
+	}
+	if err := other.Release(ctx, "op"); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if ok, _ := other.Acquire(ctx, "op", time.Second); ok {
+		t.Error("lease should still be held by its owner")
+	}
+}
+
+// TestRedisCoordinator_MinimumTTL verifies that sub-millisecond TTLs are rounded up.
+func TestRedisCoordinator_MinimumTTL(t *testing.T) {
+	client := newFakeRedis()
+	coord := redisbackend.NewCoordinator(client, "")
+
+	if ok, _ := coord.Acquire(context.Background(), "op", time.Microsecond); !ok {
+		t.Fatal("expected Acquire to succeed")
+	}
+	if client.ttls["op"] != 1 {
+		t.Errorf("expected ttl 1ms, got %d", client.ttls["op"])
+	}
+}
+
+// TestRedisCoordinator_ClientError verifies that client errors are propagated.
+func TestRedisCoordinator_ClientError(t *testing.T) {
+	client := newFakeRedis()
+	client.err = errors.New("connection refused")
+	coord := redisbackend.NewCoordinator(client, "")
+
+	if _, err := coord.Acquire(context.Background(), "op", time.Second); !errors.Is(err, client.err) {
+		t.Errorf("Acquire() error = %v, want wrapped %v", err, client.err)
+	}
+	if err := coord.Release(context.Background(), "op"); !errors.Is(err, client.err) {
+		t.Errorf("Release() error = %v, want wrapped %v", err, client.err)
+	}
+}
+
+// TestRedisBudgetStore_AddGet tests counter increments and expiry setup.
+func TestRedisBudgetStore_AddGet(t *testing.T) {
+	client := newFakeRedis()
+	store := redisbackend.NewBudgetStore(client, "budget:")
+	ctx := context.Background()
+
+	if v, err := store.Get(ctx, "c"); err != nil || v != 0 {
+		t.Fatalf("Get() on missing key = %d, %v; want 0, nil", v, err)
+	}
+	if v, err := store.Add(ctx, "c", 3, 5*time.Second); err != nil || v != 3 {
+		t.Fatalf("Add() = %d, %v; want 3, nil", v, err)
+	}
+	if v, err := store.Add(ctx, "c", -1, 5*time.Second); err != nil || v != 2 {
+		t.Fatalf("Add() = %d, %v; want 2, nil", v, err)
+	}
+	if v, err := store.Get(ctx, "c"); err != nil || v != 2 {
+		t.Fatalf("Get() = %d, %v; want 2, nil", v, err)
+	}
+	if client.ttls["budget:c"] != 5000 {
+		t.Errorf("expected ttl 5000ms, got %d", client.ttls["budget:c"])
+	}
+}
+
+// TestRedisBudgetStore_SharedBudget verifies that two budgets sharing a Redis
+// store enforce a single limit.
+func TestRedisBudgetStore_SharedBudget(t *testing.T) {
+	client := newFakeRedis()
+	replicaA := retrier.NewRetryBudget(0,
+		retrier.WithMinRetries(1),
+		retrier.WithBudgetWindow(time.Hour),
+		retrier.WithBudgetStore(redisbackend.NewBudgetStore(client, ""), "svc"),
+	)
+	replicaB := retrier.NewRetryBudget(0,
+		retrier.WithMinRetries(1),
+		retrier.WithBudgetWindow(time.Hour),
+		retrier.WithBudgetStore(redisbackend.NewBudgetStore(client, ""), "svc"),
+	)
+	fn := func() (string, error) { return "", errors.New("transient") }
+	opts := append(defaultTestOpts(), retrier.WithInitialDuration(time.Millisecond), retrier.WithMaxAttempts(2))
+
+	first := retrier.Retry(context.Background(), noopLogger, fn, append(opts, retrier.WithBudget(replicaA))...)
+	second := retrier.Retry(context.Background(), noopLogger, fn, append(opts, retrier.WithBudget(replicaB))...)
+
+	if first.Attempts() != 2 {
+		t.Errorf("expected the first replica to retry, got %d attempts", first.Attempts())
+	}
+	var retryErr *retrier.RetryError
+	if !errors.As(second.Err(), &retryErr) || retryErr.Cause != retrier.ErrBudgetExhausted {
+		t.Errorf("expected the second replica to hit the shared budget, got %v", second.Err())
+	}
+}
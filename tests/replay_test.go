@@ -0,0 +1,137 @@
+package retrier_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestWithRecording verifies that the timeline of a loop is recorded,
+// survives encoding, and replays as text.
+func TestWithRecording(t *testing.T) {
+	var recs []retrier.Recording
+	calls := 0
+	opts := append(defaultTestOpts(),
+		retrier.WithExplain(),
+		retrier.WithRecording(func(ctx context.Context, rec retrier.Recording) {
+			recs = append(recs, rec)
+		}),
+	)
+	retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, errors.New("unavailable")
+		}
+		return 1, nil
+	}, opts...)
+
+	if len(recs) != 1 {
+		t.Fatalf("expected one recording, got %d", len(recs))
+	}
+	rec := recs[0]
+	if !rec.Succeeded() || len(rec.Attempts) != 3 {
+		t.Fatalf("expected a success after 3 attempts, got %+v", rec)
+	}
+	first := rec.Attempts[0]
+	if first.Err != "unavailable" || first.Delay <= 0 || !strings.HasPrefix(first.Decision, "default_policy: ") {
+		t.Errorf("expected the first attempt to fail and be retried, got %+v", first)
+	}
+	if second := rec.Attempts[1]; second.Offset < first.Offset+first.Duration+first.Delay {
+		t.Errorf("expected the second attempt after the delay, got %+v", rec.Attempts)
+	}
+
+	var buf bytes.Buffer
+	if err := retrier.EncodeRecording(&buf, rec); err != nil {
+		t.Fatalf("EncodeRecording() error = %v", err)
+	}
+	decoded, err := retrier.DecodeRecording(&buf)
+	if err != nil {
+		t.Fatalf("DecodeRecording() error = %v", err)
+	}
+	if !decoded.Start.Equal(rec.Start) || len(decoded.Attempts) != 3 || decoded.Attempts[0] != first {
+		t.Errorf("expected the recording to round-trip, got %+v", decoded)
+	}
+
+	buf.Reset()
+	if err := retrier.Replay(&buf, decoded); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"attempt 1", "failed: unavailable", "wait", "attempt 3", "succeeded after 3 attempts"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected the replay to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+// TestWithRecording_Failure verifies that the final decision and error of a
+// failed loop are recorded.
+func TestWithRecording_Failure(t *testing.T) {
+	var rec retrier.Recording
+	opts := append(defaultTestOpts(),
+		retrier.WithExplain(),
+		retrier.WithRecording(func(ctx context.Context, r retrier.Recording) { rec = r }),
+	)
+	retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		return 0, retrier.Permanent(errors.New("bad request"))
+	}, opts...)
+
+	if rec.Succeeded() || len(rec.Attempts) != 1 || !rec.Attempts[0].Permanent {
+		t.Fatalf("expected one permanent failure, got %+v", rec)
+	}
+	if !strings.HasPrefix(rec.Attempts[0].Decision, "error_policy: ") || rec.Err == "" {
+		t.Errorf("expected the stop decision and error, got %+v", rec)
+	}
+}
+
+// TestRecording_Simulate verifies the predicted outcome of other policies
+// against an outage lasting 3 seconds.
+func TestRecording_Simulate(t *testing.T) {
+	rec := retrier.Recording{
+		Attempts: []retrier.RecordedAttempt{
+			{Offset: 0, Duration: 100 * time.Millisecond, Err: "unavailable", Delay: time.Second},
+			{Offset: 1100 * time.Millisecond, Duration: 100 * time.Millisecond, Err: "unavailable", Delay: 2 * time.Second},
+			{Offset: 3200 * time.Millisecond, Duration: 100 * time.Millisecond},
+		},
+	}
+	tests := []struct {
+		name      string
+		opts      []retrier.RetryOption
+		known     bool
+		succeeded bool
+		attempts  int
+	}{
+		{"same policy", nil, true, true, 3},
+		{"fewer attempts", []retrier.RetryOption{retrier.WithMaxAttempts(2)}, true, false, 2},
+		{"constant 500ms", []retrier.RetryOption{retrier.WithMaxAttempts(10), retrier.ConstantBackoff(500 * time.Millisecond)}, true, true, 7},
+		{"total duration", []retrier.RetryOption{retrier.WithMaxTotalDuration(2 * time.Second)}, true, false, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := rec.Simulate(tt.opts...)
+			if sim.Known != tt.known || sim.Succeeded != tt.succeeded || sim.Attempts != tt.attempts {
+				t.Errorf("expected known=%t succeeded=%t after %d attempts, got %+v", tt.known, tt.succeeded, tt.attempts, sim)
+			}
+		})
+	}
+}
+
+// TestRecording_SimulateUnknown verifies that outcomes past the end of a
+// failed recording are unknown.
+func TestRecording_SimulateUnknown(t *testing.T) {
+	rec := retrier.Recording{
+		Attempts: []retrier.RecordedAttempt{
+			{Offset: 0, Duration: 10 * time.Millisecond, Err: "unavailable", Delay: time.Second},
+			{Offset: 1010 * time.Millisecond, Duration: 10 * time.Millisecond, Err: "unavailable"},
+		},
+		Err: "exhausted",
+	}
+	if sim := rec.Simulate(retrier.WithMaxAttempts(5)); sim.Known {
+		t.Errorf("expected an unknown outcome, got %+v", sim)
+	}
+}
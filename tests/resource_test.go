@@ -0,0 +1,130 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// resourcePool hands out numbered resources and records their release.
+type resourcePool struct {
+	mu       sync.Mutex
+	acquired int
+	released map[int]error
+}
+
+func (p *resourcePool) acquire(context.Context) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.acquired++
+	return p.acquired, nil
+}
+
+func (p *resourcePool) release(r int, err error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.released == nil {
+		p.released = make(map[int]error)
+	}
+	p.released[r] = err
+	return nil
+}
+
+// TestRetryWithResource verifies that every attempt acquires a fresh resource
+// and releases it with its outcome.
+func TestRetryWithResource(t *testing.T) {
+	pool := &resourcePool{}
+	boom := errors.New("boom")
+	result := retrier.RetryWithResource(context.Background(), noopLogger, pool.acquire, pool.release,
+		func(ctx context.Context, r int) (string, error) {
+			if r < 3 {
+				return "", boom
+			}
+			return "ok", nil
+		},
+		defaultTestOpts()...,
+	)
+
+	if result.Value() != "ok" || pool.acquired != 3 {
+		t.Fatalf("expected success with resource 3, got %q after %d acquisitions", result.Value(), pool.acquired)
+	}
+	want := map[int]error{1: boom, 2: boom, 3: nil}
+	for r, err := range want {
+		got, ok := pool.released[r]
+		if !ok || got != err {
+			t.Errorf("resource %d: expected release with %v, got %v (released: %v)", r, err, got, ok)
+		}
+	}
+}
+
+// TestRetryWithResource_ReleaseError verifies that a release error fails a
+// successful attempt, and is discarded after a failed one.
+func TestRetryWithResource_ReleaseError(t *testing.T) {
+	flush := errors.New("flush failed")
+	calls := 0
+	result := retrier.RetryWithResource(context.Background(), noopLogger,
+		func(context.Context) (int, error) { calls++; return calls, nil },
+		func(r int, err error) error {
+			if r == 1 {
+				return flush
+			}
+			return errors.New("ignored")
+		},
+		func(ctx context.Context, r int) (int, error) {
+			if r == 2 {
+				return 0, retrier.Permanent(errors.New("bad input"))
+			}
+			return r, nil
+		},
+		defaultTestOpts()...,
+	)
+
+	if errs := result.Errors(); len(errs) != 2 || errs[0] != flush || errs[1].Error() != "bad input" {
+		t.Errorf("expected the release error, then the permanent error, got %v", errs)
+	}
+	if result.Value() != 0 {
+		t.Errorf("expected no value, got %d", result.Value())
+	}
+}
+
+// TestRetryWithResource_AcquireFailure verifies that acquire failures are
+// retried and release is not called without a resource.
+func TestRetryWithResource_AcquireFailure(t *testing.T) {
+	calls, releases := 0, 0
+	result := retrier.RetryWithResource(context.Background(), noopLogger,
+		func(context.Context) (int, error) {
+			calls++
+			if calls == 1 {
+				return 0, errors.New("pool exhausted")
+			}
+			return calls, nil
+		},
+		func(int, error) error { releases++; return nil },
+		func(ctx context.Context, r int) (int, error) { return r, nil },
+		defaultTestOpts()...,
+	)
+	if result.Value() != 2 || releases != 1 {
+		t.Errorf("expected 2 with one release, got %d with %d", result.Value(), releases)
+	}
+}
+
+// TestRetryWithResource_Panic verifies that a panicking attempt still releases
+// its resource, with ErrAttemptAborted.
+func TestRetryWithResource_Panic(t *testing.T) {
+	pool := &resourcePool{}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected the panic to propagate")
+		}
+		if err, ok := pool.released[1]; !ok || err != retrier.ErrAttemptAborted {
+			t.Errorf("expected release with ErrAttemptAborted, got %v (released: %v)", err, ok)
+		}
+	}()
+	retrier.RetryWithResource(context.Background(), noopLogger, pool.acquire, pool.release,
+		func(ctx context.Context, r int) (int, error) { panic("boom") },
+		defaultTestOpts()...,
+	)
+}
@@ -0,0 +1,137 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestRetrier_Do verifies that Do applies the Retrier options and per-call options.
+func TestRetrier_Do(t *testing.T) {
+	r := retrier.NewRetrier(noopLogger, append(defaultTestOpts(), retrier.WithMaxAttempts(2))...)
+	callCount := 0
+	fn := func() (int, error) {
+		callCount++
+		return 0, errors.New("transient")
+	}
+
+	if result := retrier.Do(context.Background(), r, fn); result.Attempts() != 2 {
+		t.Errorf("expected 2 attempts, got %d", result.Attempts())
+	}
+	if result := retrier.Do(context.Background(), r, fn, retrier.WithMaxAttempts(1)); result.Attempts() != 1 {
+		t.Errorf("expected the per-call option to win, got %d attempts", result.Attempts())
+	}
+}
+
+// TestRetrier_DefaultPolicy verifies that the default policy of a Retrier
+// applies to its calls and can be overridden per call.
+func TestRetrier_DefaultPolicy(t *testing.T) {
+	r := retrier.NewRetrier(noopLogger, append(defaultTestOpts(), retrier.WithDefaultPolicy(retrier.RetryPolicyNever))...)
+	fn := func() (int, error) {
+		return 0, errors.New("plain")
+	}
+
+	if result := retrier.Do(context.Background(), r, fn); result.Attempts() != 1 {
+		t.Errorf("expected plain errors to fail fast, got %d attempts", result.Attempts())
+	}
+	if result := retrier.Do(context.Background(), r, fn, retrier.WithDefaultPolicy(retrier.RetryPolicyAuto)); result.Attempts() != 3 {
+		t.Errorf("expected the per-call policy to retry, got %d attempts", result.Attempts())
+	}
+}
+
+// TestRetrier_UpdateOptions verifies that updates apply to subsequent calls
+// and notify listeners.
+func TestRetrier_UpdateOptions(t *testing.T) {
+	r := retrier.NewRetrier(noopLogger, retrier.WithMaxAttempts(4))
+
+	var events [][2]retrier.Options
+	r.OnUpdate(func(previous, current retrier.Options) {
+		events = append(events, [2]retrier.Options{previous, current})
+	})
+	r.UpdateOptions(retrier.WithMaxAttempts(1), retrier.WithInitialDuration(time.Millisecond))
+
+	if r.Options().MaxAttempts != 1 {
+		t.Errorf("expected 1 attempt after update, got %d", r.Options().MaxAttempts)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 update event, got %d", len(events))
+	}
+	if events[0][0].MaxAttempts != 4 || events[0][1].MaxAttempts != 1 || events[0][1].InitialDuration != time.Millisecond {
+		t.Errorf("unexpected update event %+v", events[0])
+	}
+
+	callCount := 0
+	retrier.Do(context.Background(), r, func() (string, error) {
+		callCount++
+		return "", errors.New("transient")
+	})
+	if callCount != 1 {
+		t.Errorf("expected 1 call, got %d", callCount)
+	}
+}
+
+// TestRetrier_InFlightKeepsSnapshot verifies that a running call is not
+// affected by an update.
+func TestRetrier_InFlightKeepsSnapshot(t *testing.T) {
+	r := retrier.NewRetrier(noopLogger, retrier.WithMaxAttempts(3), retrier.WithInitialDuration(time.Millisecond))
+	started := make(chan struct{})
+	updated := make(chan struct{})
+	callCount := 0
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		retrier.Do(context.Background(), r, func() (string, error) {
+			callCount++
+			if callCount == 1 {
+				close(started)
+				<-updated
+			}
+			return "", errors.New("transient")
+		})
+	}()
+
+	<-started
+	r.UpdateOptions(retrier.WithMaxAttempts(1))
+	close(updated)
+	wg.Wait()
+
+	if callCount != 3 {
+		t.Errorf("expected the in-flight call to keep 3 attempts, got %d", callCount)
+	}
+}
+
+// TestRetrier_AsPolicyProvider verifies that a Retrier can drive plain Retry calls.
+func TestRetrier_AsPolicyProvider(t *testing.T) {
+	r := retrier.NewRetrier(noopLogger, retrier.WithMaxAttempts(7))
+	got := retrier.ResolveOptions(retrier.WithPolicyProvider(r))
+	if got.MaxAttempts != 7 {
+		t.Errorf("expected 7 attempts, got %d", got.MaxAttempts)
+	}
+}
+
+// TestRetrier_Stats verifies the call counters.
+func TestRetrier_Stats(t *testing.T) {
+	r := retrier.NewRetrier(noopLogger, append(defaultTestOpts(), retrier.WithMaxAttempts(3))...)
+	callCount := 0
+	retrier.Do(context.Background(), r, func() (string, error) {
+		callCount++
+		if callCount < 2 {
+			return "", errors.New("transient")
+		}
+		return "ok", nil
+	})
+	retrier.Do(context.Background(), r, func() (string, error) {
+		return "", errors.New("transient")
+	})
+
+	want := retrier.RetrierStats{Calls: 2, Successes: 1, Failures: 1, Retries: 3}
+	if got := r.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/retriertest"
+)
+
+// flakyRun is a retriertest.RunFunc whose attempts always fail.
+func flakyRun(ctx context.Context, opts ...retrier.RetryOption) retrier.Result[int] {
+	opts = append([]retrier.RetryOption{retrier.WithMaxAttempts(5), retrier.WithInitialDuration(time.Second)}, opts...)
+	return retrier.Retry(ctx, noopLogger, func() (int, error) {
+		return 0, errors.New("unavailable")
+	}, opts...)
+}
+
+// TestCancelDuringBackoff tests the scenario against a correct integration.
+func TestCancelDuringBackoff(t *testing.T) {
+	result := retriertest.CancelDuringBackoff(t, flakyRun)
+	if result.Attempts() != 1 {
+		t.Errorf("Attempts() = %d, want 1", result.Attempts())
+	}
+}
+
+// TestCancelDuringBackoff_NoBackoff tests that the scenario reports code whose
+// first attempt does not back off.
+func TestCancelDuringBackoff_NoBackoff(t *testing.T) {
+	tb := &recordingTB{}
+	retriertest.CancelDuringBackoff(tb, func(ctx context.Context, opts ...retrier.RetryOption) retrier.Result[int] {
+		return retrier.Retry(ctx, noopLogger, func() (int, error) {
+			return 0, retrier.Permanent(errors.New("bad request"))
+		}, opts...)
+	})
+	if len(tb.errors) != 1 || !containsString(tb.errors[0], "without backing off") {
+		t.Errorf("expected a report about the missing backoff, got %v", tb.errors)
+	}
+}
+
+// TestCancelMidAttempt tests the scenario against a correct integration and
+// against one that drops the caller's context.
+func TestCancelMidAttempt(t *testing.T) {
+	result := retriertest.CancelMidAttempt(t, 3, flakyRun)
+	if result.Attempts() != 3 {
+		t.Errorf("Attempts() = %d, want 3", result.Attempts())
+	}
+
+	tb := &recordingTB{}
+	retriertest.CancelMidAttempt(tb, 2, func(_ context.Context, opts ...retrier.RetryOption) retrier.Result[int] {
+		return flakyRun(context.Background(), opts...)
+	})
+	if len(tb.errors) == 0 || !containsString(tb.errors[0], "made 5 attempts") {
+		t.Errorf("expected a report about the ignored cancellation, got %v", tb.errors)
+	}
+}
+
+// TestDeadlineAtBackoffBoundary tests both orders of a deadline expiring as a
+// backoff delay ends.
+func TestDeadlineAtBackoffBoundary(t *testing.T) {
+	deadlineFirst, wakeFirst := retriertest.DeadlineAtBackoffBoundary(t, flakyRun)
+	if deadlineFirst.Attempts() != 1 || wakeFirst.Attempts() != 2 {
+		t.Errorf("got %d and %d attempts, want 1 and 2", deadlineFirst.Attempts(), wakeFirst.Attempts())
+	}
+	if !errors.Is(wakeFirst.Err(), context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", wakeFirst.Err())
+	}
+}
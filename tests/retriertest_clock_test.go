@@ -0,0 +1,104 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/retriertest"
+)
+
+// TestFakeClock_Timers tests that timers fire only when the fake time reaches them.
+func TestFakeClock_Timers(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := retriertest.NewFakeClock(start)
+
+	late := clock.NewTimer(2 * time.Second)
+	early := clock.NewTimer(time.Second)
+	stopped := clock.NewTimer(time.Second)
+	if !stopped.Stop() || stopped.Stop() {
+		t.Error("expected Stop to succeed once")
+	}
+	if clock.Pending() != 2 {
+		t.Errorf("Pending() = %d, want 2", clock.Pending())
+	}
+	if next, ok := clock.NextDeadline(); !ok || !next.Equal(start.Add(time.Second)) {
+		t.Errorf("NextDeadline() = %v, %v", next, ok)
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case <-early.C():
+	default:
+		t.Error("expected the 1s timer to fire")
+	}
+	select {
+	case <-late.C():
+		t.Error("expected the 2s timer not to fire yet")
+	default:
+	}
+	if !clock.Now().Equal(start.Add(time.Second)) {
+		t.Errorf("Now() = %v", clock.Now())
+	}
+
+	clock.Advance(time.Second)
+	<-late.C()
+	if late.Stop() {
+		t.Error("expected Stop to fail on a fired timer")
+	}
+}
+
+// TestFakeClock_WithDeadline tests contexts whose deadline follows the fake time.
+func TestFakeClock_WithDeadline(t *testing.T) {
+	clock := retriertest.NewFakeClock(time.Unix(0, 0))
+	ctx, cancel := clock.WithDeadline(context.Background(), time.Unix(5, 0))
+	defer cancel()
+
+	if d, ok := ctx.Deadline(); !ok || !d.Equal(time.Unix(5, 0)) {
+		t.Errorf("Deadline() = %v, %v", d, ok)
+	}
+	clock.Advance(4 * time.Second)
+	if ctx.Err() != nil {
+		t.Fatalf("expected the context to be alive, got %v", ctx.Err())
+	}
+	clock.Advance(time.Second)
+	<-ctx.Done()
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Err() = %v, want DeadlineExceeded", ctx.Err())
+	}
+
+	other, cancelOther := clock.WithDeadline(context.Background(), time.Unix(60, 0))
+	cancelOther()
+	if !errors.Is(other.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want Canceled", other.Err())
+	}
+}
+
+// TestFakeClock_Retry tests that Retry with a FakeClock waits on fake time and
+// timestamps attempts with it.
+func TestFakeClock_Retry(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := retriertest.NewFakeClock(start)
+	done := make(chan retrier.Result[int], 1)
+
+	go func() {
+		done <- retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+			return 0, errors.New("down")
+		}, retrier.WithMaxAttempts(2), retrier.WithInitialDuration(time.Hour), retrier.WithMaxDuration(time.Hour), retrier.WithClock(clock))
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	result := <-done
+
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrExhaustedAttempts {
+		t.Fatalf("expected ErrExhaustedAttempts, got %v", result.Err())
+	}
+	history := retryErr.History()
+	if len(history) != 2 || !history[0].Time.Equal(start) || !history[1].Time.Equal(start.Add(time.Hour)) {
+		t.Errorf("expected attempts timestamped with fake time, got %v", history)
+	}
+}
@@ -0,0 +1,73 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/retriertest"
+)
+
+// TestFailThenSucceed verifies that a scripted function fails the scripted
+// number of times, then succeeds, and that its attempts and delays are
+// recorded.
+func TestFailThenSucceed(t *testing.T) {
+	script := retriertest.FailThenSucceed(2, retriertest.Transient("unavailable"), "ok")
+	rec := retriertest.NewRecorder()
+	logger := &retriertest.Logger{}
+	result := retrier.Retry(context.Background(), logger, script.Call,
+		retrier.WithInitialDuration(time.Millisecond),
+		retrier.WithMultiplier(2),
+		retrier.WithDefaultPolicy(retrier.RetryPolicyNever), // only Transient errors are retried
+		rec.Option(),
+	)
+
+	if result.Err() != nil || result.Value() != "ok" || script.Calls() != 3 {
+		t.Fatalf("expected ok after 3 calls, got %q after %d: %v", result.Value(), script.Calls(), result.Err())
+	}
+	retriertest.AssertAttempts(t, rec.Last(), 3)
+	retriertest.AssertDelays(t, rec.Last(), time.Millisecond, 2*time.Millisecond)
+	if calls := logger.Calls(); len(calls) != 3 || calls[0].Backoff != time.Millisecond || calls[2].Err != nil {
+		t.Errorf("expected two retries and a success logged, got %+v", calls)
+	}
+}
+
+// TestScript_Steps verifies that a Script returns its steps in order and
+// repeats the last one.
+func TestScript_Steps(t *testing.T) {
+	bad := errors.New("bad")
+	script := retriertest.NewScript(retriertest.Step[int]{Err: bad}, retriertest.Step[int]{Value: 7})
+	for i, want := range []int{0, 7, 7} {
+		value, err := script.CallCtx(context.Background())
+		if value != want || (i == 0) != (err != nil) {
+			t.Errorf("call %d: expected %d, got %d, %v", i+1, want, value, err)
+		}
+	}
+
+	if _, err := retriertest.AlwaysFail[int](bad).Call(); !errors.Is(err, bad) {
+		t.Errorf("expected %v, got %v", bad, err)
+	}
+}
+
+// TestAssertDelays verifies that mismatched attempts and delays are
+// reported.
+func TestAssertDelays(t *testing.T) {
+	rec := retrier.Recording{Attempts: []retrier.RecordedAttempt{
+		{Err: "down", Delay: time.Second},
+		{Err: "down"},
+	}}
+	tb := &recordingTB{}
+	retriertest.AssertAttempts(tb, rec, 2)
+	retriertest.AssertDelays(tb, rec, time.Second)
+	if len(tb.errors) != 0 {
+		t.Fatalf("expected no report, got %v", tb.errors)
+	}
+
+	retriertest.AssertAttempts(tb, rec, 3)
+	retriertest.AssertDelays(tb, rec, 2*time.Second)
+	if len(tb.errors) != 2 {
+		t.Errorf("expected two reports, got %v", tb.errors)
+	}
+}
@@ -0,0 +1,96 @@
+package retrier_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/retriertest"
+)
+
+// recordingTB captures the failures reported through testing.TB.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// TestResultEqual tests Result comparison including error chains.
+func TestResultEqual(t *testing.T) {
+	cause := errors.New("connection refused")
+	exhausted := func(err error) error {
+		return retrier.NewRetryError(retrier.ErrExhaustedAttempts, "exhausted 3 attempts", retrier.RetryPolicyManual, err)
+	}
+
+	tests := []struct {
+		name  string
+		x, y  retrier.Result[[]int]
+		equal bool
+	}{
+		{"equal successes", retrier.NewSuccessResult([]int{1, 2}, 1), retrier.NewSuccessResult([]int{1, 2}, 1), true},
+		{"different values", retrier.NewSuccessResult([]int{1}, 1), retrier.NewSuccessResult([]int{2}, 1), false},
+		{"different attempts", retrier.NewSuccessResult([]int{1}, 1), retrier.NewSuccessResult([]int{1}, 2), false},
+		{
+			"equal chains from distinct errors",
+			retrier.NewFailureResult[[]int](exhausted(cause), 3),
+			retrier.NewFailureResult[[]int](exhausted(errors.New("connection refused")), 3),
+			true,
+		},
+		{
+			"different inner errors",
+			retrier.NewFailureResult[[]int](exhausted(cause), 3),
+			retrier.NewFailureResult[[]int](exhausted(errors.New("timeout")), 3),
+			false,
+		},
+		{
+			"different causes",
+			retrier.NewFailureResult[[]int](exhausted(cause), 3),
+			retrier.NewFailureResult[[]int](retrier.NewRetryError(retrier.ErrContextCancelled, "exhausted 3 attempts", retrier.RetryPolicyNever, cause), 3),
+			false,
+		},
+		{
+			"joined errors",
+			retrier.NewFailureResult[[]int](errors.Join(cause, errors.New("b")), 1),
+			retrier.NewFailureResult[[]int](errors.Join(cause, errors.New("b")), 1),
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retriertest.ResultEqual(tt.x, tt.y); got != tt.equal {
+				t.Errorf("ResultEqual() = %v, want %v; diff:\n%s", got, tt.equal, retriertest.ResultDiff(tt.x, tt.y))
+			}
+		})
+	}
+}
+
+// TestAssertResultEqual tests the reported diff.
+func TestAssertResultEqual(t *testing.T) {
+	tb := &recordingTB{}
+	got := retrier.NewFailureResult[string](errors.New("boom"), 2)
+	want := retrier.NewSuccessResult("ok", 1)
+
+	retriertest.AssertResultEqual(tb, got, want)
+
+	if len(tb.errors) != 1 {
+		t.Fatalf("expected 1 reported error, got %d", len(tb.errors))
+	}
+	for _, fragment := range []string{`-  "ok"`, `+  ""`, "-  1", "+  2", "+  *errors.errorString: boom"} {
+		if !containsString(tb.errors[0], fragment) {
+			t.Errorf("expected diff to contain %q, got:\n%s", fragment, tb.errors[0])
+		}
+	}
+
+	tb = &recordingTB{}
+	retriertest.AssertResultEqual(tb, want, retrier.NewSuccessResult("ok", 1))
+	if len(tb.errors) != 0 {
+		t.Errorf("expected no error for equal results, got %v", tb.errors)
+	}
+}
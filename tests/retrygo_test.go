@@ -0,0 +1,107 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/retrygo"
+)
+
+// fastRetryGoOpts returns retry-go options with short delays for testing.
+func fastRetryGoOpts(opts ...retrygo.Option) []retrygo.Option {
+	return append([]retrygo.Option{retrygo.Delay(time.Millisecond), retrygo.MaxJitter(0)}, opts...)
+}
+
+// TestRetryGo_DoSucceeds verifies that Do retries until fn succeeds.
+func TestRetryGo_DoSucceeds(t *testing.T) {
+	callCount := 0
+	err := retrygo.Do(func() error {
+		callCount++
+		if callCount < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	}, fastRetryGoOpts()...)
+
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if callCount != 3 {
+		t.Errorf("expected 3 calls, got %d", callCount)
+	}
+}
+
+// TestRetryGo_Attempts verifies the attempt limit and the returned error.
+func TestRetryGo_Attempts(t *testing.T) {
+	innerErr := errors.New("transient")
+	fn := func() error { return innerErr }
+
+	err := retrygo.Do(fn, fastRetryGoOpts(retrygo.Attempts(2))...)
+	var retryErr *retrier.RetryError
+	if !errors.As(err, &retryErr) || retryErr.Cause != retrier.ErrExhaustedAttempts {
+		t.Fatalf("expected ErrExhaustedAttempts, got %v", err)
+	}
+
+	err = retrygo.Do(fn, fastRetryGoOpts(retrygo.Attempts(2), retrygo.LastErrorOnly(true))...)
+	if err != innerErr {
+		t.Errorf("expected the last error with LastErrorOnly, got %v", err)
+	}
+}
+
+// TestRetryGo_OnRetryAndRetryIf verifies the callback numbering and the predicate.
+func TestRetryGo_OnRetryAndRetryIf(t *testing.T) {
+	stop := errors.New("stop")
+	callCount := 0
+	var retries []uint
+	value, err := retrygo.DoWithData(func() (int, error) {
+		callCount++
+		if callCount < 3 {
+			return 0, errors.New("transient")
+		}
+		return callCount, stop
+	}, fastRetryGoOpts(
+		retrygo.OnRetry(func(n uint, _ error) { retries = append(retries, n) }),
+		retrygo.RetryIf(func(err error) bool { return err != stop }),
+	)...)
+
+	if err != stop || value != 0 {
+		t.Errorf("DoWithData() = %d, %v; want 0, %v", value, err, stop)
+	}
+	if len(retries) != 2 || retries[0] != 0 || retries[1] != 1 {
+		t.Errorf("expected OnRetry with [0 1], got %v", retries)
+	}
+}
+
+// TestRetryGo_ZeroAttemptsUntilContextDone verifies that Attempts(0) retries
+// until the context is done.
+func TestRetryGo_ZeroAttemptsUntilContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	callCount := 0
+	err := retrygo.Do(func() error {
+		callCount++
+		if callCount == 20 {
+			cancel()
+		}
+		return errors.New("transient")
+	}, fastRetryGoOpts(retrygo.Attempts(0), retrygo.MaxDelay(time.Millisecond), retrygo.Context(ctx))...)
+
+	var retryErr *retrier.RetryError
+	if !errors.As(err, &retryErr) || retryErr.Cause != retrier.ErrContextCancelled {
+		t.Fatalf("expected ErrContextCancelled, got %v", err)
+	}
+	if callCount != 20 {
+		t.Errorf("expected 20 calls, got %d", callCount)
+	}
+}
+
+// TestRetryGo_RetryOptions verifies the translation to retrier options.
+func TestRetryGo_RetryOptions(t *testing.T) {
+	got := retrier.ResolveOptions(retrygo.RetryOptions(retrygo.Attempts(4), retrygo.Delay(50*time.Millisecond))...)
+
+	if got.MaxAttempts != 4 || got.InitialDuration != 50*time.Millisecond || got.Jitter != 100*time.Millisecond {
+		t.Errorf("unexpected options %+v", got)
+	}
+}
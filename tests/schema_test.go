@@ -0,0 +1,154 @@
+package retrier_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// The golden records below pin the wire format of each schema version: a
+// failing comparison means a change that breaks downstream consumers, which
+// belongs in a new version instead.
+
+// TestEventV1_Golden verifies that EventV1 encodes to, and decodes from, its
+// golden record.
+func TestEventV1_Golden(t *testing.T) {
+	event := retrier.RetryEvent{
+		Kind:        retrier.EventRetry,
+		Attempt:     2,
+		MaxAttempts: 5,
+		Backoff:     1500 * time.Millisecond,
+		RawBackoff:  2 * time.Second,
+		Err:         errors.New("503 Service Unavailable"),
+		Cause:       errors.New("attempt timed out"),
+		Time:        time.Date(2026, 3, 1, 14, 2, 11, 0, time.UTC),
+		Decision: &retrier.Decision{
+			Attempt:     2,
+			Retry:       true,
+			Rule:        retrier.RuleDefaultPolicy,
+			Reason:      "standard error with default policy auto",
+			Delay:       1500 * time.Millisecond,
+			DelaySource: "backoff",
+		},
+	}
+	checkGolden(t, "testdata/event.v1.json", event.V1())
+}
+
+// TestSummaryV1_Golden verifies that SummaryV1 encodes to, and decodes from,
+// its golden record.
+func TestSummaryV1_Golden(t *testing.T) {
+	summary := retrier.Summary{
+		Attempts:          3,
+		FailureKind:       string(retrier.ErrExhaustedAttempts),
+		TotalLatency:      3200 * time.Millisecond,
+		BackoffLatency:    3 * time.Second,
+		CappedRetries:     1,
+		SuccessAfterRetry: false,
+	}
+	checkGolden(t, "testdata/summary.v1.json", summary.V1())
+}
+
+// TestJSONSchema_Fields verifies that the JSON Schema of each version
+// describes every field of its record, and requires exactly those always
+// encoded.
+func TestJSONSchema_Fields(t *testing.T) {
+	tests := []struct {
+		schema string
+		record any
+	}{
+		{retrier.EventSchemaV1, retrier.EventV1{}},
+		{retrier.SummarySchemaV1, retrier.SummaryV1{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			doc, ok := retrier.JSONSchema(tt.schema)
+			if !ok {
+				t.Fatalf("no JSON Schema for %s", tt.schema)
+			}
+			var schema jsonSchema
+			if err := json.Unmarshal(doc, &schema); err != nil {
+				t.Fatalf("invalid JSON Schema: %v", err)
+			}
+			if schema.ID != tt.schema {
+				t.Errorf("expected $id %q, got %q", tt.schema, schema.ID)
+			}
+			checkSchemaFields(t, "", schema, reflect.TypeOf(tt.record))
+		})
+	}
+
+	if _, ok := retrier.JSONSchema("retrier.event.v0"); ok {
+		t.Error("expected no JSON Schema for an unknown version")
+	}
+}
+
+// jsonSchema is the part of a JSON Schema document the tests check.
+type jsonSchema struct {
+	ID         string                `json:"$id"`
+	Required   []string              `json:"required"`
+	Properties map[string]jsonSchema `json:"properties"`
+}
+
+// checkSchemaFields reports the fields of record missing from schema, and
+// the mismatches between its required fields and those record always
+// encodes.
+func checkSchemaFields(t *testing.T, path string, schema jsonSchema, record reflect.Type) {
+	t.Helper()
+	if record.Kind() == reflect.Pointer {
+		record = record.Elem()
+	}
+	var required []string
+	for i := range record.NumField() {
+		field := record.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		property, ok := schema.Properties[name]
+		if !ok {
+			t.Errorf("field %s%s is not in the schema", path, name)
+			continue
+		}
+		if opts != "omitempty" {
+			required = append(required, name)
+		}
+		if field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct {
+			checkSchemaFields(t, path+name+".", property, field.Type)
+		}
+	}
+	slices.Sort(required)
+	got := slices.Sorted(slices.Values(schema.Required))
+	if !slices.Equal(got, required) {
+		t.Errorf("expected %srequired %v, got %v", path, required, got)
+	}
+}
+
+// checkGolden compares the JSON encoding of record with the golden file at
+// path, and decodes the file back into a record equal to it.
+func checkGolden[R any](t *testing.T, path string, record R) {
+	t.Helper()
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(encoded) + "\n"; got != string(golden) {
+		t.Errorf("encoding changed:\n%s", got)
+	}
+
+	var decoded R
+	decoder := json.NewDecoder(bytes.NewReader(golden))
+	if err := decoder.Decode(&decoded); err != nil {
+		t.Fatalf("decode golden record: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, record) {
+		t.Errorf("expected %+v, decoded %+v", record, decoded)
+	}
+}
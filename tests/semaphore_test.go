@@ -0,0 +1,92 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestWithSemaphore_GatesAttempts verifies that attempts never exceed the
+// semaphore's capacity and that backoff delays do not hold it.
+func TestWithSemaphore_GatesAttempts(t *testing.T) {
+	pool := make(chan struct{}, 2)
+	sem := retrier.ChanSemaphore(pool)
+	var running, peak atomic.Int32
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			calls := 0
+			retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(2 * time.Millisecond)
+				calls++
+				if calls < 2 {
+					return 0, errors.New("transient")
+				}
+				return 1, nil
+			}, append(defaultTestOpts(), retrier.WithSemaphore(sem))...)
+		}()
+	}
+	wg.Wait()
+
+	if peak.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent attempts, got %d", peak.Load())
+	}
+	if len(pool) != 0 {
+		t.Errorf("expected every unit to be released, %d held", len(pool))
+	}
+}
+
+// TestWithSemaphore_SharedWithOtherCalls verifies that a unit held outside of
+// Retry delays the attempt, and that cancellation while waiting ends Retry.
+func TestWithSemaphore_SharedWithOtherCalls(t *testing.T) {
+	pool := make(chan struct{}, 1)
+	sem := retrier.ChanSemaphore(pool)
+	if err := sem.Acquire(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	calls := 0
+	result := retrier.Retry(ctx, noopLogger, func() (int, error) {
+		calls++
+		return 1, nil
+	}, append(defaultTestOpts(), retrier.WithSemaphore(sem))...)
+
+	if calls != 0 {
+		t.Errorf("expected no attempt while the semaphore is held, got %d", calls)
+	}
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrContextCancelled {
+		t.Fatalf("expected ErrContextCancelled, got %v", result.Err())
+	}
+	if !errors.Is(result.Err(), context.DeadlineExceeded) {
+		t.Errorf("expected the context error in the chain, got %v", result.Err())
+	}
+	if result.Attempts() != 0 {
+		t.Errorf("expected 0 attempts, got %d", result.Attempts())
+	}
+
+	sem.Release(1)
+	result = retrier.Retry(context.Background(), noopLogger, func() (int, error) { return 1, nil },
+		append(defaultTestOpts(), retrier.WithSemaphore(sem))...)
+	if result.IsFailure() || len(pool) != 0 {
+		t.Errorf("expected success with the unit released, got %v and %d held", result.Err(), len(pool))
+	}
+}
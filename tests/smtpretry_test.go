@@ -0,0 +1,90 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/textproto"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/smtpretry"
+)
+
+// TestSMTPRetry_ReplyCode tests reply code extraction.
+func TestSMTPRetry_ReplyCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   int
+		wantOK bool
+	}{
+		{"textproto error", &textproto.Error{Code: 451, Msg: "4.3.0 local error"}, 451, true},
+		{"wrapped textproto error", fmt.Errorf("send: %w", &textproto.Error{Code: 550, Msg: "no such user"}), 550, true},
+		{"message prefix", errors.New("421 4.7.0 too many connections"), 421, true},
+		{"no code", errors.New("dial tcp: connection refused"), 0, false},
+		{"nil", nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := smtpretry.ReplyCode(tt.err)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ReplyCode() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestSMTPRetry_RetryPolicy tests transient/permanent classification.
+func TestSMTPRetry_RetryPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want retrier.RetryPolicy
+	}{
+		{"4xx", &textproto.Error{Code: 452, Msg: "insufficient storage"}, retrier.RetryPolicyAuto},
+		{"5xx", &textproto.Error{Code: 550, Msg: "5.1.1 user unknown"}, retrier.RetryPolicyNever},
+		{"greylisted 4xx", &textproto.Error{Code: 450, Msg: "4.2.0 Greylisted, please retry later"}, retrier.RetryPolicyAuto},
+		{"greylisted 5xx", errors.New("554 temporarily rejected by greylisting"), retrier.RetryPolicyAuto},
+		{"network error", errors.New("dial tcp: i/o timeout"), retrier.RetryPolicyAuto},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := smtpretry.RetryPolicy(tt.err); got != tt.want {
+				t.Errorf("RetryPolicy() = %v, want %v", got, tt.want)
+			}
+			if got := smtpretry.RetryIf(tt.err); got != (tt.want == retrier.RetryPolicyAuto) {
+				t.Errorf("RetryIf() = %v", got)
+			}
+		})
+	}
+	if !smtpretry.IsGreylisted(errors.New("451 Greylisting in action")) || smtpretry.IsGreylisted(nil) {
+		t.Error("unexpected IsGreylisted result")
+	}
+}
+
+// TestSMTPRetry_Options tests the mail delivery preset.
+func TestSMTPRetry_Options(t *testing.T) {
+	o := retrier.ResolveOptions(smtpretry.Options()...)
+	if o.MaxAttempts != 8 || o.InitialDuration != 5*time.Minute || o.MaxDuration != 4*time.Hour || o.Jitter != 10*time.Minute {
+		t.Errorf("unexpected preset: %+v", o)
+	}
+
+	calls := 0
+	opts := append(smtpretry.Options(), defaultTestOpts()...)
+	opts = append(opts, retrier.WithJitter(0))
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, &textproto.Error{Code: 421, Msg: "service not available"}
+		}
+		return 0, &textproto.Error{Code: 550, Msg: "mailbox unavailable"}
+	}, opts...)
+	if calls != 2 {
+		t.Errorf("expected the 5xx reply to stop retries after 2 calls, got %d", calls)
+	}
+	if code, _ := smtpretry.ReplyCode(result.Err()); code != 550 {
+		t.Errorf("expected the 550 reply, got %v", result.Err())
+	}
+}
@@ -0,0 +1,98 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestWithSoftFail_Degraded verifies that a call failing after a Degraded
+// attempt succeeds with its value and warnings.
+func TestWithSoftFail_Degraded(t *testing.T) {
+	enrich := errors.New("enrichment unavailable")
+	down := errors.New("down")
+	calls := 0
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		calls++
+		if calls == 2 {
+			return 42, retrier.Degraded(enrich)
+		}
+		return 0, down
+	}, append(defaultTestOpts(), retrier.WithSoftFail())...)
+
+	if result.Err() != nil || !result.IsSuccess() || !result.IsDegraded() {
+		t.Fatalf("expected a degraded success, got %v", result.Err())
+	}
+	if result.Value() != 42 || result.Attempts() != 3 {
+		t.Errorf("expected value 42 after 3 attempts, got %d after %d", result.Value(), result.Attempts())
+	}
+	warnings := result.Warnings()
+	if len(warnings) != 2 || !errors.Is(warnings[0], enrich) || !errors.Is(warnings[1], down) {
+		t.Errorf("expected the degraded error then the final error, got %v", warnings)
+	}
+}
+
+// TestWithSoftFail_SuccessWins verifies that an attempt succeeding after a
+// Degraded one returns a plain success.
+func TestWithSoftFail_SuccessWins(t *testing.T) {
+	calls := 0
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		calls++
+		if calls == 1 {
+			return 1, retrier.Degraded(errors.New("partial"))
+		}
+		return 2, nil
+	}, append(defaultTestOpts(), retrier.WithSoftFail())...)
+
+	if result.Err() != nil || result.IsDegraded() || result.Value() != 2 || result.Warnings() != nil {
+		t.Errorf("expected a plain success with 2, got %d, %v, %v", result.Value(), result.Err(), result.Warnings())
+	}
+}
+
+// TestWithSoftFail_Unusable verifies that a Degraded value rejected by the
+// result check, or returned without WithSoftFail, is not returned.
+func TestWithSoftFail_Unusable(t *testing.T) {
+	fn := func() (int, error) {
+		return -1, retrier.Permanent(retrier.Degraded(errors.New("partial")))
+	}
+	tests := []struct {
+		name string
+		opts []retrier.RetryOption
+	}{
+		{"without soft fail", nil},
+		{"rejected by the result check", []retrier.RetryOption{
+			retrier.WithSoftFail(),
+			retrier.WithRetryOnResult(func(n int) error {
+				if n < 0 {
+					return errors.New("negative")
+				}
+				return nil
+			}),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := retrier.Retry(context.Background(), noopLogger, fn, append(defaultTestOpts(), tt.opts...)...)
+			if result.Err() == nil || result.IsDegraded() || result.Value() != 0 {
+				t.Errorf("expected a failure, got %d, %v", result.Value(), result.Err())
+			}
+		})
+	}
+}
+
+// TestWithSoftFail_Permanent verifies that a Degraded error classified as
+// permanent is returned once, as the only warning.
+func TestWithSoftFail_Permanent(t *testing.T) {
+	result := retrier.Retry(context.Background(), noopLogger, func() (string, error) {
+		return "partial", retrier.Degraded(retrier.Permanent(errors.New("cache write failed")))
+	}, append(defaultTestOpts(), retrier.WithSoftFail())...)
+
+	if !result.IsDegraded() || result.Value() != "partial" || result.Attempts() != 1 {
+		t.Fatalf("expected a degraded success after 1 attempt, got %q after %d: %v", result.Value(), result.Attempts(), result.Err())
+	}
+	if len(result.Warnings()) != 1 {
+		t.Errorf("expected one warning, got %v", result.Warnings())
+	}
+}
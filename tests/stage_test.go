@@ -0,0 +1,160 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestStage_ProcessesAllItems tests that every item is retried and its outcome
+// sent, and that out is closed afterwards.
+func TestStage_ProcessesAllItems(t *testing.T) {
+	in := make(chan int)
+	out := make(chan retrier.Result[int])
+	var seen sync.Map
+
+	go func() {
+		for i := 1; i <= 10; i++ {
+			in <- i
+		}
+		close(in)
+	}()
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- retrier.Stage(context.Background(), noopLogger, in, out, 3,
+			func(ctx context.Context, item int) (int, error) {
+				if _, retried := seen.LoadOrStore(item, true); !retried && item%2 == 0 {
+					return 0, errors.New("flaky")
+				}
+				if item == 7 {
+					return 0, retrier.Permanent(errors.New("bad item"))
+				}
+				return item * item, nil
+			}, defaultTestOpts()...)
+	}()
+
+	var values []int
+	failures := 0
+	for result := range out {
+		if result.IsFailure() {
+			failures++
+			continue
+		}
+		values = append(values, result.Value())
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("Stage() error = %v", err)
+	}
+
+	sort.Ints(values)
+	want := []int{1, 4, 9, 16, 25, 36, 64, 81, 100}
+	if len(values) != len(want) || failures != 1 {
+		t.Fatalf("got values %v and %d failures, want %v and 1 failure", values, failures, want)
+	}
+	for i := range want {
+		if values[i] != want[i] {
+			t.Errorf("values = %v, want %v", values, want)
+			break
+		}
+	}
+}
+
+// TestStage_BoundedConcurrency tests that at most workers items are processed at once.
+func TestStage_BoundedConcurrency(t *testing.T) {
+	in := make(chan int, 8)
+	for i := 0; i < 8; i++ {
+		in <- i
+	}
+	close(in)
+	out := make(chan retrier.Result[int], 8)
+
+	var running, peak atomic.Int32
+	err := retrier.Stage(context.Background(), noopLogger, in, out, 2,
+		func(ctx context.Context, item int) (int, error) {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return item, nil
+		}, defaultTestOpts()...)
+	if err != nil {
+		t.Fatalf("Stage() error = %v", err)
+	}
+	if peak.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent items, got %d", peak.Load())
+	}
+	if len(out) != 8 {
+		t.Errorf("expected 8 outcomes, got %d", len(out))
+	}
+}
+
+// TestStage_ContextCancelled tests that Stage stops and closes out when ctx is cancelled.
+func TestStage_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int) // never closed
+	out := make(chan retrier.Result[int])
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- retrier.Stage(ctx, noopLogger, in, out, 2,
+			func(ctx context.Context, item int) (int, error) { return item, nil }, defaultTestOpts()...)
+	}()
+	cancel()
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Stage() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stage did not return after cancellation")
+	}
+	if _, ok := <-out; ok {
+		t.Error("expected out to be closed")
+	}
+}
+
+// TestStage_AttemptContext tests that fn receives the attempt context, so per-attempt
+// deadlines cut a hanging attempt short.
+func TestStage_AttemptContext(t *testing.T) {
+	in := make(chan int, 1)
+	out := make(chan retrier.Result[int], 1)
+	in <- 1
+	close(in)
+
+	var attempts atomic.Int32
+	var cutShort atomic.Bool
+	err := retrier.Stage(context.Background(), noopLogger, in, out, 1,
+		func(ctx context.Context, item int) (int, error) {
+			if attempts.Add(1) > 1 {
+				return item, nil
+			}
+			select {
+			case <-ctx.Done():
+				cutShort.Store(true)
+				return 0, ctx.Err()
+			case <-time.After(time.Second):
+				return 0, errors.New("hung")
+			}
+		}, append(defaultTestOpts(), retrier.WithMaxAttempts(2), retrier.WithMaxAttemptDuration(10*time.Millisecond))...)
+	if err != nil {
+		t.Fatalf("Stage() error = %v", err)
+	}
+
+	result := <-out
+	if !cutShort.Load() || result.Value() != 1 {
+		t.Errorf("expected the first attempt cut short and retried, got value %d after %d attempts", result.Value(), result.Attempts())
+	}
+}
@@ -0,0 +1,98 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestWithStrictClassification_Fail verifies that StrictFail stops the loop
+// at the first unclassified error.
+func TestWithStrictClassification_Fail(t *testing.T) {
+	events := make(chan retrier.RetryEvent, 10)
+	logger := &warningLogger{}
+	opts := append(defaultTestOpts(),
+		retrier.WithStrictClassification(retrier.StrictFail),
+		retrier.WithNotifyChannel(events),
+	)
+	boom := errors.New("boom")
+	result := retrier.Retry(context.Background(), logger, func() (int, error) {
+		return 0, boom
+	}, opts...)
+	close(events)
+
+	if result.Attempts() != 1 {
+		t.Errorf("expected 1 attempt, got %d", result.Attempts())
+	}
+	var retryErr *retrier.RetryError
+	if !errors.As(result.Err(), &retryErr) || retryErr.Cause != retrier.ErrUnclassified {
+		t.Fatalf("expected ErrUnclassified, got %v", result.Err())
+	}
+	if !errors.Is(result.Err(), boom) {
+		t.Errorf("expected the error to wrap the attempt error, got %v", result.Err())
+	}
+	unclassified := 0
+	for e := range events {
+		if e.Kind == retrier.EventUnclassified {
+			unclassified++
+		}
+	}
+	if unclassified != 1 {
+		t.Errorf("expected one unclassified event, got %d", unclassified)
+	}
+	if len(logger.warnings) != 1 || logger.warnings[0] != "unclassified error" {
+		t.Errorf("expected one warning, got %v", logger.warnings)
+	}
+}
+
+// TestWithStrictClassification_Warn verifies that StrictWarn reports every
+// unclassified error and keeps retrying.
+func TestWithStrictClassification_Warn(t *testing.T) {
+	logger := &warningLogger{}
+	opts := append(defaultTestOpts(), retrier.WithStrictClassification(retrier.StrictWarn))
+	result := retrier.Retry(context.Background(), logger, func() (int, error) {
+		return 0, errors.New("boom")
+	}, opts...)
+
+	if result.Attempts() != 3 {
+		t.Errorf("expected 3 attempts, got %d", result.Attempts())
+	}
+	if len(logger.warnings) != 3 {
+		t.Errorf("expected a warning per attempt, got %v", logger.warnings)
+	}
+}
+
+// TestWithStrictClassification_Classified verifies that classified errors
+// pass strict mode.
+func TestWithStrictClassification_Classified(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		opts []retrier.RetryOption
+		want int
+	}{
+		{"permanent", retrier.Permanent(errors.New("bad request")), nil, 1},
+		{"retry if", errors.New("boom"), []retrier.RetryOption{
+			retrier.WithRetryIf(func(error) bool { return true }),
+		}, 3},
+		{"context", context.DeadlineExceeded, nil, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &warningLogger{}
+			opts := append(defaultTestOpts(), retrier.WithStrictClassification(retrier.StrictFail))
+			result := retrier.Retry(context.Background(), logger, func() (int, error) {
+				return 0, tt.err
+			}, append(opts, tt.opts...)...)
+
+			if result.Attempts() != tt.want {
+				t.Errorf("expected %d attempts, got %d: %v", tt.want, result.Attempts(), result.Err())
+			}
+			if len(logger.warnings) != 0 {
+				t.Errorf("expected no warning, got %v", logger.warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// runSummarized runs fn with a SummarySink and returns the Summary it got.
+func runSummarized(t *testing.T, ctx context.Context, fn func() (int, error), opts ...retrier.RetryOption) retrier.Summary {
+	t.Helper()
+	var summaries []retrier.Summary
+	opts = append(append(defaultTestOpts(), opts...), retrier.WithSummarySink(func(_ context.Context, s retrier.Summary) {
+		summaries = append(summaries, s)
+	}))
+	retrier.Retry(ctx, noopLogger, fn, opts...)
+	if len(summaries) != 1 {
+		t.Fatalf("expected 1 summary, got %d", len(summaries))
+	}
+	return summaries[0]
+}
+
+// TestWithSummarySink_FirstAttempt verifies the summary of a loop succeeding
+// right away.
+func TestWithSummarySink_FirstAttempt(t *testing.T) {
+	s := runSummarized(t, context.Background(), func() (int, error) { return 1, nil })
+
+	if !s.Succeeded() || s.SuccessAfterRetry || s.Attempts != 1 {
+		t.Errorf("got %+v, want a success after 1 attempt without retry", s)
+	}
+	if s.BackoffLatency != 0 {
+		t.Errorf("expected no backoff latency, got %v", s.BackoffLatency)
+	}
+}
+
+// TestWithSummarySink_SuccessAfterRetry verifies that the time spent in
+// attempts and in backoff delays is told apart.
+func TestWithSummarySink_SuccessAfterRetry(t *testing.T) {
+	calls := 0
+	s := runSummarized(t, context.Background(), func() (int, error) {
+		calls++
+		time.Sleep(20 * time.Millisecond)
+		if calls < 3 {
+			return 0, errors.New("transient")
+		}
+		return 1, nil
+	})
+
+	if !s.Succeeded() || !s.SuccessAfterRetry || s.Attempts != 3 {
+		t.Errorf("got %+v, want a success after retry in 3 attempts", s)
+	}
+	// Backoff delays of 10ms and 20ms, attempts of 20ms each
+	if s.BackoffLatency < 30*time.Millisecond || s.BackoffLatency >= 150*time.Millisecond {
+		t.Errorf("BackoffLatency = %v, want about 30ms", s.BackoffLatency)
+	}
+	if s.WorkLatency() < 60*time.Millisecond {
+		t.Errorf("WorkLatency() = %v, want at least 60ms", s.WorkLatency())
+	}
+	if s.TotalLatency != s.BackoffLatency+s.WorkLatency() {
+		t.Errorf("TotalLatency = %v, want BackoffLatency + WorkLatency()", s.TotalLatency)
+	}
+}
+
+// TestWithSummarySink_FailureKinds verifies the failure kind of loops ending
+// in different ways.
+func TestWithSummarySink_FailureKinds(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want string
+	}{
+		{"exhausted", context.Background(), errors.New("transient"), string(retrier.ErrExhaustedAttempts)},
+		{"permanent", context.Background(), retrier.Permanent(errors.New("bad request")), retrier.FailurePermanent},
+		{"nested retry error", context.Background(), retrier.NewRetryError(retrier.ErrExhaustedAttempts, "inner", retrier.RetryPolicyManual, nil), retrier.FailurePermanent},
+		{"cancelled", cancelled, errors.New("transient"), string(retrier.ErrContextCancelled)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := runSummarized(t, tt.ctx, func() (int, error) { return 0, tt.err }, retrier.WithMaxAttempts(2))
+
+			if s.Succeeded() || s.FailureKind != tt.want {
+				t.Errorf("FailureKind = %q, want %q", s.FailureKind, tt.want)
+			}
+		})
+	}
+}
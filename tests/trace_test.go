@@ -0,0 +1,39 @@
+package retrier_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime/trace"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestWithRuntimeTrace verifies that calls record their task, attempt and
+// backoff regions, and log messages in execution traces.
+func TestWithRuntimeTrace(t *testing.T) {
+	var buf bytes.Buffer
+	if err := trace.Start(&buf); err != nil {
+		t.Skipf("tracing already enabled: %v", err)
+	}
+	calls := 0
+	opts := append(defaultTestOpts(), retrier.WithRuntimeTrace("fetchProfile"))
+	result := retrier.RetryCtx(context.Background(), noopLogger, func(ctx context.Context) (int, error) {
+		calls++
+		if calls < 2 {
+			return 0, errors.New("transient")
+		}
+		return 1, nil
+	}, opts...)
+	trace.Stop()
+
+	if result.IsFailure() {
+		t.Fatalf("expected success, got %v", result.Err())
+	}
+	for _, want := range []string{"fetchProfile", "retrier.attempt", "retrier.backoff", "attempt 1 failed", "succeeded after 2 attempts"} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Errorf("expected the trace to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package retrier_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+	"github.com/rohmanhakim/retrier/retriertest"
+)
+
+// TestLatencyTuner tests the p95 computation and bounds.
+func TestLatencyTuner(t *testing.T) {
+	tuner := retrier.NewLatencyTuner(2, 10*time.Millisecond, time.Second)
+	if _, ok := tuner.P95(); ok {
+		t.Error("expected no p95 before any observation")
+	}
+
+	for i := 1; i <= 9; i++ {
+		tuner.Observe(time.Duration(i) * time.Millisecond)
+	}
+	if _, ok := tuner.InitialDuration(); ok {
+		t.Error("expected no tuning before 10 observations")
+	}
+
+	for i := 10; i <= 100; i++ {
+		tuner.Observe(time.Duration(i) * time.Millisecond)
+	}
+	if p95, _ := tuner.P95(); p95 != 95*time.Millisecond {
+		t.Errorf("P95() = %v, want 95ms", p95)
+	}
+	if d, ok := tuner.InitialDuration(); !ok || d != 190*time.Millisecond {
+		t.Errorf("InitialDuration() = %v, %v, want 190ms", d, ok)
+	}
+
+	// Old latencies leave the window; the result is clamped
+	for i := 0; i < 200; i++ {
+		tuner.Observe(time.Hour)
+	}
+	if d, _ := tuner.InitialDuration(); d != time.Second {
+		t.Errorf("InitialDuration() = %v, want the 1s bound", d)
+	}
+	for i := 0; i < 200; i++ {
+		tuner.Observe(time.Microsecond)
+	}
+	if d, _ := tuner.InitialDuration(); d != 10*time.Millisecond {
+		t.Errorf("InitialDuration() = %v, want the 10ms bound", d)
+	}
+}
+
+// TestWithLatencyTuner verifies that successful attempts are observed and the
+// tuned initial backoff overrides WithInitialDuration.
+func TestWithLatencyTuner(t *testing.T) {
+	clock := retriertest.NewFakeClock(time.Unix(0, 0))
+	tuner := retrier.NewLatencyTuner(3, time.Millisecond, time.Minute)
+	r := retrier.NewRetrier(noopLogger,
+		retrier.WithLatencyTuner(tuner),
+		retrier.WithInitialDuration(time.Second),
+		retrier.WithClock(clock),
+	)
+
+	for i := 0; i < 10; i++ {
+		if got := r.Options().InitialDuration; got != time.Second {
+			t.Fatalf("call %d: expected the static 1s before tuning, got %v", i, got)
+		}
+		retrier.Do(context.Background(), r, func() (int, error) {
+			clock.Advance(20 * time.Millisecond)
+			return 1, nil
+		})
+	}
+
+	if got := r.Options().InitialDuration; got != 60*time.Millisecond {
+		t.Errorf("expected the tuned 60ms, got %v", got)
+	}
+}
@@ -0,0 +1,138 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// twoPhaseRecorder counts the steps of a two-phase operation.
+type twoPhaseRecorder struct {
+	prepares    int
+	confirms    int
+	compensated []error
+}
+
+func (r *twoPhaseRecorder) prepare(context.Context) (string, error) {
+	r.prepares++
+	return "auth-1", nil
+}
+
+func (r *twoPhaseRecorder) compensate(ctx context.Context, prepared string, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	r.compensated = append(r.compensated, err)
+	return nil
+}
+
+// TestRetryTwoPhase verifies that prepare runs once and confirm is retried
+// with the prepared value until it succeeds, without compensation.
+func TestRetryTwoPhase(t *testing.T) {
+	rec := &twoPhaseRecorder{}
+	result := retrier.RetryTwoPhase(context.Background(), noopLogger, rec.prepare,
+		func(ctx context.Context, authID string) (string, error) {
+			rec.confirms++
+			if rec.confirms < 3 {
+				return "", errors.New("gateway timeout")
+			}
+			return "captured " + authID, nil
+		},
+		rec.compensate,
+		[]retrier.RetryOption{retrier.WithMaxAttempts(1)},
+		defaultTestOpts(),
+	)
+
+	if result.Value() != "captured auth-1" || result.Err() != nil {
+		t.Fatalf("unexpected result %q, %v", result.Value(), result.Err())
+	}
+	if rec.prepares != 1 || result.Attempts() != 4 || len(result.History()) != 2 {
+		t.Errorf("expected 1 prepare and 4 attempts with 2 failures, got %d, %d, %d", rec.prepares, result.Attempts(), len(result.History()))
+	}
+	if len(rec.compensated) != 0 {
+		t.Errorf("expected no compensation, got %v", rec.compensated)
+	}
+}
+
+// TestRetryTwoPhase_Compensates verifies that an abandoned confirm is
+// compensated with its error, even once the context is cancelled.
+func TestRetryTwoPhase_Compensates(t *testing.T) {
+	rec := &twoPhaseRecorder{}
+	declined := errors.New("capture declined")
+	ctx, cancel := context.WithCancel(context.Background())
+	result := retrier.RetryTwoPhase(ctx, noopLogger, rec.prepare,
+		func(ctx context.Context, authID string) (string, error) {
+			cancel()
+			return "", declined
+		},
+		rec.compensate, nil, defaultTestOpts(),
+	)
+
+	if !errors.Is(result.Err(), declined) && !errors.Is(result.Err(), context.Canceled) {
+		t.Errorf("expected the confirm failure, got %v", result.Err())
+	}
+	if len(rec.compensated) != 1 || rec.compensated[0] != result.Err() {
+		t.Errorf("expected one compensation with the confirm error, got %v", rec.compensated)
+	}
+}
+
+// TestRetryTwoPhase_PrepareFails verifies that a failed prepare is returned
+// without confirming or compensating.
+func TestRetryTwoPhase_PrepareFails(t *testing.T) {
+	rec := &twoPhaseRecorder{}
+	result := retrier.RetryTwoPhase(context.Background(), noopLogger,
+		func(context.Context) (string, error) {
+			return "", retrier.Permanent(errors.New("card rejected"))
+		},
+		func(ctx context.Context, authID string) (string, error) {
+			rec.confirms++
+			return "", nil
+		},
+		rec.compensate, nil, nil,
+	)
+
+	if result.Err() == nil || result.Attempts() != 1 || rec.confirms != 0 || len(rec.compensated) != 0 {
+		t.Errorf("expected the prepare failure alone, got %v after %d attempts, %d confirms, %d compensations",
+			result.Err(), result.Attempts(), rec.confirms, len(rec.compensated))
+	}
+}
+
+// TestRetryTwoPhase_CompensationFails verifies that a failed compensation is
+// reported along with the confirm failure.
+func TestRetryTwoPhase_CompensationFails(t *testing.T) {
+	declined := errors.New("capture declined")
+	voidFailed := errors.New("void failed")
+	result := retrier.RetryTwoPhase(context.Background(), noopLogger,
+		func(context.Context) (string, error) { return "auth-1", nil },
+		func(ctx context.Context, authID string) (string, error) {
+			return "", retrier.Permanent(declined)
+		},
+		func(ctx context.Context, authID string, err error) error { return voidFailed },
+		nil, nil,
+	)
+
+	err := result.Err()
+	if !errors.Is(err, declined) || !errors.Is(err, retrier.ErrCompensationFailed) || !errors.Is(err, voidFailed) {
+		t.Errorf("expected the confirm and compensation failures, got %v", err)
+	}
+}
+
+// TestRetryTwoPhase_ConfirmPanics verifies that a panicking confirm is
+// compensated with ErrAttemptAborted.
+func TestRetryTwoPhase_ConfirmPanics(t *testing.T) {
+	rec := &twoPhaseRecorder{}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected the panic to propagate")
+		}
+		if len(rec.compensated) != 1 || rec.compensated[0] != retrier.ErrAttemptAborted {
+			t.Errorf("expected compensation with ErrAttemptAborted, got %v", rec.compensated)
+		}
+	}()
+	retrier.RetryTwoPhase(context.Background(), noopLogger, rec.prepare,
+		func(ctx context.Context, authID string) (string, error) { panic("boom") },
+		rec.compensate, nil, nil,
+	)
+}
@@ -0,0 +1,136 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// fakeTx records how a transaction ended.
+type fakeTx struct {
+	id         int
+	commitErr  error
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.committed = true
+	return tx.commitErr
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.rolledBack = true
+	return nil
+}
+
+// txRecorder begins fakeTx transactions and keeps them.
+type txRecorder struct {
+	txs       []*fakeTx
+	commitErr func(id int) error
+}
+
+func (r *txRecorder) begin(context.Context) (*fakeTx, error) {
+	tx := &fakeTx{id: len(r.txs) + 1}
+	if r.commitErr != nil {
+		tx.commitErr = r.commitErr(tx.id)
+	}
+	r.txs = append(r.txs, tx)
+	return tx, nil
+}
+
+// TestRetryTx_RollsBackFailedAttempts verifies that each attempt gets a fresh
+// transaction, failed ones are rolled back, and the successful one committed.
+func TestRetryTx_RollsBackFailedAttempts(t *testing.T) {
+	rec := &txRecorder{}
+	result := retrier.RetryTx(context.Background(), noopLogger, rec.begin,
+		func(ctx context.Context, tx *fakeTx) (int, error) {
+			if tx.id < 3 {
+				return 0, errors.New("deadlock detected")
+			}
+			return tx.id * 10, nil
+		},
+		defaultTestOpts()...,
+	)
+
+	if result.Value() != 30 || result.Attempts() != 3 {
+		t.Fatalf("expected 30 after 3 attempts, got %d after %d (%v)", result.Value(), result.Attempts(), result.Err())
+	}
+	for _, tx := range rec.txs[:2] {
+		if !tx.rolledBack || tx.committed {
+			t.Errorf("tx %d: expected a rollback only, got %+v", tx.id, tx)
+		}
+	}
+	if last := rec.txs[2]; !last.committed || last.rolledBack {
+		t.Errorf("tx 3: expected a commit only, got %+v", last)
+	}
+}
+
+// TestRetryTx_CommitFailure verifies that a failed commit is retried.
+func TestRetryTx_CommitFailure(t *testing.T) {
+	rec := &txRecorder{commitErr: func(id int) error {
+		if id == 1 {
+			return errors.New("could not serialize access")
+		}
+		return nil
+	}}
+	result := retrier.RetryTx(context.Background(), noopLogger, rec.begin,
+		func(ctx context.Context, tx *fakeTx) (string, error) { return "ok", nil },
+		defaultTestOpts()...,
+	)
+	if result.Value() != "ok" || len(rec.txs) != 2 {
+		t.Errorf("expected success on the second transaction, got %q after %d (%v)", result.Value(), len(rec.txs), result.Err())
+	}
+}
+
+// TestRetryTx_PermanentFailure verifies that a permanent failure is returned
+// without retrying.
+func TestRetryTx_PermanentFailure(t *testing.T) {
+	rec := &txRecorder{}
+	constraint := retrier.Permanent(errors.New("unique violation"))
+	result := retrier.RetryTx(context.Background(), noopLogger, rec.begin,
+		func(ctx context.Context, tx *fakeTx) (int, error) { return 0, constraint },
+		defaultTestOpts()...,
+	)
+	if result.Err() != constraint || len(rec.txs) != 1 || !rec.txs[0].rolledBack {
+		t.Errorf("expected the permanent error after one rolled back attempt, got %v after %d", result.Err(), len(rec.txs))
+	}
+}
+
+// TestRetryTx_BeginFailure verifies that begin failures are retried.
+func TestRetryTx_BeginFailure(t *testing.T) {
+	calls := 0
+	result := retrier.RetryTx(context.Background(), noopLogger,
+		func(ctx context.Context) (*fakeTx, error) {
+			calls++
+			if calls == 1 {
+				return nil, errors.New("connection refused")
+			}
+			return &fakeTx{}, nil
+		},
+		func(ctx context.Context, tx *fakeTx) (int, error) { return 1, nil },
+		defaultTestOpts()...,
+	)
+	if result.IsFailure() || calls != 2 {
+		t.Errorf("expected success on the second begin, got %v after %d", result.Err(), calls)
+	}
+}
+
+// TestRetryTx_Panic verifies that a panicking body rolls back its transaction.
+func TestRetryTx_Panic(t *testing.T) {
+	rec := &txRecorder{}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected the panic to propagate")
+		}
+		if len(rec.txs) != 1 || !rec.txs[0].rolledBack {
+			t.Errorf("expected the transaction to be rolled back, got %+v", rec.txs)
+		}
+	}()
+	retrier.RetryTx(context.Background(), noopLogger, rec.begin,
+		func(ctx context.Context, tx *fakeTx) (int, error) { panic("boom") },
+		defaultTestOpts()...,
+	)
+}
@@ -0,0 +1,75 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestWithSampledValidation_Retries verifies that a failed validation fails
+// the attempt, which is retried.
+func TestWithSampledValidation_Retries(t *testing.T) {
+	corrupt := errors.New("checksum mismatch")
+	var validated []int
+	result := retrier.RetryCtx(context.Background(), noopLogger, func(ctx context.Context) (int, error) {
+		return len(validated) + 1, nil
+	}, append(defaultTestOpts(), retrier.WithSampledValidation(1, func(ctx context.Context, n int) error {
+		validated = append(validated, n)
+		if n < 2 {
+			return corrupt
+		}
+		return nil
+	}))...)
+
+	if result.Err() != nil || result.Value() != 2 || result.Attempts() != 2 {
+		t.Fatalf("expected value 2 after 2 attempts, got %d after %d: %v", result.Value(), result.Attempts(), result.Err())
+	}
+	if history := result.History(); len(history) != 1 || !errors.Is(history[0].Err, corrupt) {
+		t.Errorf("expected the validation failure in the history, got %v", history)
+	}
+}
+
+// TestWithSampledValidation_LastAttempt verifies that a fraction of 0 skips
+// validation, except on the last attempt.
+func TestWithSampledValidation_LastAttempt(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		maxAttempts int
+		validated   int
+	}{
+		{"retries left", 3, 0},
+		{"last attempt", 1, 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			validated := 0
+			result := retrier.Retry(context.Background(), noopLogger, func() (string, error) {
+				return "blob", nil
+			}, retrier.WithMaxAttempts(tt.maxAttempts), retrier.WithSampledValidation(0, func(ctx context.Context, s string) error {
+				validated++
+				return nil
+			}))
+
+			if result.Err() != nil || validated != tt.validated {
+				t.Errorf("expected %d validations, got %d: %v", tt.validated, validated, result.Err())
+			}
+		})
+	}
+}
+
+// TestWithSampledValidation_Permanent verifies that a permanent validation
+// error stops the loop.
+func TestWithSampledValidation_Permanent(t *testing.T) {
+	calls := 0
+	result := retrier.Retry(context.Background(), noopLogger, func() (int, error) {
+		calls++
+		return 1, nil
+	}, append(defaultTestOpts(), retrier.WithSampledValidation(1, func(ctx context.Context, n int) error {
+		return retrier.Permanent(errors.New("schema violation"))
+	}))...)
+
+	if !retrier.IsPermanent(result.Err()) || calls != 1 {
+		t.Errorf("expected a permanent failure after 1 call, got %d: %v", calls, result.Err())
+	}
+}
@@ -0,0 +1,85 @@
+package retrier_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestWakeSignal_WakesSleepingLoops verifies that Wake ends the backoff delay
+// of every sleeping retry loop.
+func TestWakeSignal_WakesSleepingLoops(t *testing.T) {
+	signal := retrier.NewWakeSignal()
+	const loops = 3
+
+	var sleeping sync.WaitGroup
+	sleeping.Add(loops)
+	var done sync.WaitGroup
+	done.Add(loops)
+
+	start := time.Now()
+	for i := 0; i < loops; i++ {
+		go func() {
+			defer done.Done()
+			calls := 0
+			fn := func() (string, error) {
+				calls++
+				if calls == 1 {
+					sleeping.Done()
+					return "", errors.New("transient")
+				}
+				return "ok", nil
+			}
+			result := retrier.Retry(context.Background(), noopLogger, fn,
+				retrier.WithInitialDuration(time.Minute),
+				retrier.WithWakeSignal(signal),
+			)
+			if result.IsFailure() {
+				t.Errorf("expected success, got: %v", result.Err())
+			}
+		}()
+	}
+
+	sleeping.Wait()
+	// Give the loops time to enter their backoff delay
+	time.Sleep(20 * time.Millisecond)
+	signal.Wake()
+	done.Wait()
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected loops to wake early, took %v", elapsed)
+	}
+}
+
+// TestWakeSignal_WakeWithoutSleepers verifies that Wake is harmless when no
+// loop is sleeping and can be called repeatedly.
+func TestWakeSignal_WakeWithoutSleepers(t *testing.T) {
+	signal := retrier.NewWakeSignal()
+	signal.Wake()
+	signal.Wake()
+
+	calls := 0
+	fn := func() (string, error) {
+		calls++
+		if calls == 1 {
+			return "", errors.New("transient")
+		}
+		return "ok", nil
+	}
+	start := time.Now()
+	result := retrier.Retry(context.Background(), noopLogger, fn,
+		retrier.WithInitialDuration(30*time.Millisecond),
+		retrier.WithWakeSignal(signal),
+	)
+
+	if result.IsFailure() {
+		t.Fatalf("expected success, got: %v", result.Err())
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("earlier wakes should not shorten later delays, took %v", elapsed)
+	}
+}
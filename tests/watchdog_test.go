@@ -0,0 +1,82 @@
+package retrier_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retrier "github.com/rohmanhakim/retrier"
+)
+
+// TestWithWatchdog verifies that an attempt running past the threshold is
+// reported once, with stacks, without being cancelled, while fast attempts
+// are not reported.
+func TestWithWatchdog(t *testing.T) {
+	events := make(chan retrier.RetryEvent, 10)
+	var stuck []retrier.StuckAttempt
+	attempt := 0
+	result := retrier.RetryCtx(context.Background(), noopLogger, func(ctx context.Context) (int, error) {
+		attempt++
+		if attempt == 1 {
+			return 0, errors.New("fast failure")
+		}
+		time.Sleep(100 * time.Millisecond)
+		return attempt, ctx.Err()
+	}, retrier.WithMaxAttempts(2), retrier.WithInitialDuration(time.Millisecond),
+		retrier.WithWatchdog(20*time.Millisecond, func(ctx context.Context, s retrier.StuckAttempt) {
+			stuck = append(stuck, s)
+		}),
+		retrier.WithWatchdogStacks(),
+		retrier.WithNotifyChannel(events))
+	close(events)
+
+	if result.Err() != nil {
+		t.Fatalf("expected the stuck attempt to complete, got %v", result.Err())
+	}
+	if len(stuck) != 1 {
+		t.Fatalf("expected 1 stuck attempt, got %d", len(stuck))
+	}
+	if s := stuck[0]; s.Attempt != 2 || s.Running != 20*time.Millisecond || s.Started.IsZero() {
+		t.Errorf("unexpected StuckAttempt %+v", s)
+	}
+	if !bytes.Contains(stuck[0].Stacks, []byte("TestWithWatchdog")) {
+		t.Errorf("expected the stacks to show the attempt, got %q", stuck[0].Stacks)
+	}
+
+	var kinds []retrier.EventKind
+	for e := range events {
+		kinds = append(kinds, e.Kind)
+	}
+	want := []retrier.EventKind{retrier.EventRetry, retrier.EventAttemptStuck, retrier.EventSuccess}
+	if len(kinds) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, kinds)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("event %d: %v, want %v", i, kinds[i], want[i])
+		}
+	}
+}
+
+// TestWithWatchdog_Warning verifies that stuck attempts are logged as
+// warnings, without stacks unless requested.
+func TestWithWatchdog_Warning(t *testing.T) {
+	logger := &warningLogger{}
+	fired := false
+	var stacks []byte
+	retrier.Retry(context.Background(), logger, func() (int, error) {
+		time.Sleep(60 * time.Millisecond)
+		return 1, nil
+	}, retrier.WithWatchdog(10*time.Millisecond, func(ctx context.Context, s retrier.StuckAttempt) {
+		fired, stacks = true, s.Stacks
+	}))
+
+	if !fired || stacks != nil {
+		t.Errorf("expected the watchdog to fire without stacks, got %t and %d bytes", fired, len(stacks))
+	}
+	if len(logger.warnings) != 1 || logger.warnings[0] != "attempt stuck" {
+		t.Errorf("expected an attempt stuck warning, got %v", logger.warnings)
+	}
+}
@@ -0,0 +1,63 @@
+package retrier
+
+import (
+	"context"
+	"fmt"
+	"runtime/trace"
+)
+
+// WithRuntimeTrace instruments calls for Go execution traces (see
+// runtime/trace and go tool trace): each call runs as a task of type name,
+// each attempt as a region of type "retrier.attempt" and each backoff delay
+// as a region of type "retrier.backoff" within it. Failed attempts and the
+// outcome are logged to the task under the category "retrier", with errors
+// redacted like logged errors. fn receives the task in its context (see
+// RetryCtx), so its own tasks and regions nest under the call.
+//
+// Instrumentation costs next to nothing while no trace is being recorded.
+// Default is none.
+//
+// Example:
+//
+//	result := retrier.RetryCtx(ctx, logger, fn, retrier.WithRuntimeTrace("fetchProfile"))
+func WithRuntimeTrace(name string) RetryOption {
+	return func(c *retryConfig) {
+		c.traceName = name
+	}
+}
+
+// traceTask starts the task of a call run with ctx, if WithRuntimeTrace is
+// set, and returns the context carrying it with the function ending it.
+func (c *retryConfig) traceTask(ctx context.Context) (context.Context, func()) {
+	if c.traceName == "" {
+		return ctx, func() {}
+	}
+	ctx, task := trace.NewTask(ctx, c.traceName)
+	return ctx, task.End
+}
+
+// traceRegion starts a region of type regionType in the task of ctx, if
+// WithRuntimeTrace is set, and returns the function ending it.
+func (c *retryConfig) traceRegion(ctx context.Context, regionType string) func() {
+	if c.traceName == "" {
+		return func() {}
+	}
+	return trace.StartRegion(ctx, regionType).End
+}
+
+// traceOutcome logs the outcome of a call that returned err after attempts.
+func (c *retryConfig) traceOutcome(ctx context.Context, attempts int, err error) {
+	if err == nil {
+		c.traceLog(ctx, "succeeded after %d attempts", attempts)
+		return
+	}
+	c.traceLog(ctx, "failed after %d attempts: %v", attempts, c.redactError(err))
+}
+
+// traceLog logs a message to the task of ctx, if WithRuntimeTrace is set.
+func (c *retryConfig) traceLog(ctx context.Context, format string, args ...any) {
+	if c.traceName == "" || !trace.IsEnabled() {
+		return
+	}
+	trace.Log(ctx, "retrier", fmt.Sprintf(format, args...))
+}
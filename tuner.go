@@ -0,0 +1,94 @@
+package retrier
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// latencyWindow is the number of recent latencies a LatencyTuner keeps.
+	latencyWindow = 128
+
+	// latencyMinSamples is the number of latencies a LatencyTuner needs
+	// before it tunes the initial backoff.
+	latencyMinSamples = 10
+)
+
+// LatencyTuner adjusts the initial backoff to the latency of the dependency:
+// it observes the duration of successful attempts and sets the initial backoff
+// to a multiple of their recent p95, within bounds. Static initial durations
+// drift out of step as dependencies get faster or slower; a tuned one follows.
+//
+// Share a LatencyTuner between the calls to one dependency, typically by
+// passing WithLatencyTuner to a Retrier. Tuning starts once 10 latencies were
+// observed and follows the last 128. A LatencyTuner is safe for concurrent use.
+type LatencyTuner struct {
+	multiplier float64
+	min        time.Duration
+	max        time.Duration
+
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+// NewLatencyTuner creates a LatencyTuner setting the initial backoff to
+// multiplier times the p95 latency, clamped to [min, max].
+func NewLatencyTuner(multiplier float64, min, max time.Duration) *LatencyTuner {
+	return &LatencyTuner{multiplier: multiplier, min: min, max: max}
+}
+
+// Observe records the latency of a successful attempt. WithLatencyTuner calls
+// it; call it directly to feed latencies measured elsewhere.
+func (t *LatencyTuner) Observe(latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.samples) < latencyWindow {
+		t.samples = append(t.samples, latency)
+		return
+	}
+	t.samples[t.next] = latency
+	t.next = (t.next + 1) % latencyWindow
+}
+
+// P95 returns the 95th percentile of the recent latencies, and false if none
+// was observed.
+func (t *LatencyTuner) P95() (time.Duration, bool) {
+	t.mu.Lock()
+	sorted := slices.Clone(t.samples)
+	t.mu.Unlock()
+	if len(sorted) == 0 {
+		return 0, false
+	}
+	slices.Sort(sorted)
+	return sorted[(len(sorted)*95+99)/100-1], true
+}
+
+// InitialDuration returns the tuned initial backoff, and false until enough
+// latencies were observed.
+func (t *LatencyTuner) InitialDuration() (time.Duration, bool) {
+	t.mu.Lock()
+	n := len(t.samples)
+	t.mu.Unlock()
+	if n < latencyMinSamples {
+		return 0, false
+	}
+	p95, _ := t.P95()
+	d := time.Duration(float64(p95) * t.multiplier)
+	return min(max(d, t.min), t.max), true
+}
+
+// WithLatencyTuner feeds the latency of successful attempts to t and, once t
+// has enough of them, replaces the initial backoff with the tuned one,
+// whatever the order of options. Default is none.
+//
+// Example:
+//
+//	tuner := retrier.NewLatencyTuner(2, 50*time.Millisecond, 5*time.Second)
+//	r := retrier.NewRetrier(logger, retrier.WithLatencyTuner(tuner))
+func WithLatencyTuner(t *LatencyTuner) RetryOption {
+	return func(c *retryConfig) {
+		c.tuner = t
+	}
+}
@@ -0,0 +1,73 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrCompensationFailed is wrapped by the error of RetryTwoPhase when the
+// compensation of an abandoned operation failed too, leaving it prepared.
+var ErrCompensationFailed = errors.New("compensation failed")
+
+// RetryTwoPhase runs an operation made of a prepare step and a confirm step,
+// such as a payment authorization and its capture or a reservation and its
+// booking, retrying each step under its own options. Give the step that must
+// not be repeated WithMaxAttempts(1), typically prepare when it is not
+// idempotent, or confirm when it is not.
+//
+// Once prepare succeeded, the operation must be confirmed or undone: if
+// confirm gives up, for any reason including a cancelled context, compensate
+// is called once with the prepared value and the error of confirm, to void
+// the authorization or release the reservation. It is also called with
+// ErrAttemptAborted if confirm panics. compensate runs with a context that
+// is not cancelled with ctx, and is not called if prepare fails.
+//
+// The Result carries the value of confirm. Its error is that of the step that
+// failed; when compensate fails too, it also wraps ErrCompensationFailed and
+// the error of compensate. Attempts counts the attempts of both steps, and
+// History and Errors list their failed attempts, those of prepare first,
+// each numbered within its step.
+//
+// Example:
+//
+//	result := retrier.RetryTwoPhase(ctx, logger,
+//	    func(ctx context.Context) (string, error) {
+//	        return gateway.Authorize(ctx, order) // not idempotent: runs once
+//	    },
+//	    func(ctx context.Context, authID string) (Receipt, error) {
+//	        return gateway.Capture(ctx, authID)
+//	    },
+//	    func(ctx context.Context, authID string, err error) error {
+//	        return gateway.Void(ctx, authID)
+//	    },
+//	    []retrier.RetryOption{retrier.WithMaxAttempts(1)},
+//	    []retrier.RetryOption{retrier.WithMaxAttempts(5)},
+//	)
+func RetryTwoPhase[P, T any](ctx context.Context, logger DebugLogger, prepare func(ctx context.Context) (P, error), confirm func(ctx context.Context, prepared P) (T, error), compensate func(ctx context.Context, prepared P, err error) error, prepareOpts, confirmOpts []RetryOption) Result[T] {
+	prepared := RetryCtx(ctx, logger, prepare, prepareOpts...)
+	if prepared.err != nil {
+		return Result[T]{err: prepared.err, attempts: prepared.attempts, history: prepared.history}
+	}
+
+	returned := false
+	defer func() {
+		// confirm panicked or exited the goroutine; the panic keeps unwinding
+		if !returned {
+			_ = compensate(context.WithoutCancel(ctx), prepared.value, ErrAttemptAborted)
+		}
+	}()
+	result := RetryCtx(ctx, logger, func(ctx context.Context) (T, error) {
+		return confirm(ctx, prepared.value)
+	}, confirmOpts...)
+	returned = true
+
+	result.attempts += prepared.attempts
+	result.history = append(prepared.history, result.history...)
+	if result.err != nil {
+		if err := compensate(context.WithoutCancel(ctx), prepared.value, result.err); err != nil {
+			result.err = errors.Join(result.err, fmt.Errorf("%w: %w", ErrCompensationFailed, err))
+		}
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+package retrier
+
+import "context"
+
+// Tx is a transaction that RetryTx commits or rolls back. *sql.Tx implements it.
+type Tx interface {
+	Commit() error
+	Rollback() error
+}
+
+// RetryTx runs body in a transaction, retrying the whole transaction. Each
+// attempt begins a fresh transaction with begin, runs body in it, and commits
+// it if body succeeds or rolls it back if body fails or panics, like
+// RetryWithResource with the transaction as the resource. Failures of
+// begin, body, and Commit are classified like any error passed to Retry, so
+// only transient ones, such as serialization failures and deadlocks, are
+// retried; the others are returned right away. Rollback errors are ignored:
+// the attempt already failed, and a transaction that cannot be rolled back is
+// discarded by its database.
+//
+// begin and body receive the context of the attempt (see RetryCtx).
+//
+// Example:
+//
+//	result := retrier.RetryTx(ctx, logger,
+//	    func(ctx context.Context) (*sql.Tx, error) {
+//	        return db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+//	    },
+//	    func(ctx context.Context, tx *sql.Tx) (int64, error) {
+//	        return debit(ctx, tx, account, amount)
+//	    },
+//	    retrier.WithRetryIf(isSerializationFailure),
+//	)
+func RetryTx[T any, X Tx](ctx context.Context, logger DebugLogger, begin func(ctx context.Context) (X, error), body func(ctx context.Context, tx X) (T, error), opts ...RetryOption) Result[T] {
+	return RetryWithResource(ctx, logger, begin, endTx[X], body, opts...)
+}
+
+// endTx commits tx after a successful attempt and rolls it back otherwise.
+func endTx[X Tx](tx X, err error) error {
+	if err == nil {
+		return tx.Commit()
+	}
+	_ = tx.Rollback()
+	return nil
+}
@@ -0,0 +1,58 @@
+package retrier
+
+import (
+	"context"
+	"math/rand/v2"
+)
+
+// sampledValidation validates a fraction of successful results (see
+// WithSampledValidation).
+type sampledValidation struct {
+	fraction float64
+	validate func(ctx context.Context, result any) error
+}
+
+// check validates result with probability fraction, or always when final is
+// set, and returns the validation error.
+func (v *sampledValidation) check(ctx context.Context, final bool, result any) error {
+	if !final && rand.Float64() >= v.fraction {
+		return nil
+	}
+	return v.validate(ctx, result)
+}
+
+// WithSampledValidation runs validate, an expensive check of a successful
+// result such as a checksum over the written data or a read-back, on a
+// random fraction of successful attempts, and always on the last attempt
+// allowed by WithMaxAttempts, so no unverified result is returned once no
+// retry is left to correct it. When validate returns an error, the attempt
+// fails with it and is retried or returned like any other error; return a
+// Permanent error to stop retrying. validate runs with the attempt context.
+//
+// fraction is clamped to [0, 1]: 0 validates only the last attempt, 1 every
+// successful attempt. validate is only called with results of type T, so T
+// must match the type parameter of the retried function. It runs after the
+// check of WithRetryOnResult, if any. Default is none.
+//
+// Example:
+//
+//	result := retrier.RetryCtx(ctx, logger, writeBlob,
+//	    retrier.WithSampledValidation(0.05, func(ctx context.Context, ref BlobRef) error {
+//	        return verifyChecksum(ctx, ref) // reads the blob back
+//	    }),
+//	)
+func WithSampledValidation[T any](fraction float64, validate func(ctx context.Context, result T) error) RetryOption {
+	fraction = min(max(fraction, 0), 1)
+	return func(c *retryConfig) {
+		c.validation = &sampledValidation{
+			fraction: fraction,
+			validate: func(ctx context.Context, result any) error {
+				value, ok := result.(T)
+				if !ok && result != nil {
+					return nil
+				}
+				return validate(ctx, value)
+			},
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package retrier
+
+import "sync"
+
+// WakeSignal wakes up retry loops that are sleeping in a backoff delay, so they
+// retry immediately. Wire it to a component that knows when a dependency has
+// recovered (a health watcher, a push notification, ...) instead of waiting out
+// long backoffs after the dependency is already healthy.
+//
+// A WakeSignal is safe for concurrent use and can be shared by any number of
+// Retry calls. Waking only affects loops that are sleeping at that moment.
+type WakeSignal struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+// NewWakeSignal creates a WakeSignal.
+func NewWakeSignal() *WakeSignal {
+	return &WakeSignal{ch: make(chan struct{})}
+}
+
+// Wake interrupts the backoff delay of every retry loop currently sleeping on s.
+// Its signature makes it usable directly as a callback.
+func (s *WakeSignal) Wake() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	close(s.ch)
+	s.ch = make(chan struct{})
+}
+
+// wait returns a channel that is closed on the next Wake.
+func (s *WakeSignal) wait() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ch
+}
+
+// WithWakeSignal lets s cut backoff delays short.
+// A woken loop proceeds to its next attempt right away; attempts are still
+// counted against the maximum.
+func WithWakeSignal(s *WakeSignal) RetryOption {
+	return func(c *retryConfig) {
+		c.wake = s
+	}
+}
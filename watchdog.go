@@ -0,0 +1,116 @@
+package retrier
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// maxStackBytes bounds the goroutine stacks a watchdog captures.
+const maxStackBytes = 1 << 20
+
+// StuckAttempt describes an attempt that has been running longer than the
+// threshold of WithWatchdog.
+type StuckAttempt struct {
+	// Attempt is the number of the stuck attempt.
+	Attempt int
+
+	// Started is when the attempt started, according to the configured Clock.
+	Started time.Time
+
+	// Running is how long the attempt had been running when the watchdog
+	// fired: the threshold of WithWatchdog.
+	Running time.Duration
+
+	// Stacks holds the stacks of all goroutines when the watchdog fired, if
+	// WithWatchdogStacks is set, truncated at 1 MiB.
+	Stacks []byte
+}
+
+// WithWatchdog reports attempts that run longer than threshold, so hangs
+// inside fn show up before the deadline of the whole call expires. The
+// attempt is not cancelled: bound it with WithAttemptContext or
+// WithDeadlineSplit for that. When an attempt crosses threshold, an
+// EventAttemptStuck is published (see WithNotifyChannel), a warning is
+// logged if the logger implements WarningLogger, and onStuck, if not nil, is
+// called once for the attempt.
+//
+// The watchdog runs on its own goroutine, timed by the configured Clock; the
+// attempt returns only once a running onStuck did. Default is none.
+//
+// Example:
+//
+//	result := retrier.RetryCtx(ctx, logger, fn,
+//	    retrier.WithWatchdog(30*time.Second, func(ctx context.Context, s retrier.StuckAttempt) {
+//	        log.Printf("attempt %d stuck for %v\n%s", s.Attempt, s.Running, s.Stacks)
+//	    }),
+//	    retrier.WithWatchdogStacks(),
+//	)
+func WithWatchdog(threshold time.Duration, onStuck func(ctx context.Context, s StuckAttempt)) RetryOption {
+	return func(c *retryConfig) {
+		c.watchdog = threshold
+		c.onStuck = onStuck
+	}
+}
+
+// WithWatchdogStacks makes WithWatchdog capture the stacks of all goroutines
+// into StuckAttempt.Stacks, to show where the attempt hangs. Capturing stops
+// the world briefly. Default is off.
+func WithWatchdogStacks() RetryOption {
+	return func(c *retryConfig) {
+		c.watchdogStacks = true
+	}
+}
+
+// watch starts watching attempt, run with ctx, and returns the function
+// stopping the watch once the attempt returned. It does nothing without
+// WithWatchdog.
+func (c *retryConfig) watch(ctx context.Context, logger DebugLogger, attempt int) (stop func()) {
+	if c.watchdog <= 0 {
+		return func() {}
+	}
+	started := c.clock.Now()
+	timer := c.clock.NewTimer(c.watchdog)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-timer.C():
+			c.reportStuck(ctx, logger, StuckAttempt{Attempt: attempt, Started: started, Running: c.watchdog})
+		case <-done:
+			timer.Stop()
+		}
+	}()
+	return func() {
+		close(done)
+		<-exited
+	}
+}
+
+// reportStuck publishes, logs, and passes on s.
+func (c *retryConfig) reportStuck(ctx context.Context, logger DebugLogger, s StuckAttempt) {
+	if c.watchdogStacks {
+		s.Stacks = captureStacks()
+	}
+	c.publish(ctx, RetryEvent{Kind: EventAttemptStuck, Attempt: s.Attempt})
+	if warner, ok := logger.(WarningLogger); ok {
+		attrs := append([]any{"attempt", s.Attempt, "running", s.Running}, c.attrs...)
+		warner.LogWarning(c.logContext(ctx), "attempt stuck", attrs...)
+	}
+	if c.onStuck != nil {
+		c.onStuck(ctx, s)
+	}
+}
+
+// captureStacks returns the stacks of all goroutines, up to maxStackBytes.
+func captureStacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxStackBytes {
+			return buf[:n]
+		}
+		buf = make([]byte, min(2*len(buf), maxStackBytes))
+	}
+}